@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
@@ -29,6 +30,9 @@ type GEO struct {
 	// Base URLS for 2GIS
 	BaseURLCatalog string
 	BaseURLRouting string
+
+	// Maximum number of sources and targets in one distance matrix request
+	MatrixChunkSize int
 }
 
 // LOG is a struct for storing Logrus configatrion settings
@@ -48,6 +52,96 @@ type REDIS struct {
 	RedisDB       int
 }
 
+// DISPATCH is a struct for storing automatic dispatch settings
+type DISPATCH struct {
+	Enabled       bool
+	OfferTimeout  time.Duration
+	MaxCandidates int
+	SearchRadius  float64 // in m
+}
+
+// PROOF is a struct for storing proof of delivery settings
+type PROOF struct {
+	StorageDir     string  // directory of the local blob store
+	MaxFileSize    int64   // in bytes
+	GeoTolerance   float64 // in m
+	MaxPINAttempts int
+}
+
+// CHAT is a struct for storing delivery chat settings
+type CHAT struct {
+	CloseAfter time.Duration // since completion of the delivery
+}
+
+// NOTIFY is a struct for storing notification settings, channels
+// without provider settings are written to the local sink instead
+type NOTIFY struct {
+	// SMTP server of emails
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUser     string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// HTTP gateway of SMS
+	SMSURL    string
+	SMSAPIKey string
+
+	// FCM server key of push notifications
+	PushServerKey string
+
+	SinkDir      string // directory of the local sink
+	MaxAttempts  int
+	RetryDelay   time.Duration // before the first retry, doubled for each next one
+	PollInterval time.Duration // of the queue of notifications
+}
+
+// WEBHOOK is a struct for storing outbound webhooks settings
+type WEBHOOK struct {
+	MaxAttempts  int           // of each event's delivery
+	RetryDelay   time.Duration // before the first retry, doubled for each next one
+	DisableAfter int           // consecutive failed attempts of the endpoint
+	Timeout      time.Duration // of the request to the endpoint
+	PollInterval time.Duration // of the queue of events
+}
+
+// OUTBOX is a struct for storing settings of the domain events' outbox
+// and the bus they are relayed to
+type OUTBOX struct {
+	Bus          string        // memory or redis
+	Stream       string        // name of the redis stream
+	StreamMaxLen int           // approximate length the redis stream is trimmed to
+	BatchSize    int           // of events relayed at once
+	MaxAttempts  int           // of publishing each event before it's dead
+	RetryDelay   time.Duration // before the first retry, doubled for each next one
+	PollInterval time.Duration
+	Retention    time.Duration // of the published events
+}
+
+// AUTH is a struct for storing settings of users' sessions
+// and one-time tokens sent by email
+type AUTH struct {
+	AccessTTL  time.Duration // of the access token
+	RefreshTTL time.Duration // of the session since its last refresh
+	VerifyTTL  time.Duration // of the email verification token
+	ResetTTL   time.Duration // of the password reset token
+	AppURL     string        // of the client app, links in emails lead to it
+}
+
+// OTP is a struct for storing settings of one-time codes verifying phone numbers
+type OTP struct {
+	CodeTTL     time.Duration
+	Cooldown    time.Duration // between sending codes to the user
+	MaxAttempts int           // of entering each code
+	MaxSends    int           // of codes sent to the user per day
+}
+
+// APIKEY is a struct for storing limits of clients' api keys
+type APIKEY struct {
+	RateLimit    int // requests per minute of the personal key
+	OrgRateLimit int // requests per minute of the organization's key
+}
+
 // Config is a struct for storing all required configuration parameters
 type Config struct {
 	*PG
@@ -55,6 +149,15 @@ type Config struct {
 	*SERVICES
 	*LOG
 	*REDIS
+	*DISPATCH
+	*PROOF
+	*CHAT
+	*NOTIFY
+	*WEBHOOK
+	*OUTBOX
+	*AUTH
+	*OTP
+	*APIKEY
 }
 
 // New returns application config
@@ -104,6 +207,11 @@ func New() (*Config, error) {
 		return nil, errors.New("BASE_URL_ROUTING is not set")
 	}
 
+	matrixChunkSize, err := getEnvIntOrDefault("GEO_MATRIX_CHUNK_SIZE", 25)
+	if err != nil {
+		return nil, err
+	}
+
 	var mainPort int
 
 	port1 := os.Getenv("PORT")
@@ -144,6 +252,198 @@ func New() (*Config, error) {
 		return nil, err
 	}
 
+	// Automatic dispatch is optional and disabled by default
+	dispatchEnabled := os.Getenv("DISPATCH_ENABLED") == "true"
+
+	offerTimeout, err := getEnvIntOrDefault("DISPATCH_OFFER_TIMEOUT", 30)
+	if err != nil {
+		return nil, err
+	}
+
+	maxCandidates, err := getEnvIntOrDefault("DISPATCH_MAX_CANDIDATES", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	searchRadius, err := getEnvIntOrDefault("DISPATCH_SEARCH_RADIUS", 5000)
+	if err != nil {
+		return nil, err
+	}
+
+	// Evidence of delivery is stored locally unless another storage is set
+	storageDir := os.Getenv("PROOF_STORAGE_DIR")
+	if storageDir == "" {
+		storageDir = "./storage"
+	}
+
+	maxFileSize, err := getEnvIntOrDefault("PROOF_MAX_FILE_SIZE", 5<<20)
+	if err != nil {
+		return nil, err
+	}
+
+	geoTolerance, err := getEnvIntOrDefault("PROOF_GEO_TOLERANCE", 300)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPINAttempts, err := getEnvIntOrDefault("PROOF_MAX_PIN_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	chatCloseAfter, err := getEnvIntOrDefault("CHAT_CLOSE_AFTER", 24)
+	if err != nil {
+		return nil, err
+	}
+
+	smtpPort, err := getEnvIntOrDefault("SMTP_PORT", 587)
+	if err != nil {
+		return nil, err
+	}
+
+	notifySinkDir := os.Getenv("NOTIFY_SINK_DIR")
+	if notifySinkDir == "" {
+		notifySinkDir = "./storage/notifications"
+	}
+
+	notifyMaxAttempts, err := getEnvIntOrDefault("NOTIFY_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyRetryDelay, err := getEnvIntOrDefault("NOTIFY_RETRY_DELAY", 30)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyPollInterval, err := getEnvIntOrDefault("NOTIFY_POLL_INTERVAL", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookMaxAttempts, err := getEnvIntOrDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookRetryDelay, err := getEnvIntOrDefault("WEBHOOK_RETRY_DELAY", 30)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookDisableAfter, err := getEnvIntOrDefault("WEBHOOK_DISABLE_AFTER", 50)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookTimeout, err := getEnvIntOrDefault("WEBHOOK_TIMEOUT", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookPollInterval, err := getEnvIntOrDefault("WEBHOOK_POLL_INTERVAL", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxBus := os.Getenv("OUTBOX_BUS")
+	if outboxBus == "" {
+		outboxBus = "memory"
+	}
+	if outboxBus != "memory" && outboxBus != "redis" {
+		return nil, fmt.Errorf("unknown outbox bus %q", outboxBus)
+	}
+
+	outboxStream := os.Getenv("OUTBOX_STREAM")
+	if outboxStream == "" {
+		outboxStream = "truckly:events"
+	}
+
+	outboxStreamMaxLen, err := getEnvIntOrDefault("OUTBOX_STREAM_MAX_LEN", 100000)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxBatchSize, err := getEnvIntOrDefault("OUTBOX_BATCH_SIZE", 100)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxMaxAttempts, err := getEnvIntOrDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRetryDelay, err := getEnvIntOrDefault("OUTBOX_RETRY_DELAY", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxPollInterval, err := getEnvIntOrDefault("OUTBOX_POLL_INTERVAL", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRetention, err := getEnvIntOrDefault("OUTBOX_RETENTION_HOURS", 168)
+	if err != nil {
+		return nil, err
+	}
+
+	authAccessTTL, err := getEnvIntOrDefault("AUTH_ACCESS_TTL", 15)
+	if err != nil {
+		return nil, err
+	}
+
+	authRefreshTTL, err := getEnvIntOrDefault("AUTH_REFRESH_TTL_HOURS", 720)
+	if err != nil {
+		return nil, err
+	}
+
+	authVerifyTTL, err := getEnvIntOrDefault("AUTH_VERIFY_TTL_HOURS", 48)
+	if err != nil {
+		return nil, err
+	}
+
+	authResetTTL, err := getEnvIntOrDefault("AUTH_RESET_TTL", 60)
+	if err != nil {
+		return nil, err
+	}
+
+	otpCodeTTL, err := getEnvIntOrDefault("OTP_CODE_TTL", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	otpCooldown, err := getEnvIntOrDefault("OTP_COOLDOWN", 60)
+	if err != nil {
+		return nil, err
+	}
+
+	otpMaxAttempts, err := getEnvIntOrDefault("OTP_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	otpMaxSends, err := getEnvIntOrDefault("OTP_MAX_SENDS", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyRateLimit, err := getEnvIntOrDefault("APIKEY_RATE_LIMIT", 60)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyOrgRateLimit, err := getEnvIntOrDefault("APIKEY_ORG_RATE_LIMIT", 600)
+	if err != nil {
+		return nil, err
+	}
+
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
+
 	return &Config{
 		PG: &PG{
 			PostgresUser:     user,
@@ -157,6 +457,8 @@ func New() (*Config, error) {
 
 			BaseURLCatalog: baseURLCatalog,
 			BaseURLRouting: baseURLRouting,
+
+			MatrixChunkSize: matrixChunkSize,
 		},
 		SERVICES: &SERVICES{
 			Ports: map[string]int{
@@ -203,6 +505,69 @@ func New() (*Config, error) {
 			RedisPassword: redisPassword,
 			RedisDB:       db,
 		},
+		DISPATCH: &DISPATCH{
+			Enabled:       dispatchEnabled,
+			OfferTimeout:  time.Duration(offerTimeout) * time.Second,
+			MaxCandidates: maxCandidates,
+			SearchRadius:  float64(searchRadius),
+		},
+		PROOF: &PROOF{
+			StorageDir:     storageDir,
+			MaxFileSize:    int64(maxFileSize),
+			GeoTolerance:   float64(geoTolerance),
+			MaxPINAttempts: maxPINAttempts,
+		},
+		CHAT: &CHAT{
+			CloseAfter: time.Duration(chatCloseAfter) * time.Hour,
+		},
+		NOTIFY: &NOTIFY{
+			SMTPHost:      os.Getenv("SMTP_HOST"),
+			SMTPPort:      smtpPort,
+			SMTPUser:      os.Getenv("SMTP_USER"),
+			SMTPPassword:  os.Getenv("SMTP_PASSWORD"),
+			SMTPFrom:      os.Getenv("SMTP_FROM"),
+			SMSURL:        os.Getenv("SMS_URL"),
+			SMSAPIKey:     os.Getenv("SMS_API_KEY"),
+			PushServerKey: os.Getenv("PUSH_SERVER_KEY"),
+			SinkDir:       notifySinkDir,
+			MaxAttempts:   notifyMaxAttempts,
+			RetryDelay:    time.Duration(notifyRetryDelay) * time.Second,
+			PollInterval:  time.Duration(notifyPollInterval) * time.Second,
+		},
+		WEBHOOK: &WEBHOOK{
+			MaxAttempts:  webhookMaxAttempts,
+			RetryDelay:   time.Duration(webhookRetryDelay) * time.Second,
+			DisableAfter: webhookDisableAfter,
+			Timeout:      time.Duration(webhookTimeout) * time.Second,
+			PollInterval: time.Duration(webhookPollInterval) * time.Second,
+		},
+		OUTBOX: &OUTBOX{
+			Bus:          outboxBus,
+			Stream:       outboxStream,
+			StreamMaxLen: outboxStreamMaxLen,
+			BatchSize:    outboxBatchSize,
+			MaxAttempts:  outboxMaxAttempts,
+			RetryDelay:   time.Duration(outboxRetryDelay) * time.Second,
+			PollInterval: time.Duration(outboxPollInterval) * time.Second,
+			Retention:    time.Duration(outboxRetention) * time.Hour,
+		},
+		AUTH: &AUTH{
+			AccessTTL:  time.Duration(authAccessTTL) * time.Minute,
+			RefreshTTL: time.Duration(authRefreshTTL) * time.Hour,
+			VerifyTTL:  time.Duration(authVerifyTTL) * time.Hour,
+			ResetTTL:   time.Duration(authResetTTL) * time.Minute,
+			AppURL:     strings.TrimSuffix(appURL, "/"),
+		},
+		OTP: &OTP{
+			CodeTTL:     time.Duration(otpCodeTTL) * time.Minute,
+			Cooldown:    time.Duration(otpCooldown) * time.Second,
+			MaxAttempts: otpMaxAttempts,
+			MaxSends:    otpMaxSends,
+		},
+		APIKEY: &APIKEY{
+			RateLimit:    apiKeyRateLimit,
+			OrgRateLimit: apiKeyOrgRateLimit,
+		},
 	}, nil
 }
 
@@ -214,3 +579,17 @@ func convEnvToInt(env string) (int, error) {
 	}
 	return v, nil
 }
+
+// getEnvIntOrDefault converts optional environmental variable to int
+// and returns default value if variable is not set
+func getEnvIntOrDefault(key string, def int) (int, error) {
+	env, ok := os.LookupEnv(key)
+	if !ok || env == "" {
+		return def, nil
+	}
+	v, err := convEnvToInt(env)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a number", key)
+	}
+	return v, nil
+}
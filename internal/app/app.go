@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"github.com/dacore-x/truckly/config"
 	"github.com/dacore-x/truckly/pkg/logger"
@@ -13,9 +14,16 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/infrastructure/blobstore"
+	"github.com/dacore-x/truckly/internal/infrastructure/eventbus"
 	"github.com/dacore-x/truckly/internal/infrastructure/microservice"
+	"github.com/dacore-x/truckly/internal/infrastructure/notifier"
+	"github.com/dacore-x/truckly/internal/infrastructure/pubsub"
+	"github.com/dacore-x/truckly/internal/infrastructure/repository/cache"
 	"github.com/dacore-x/truckly/internal/infrastructure/repository/postgres"
 	"github.com/dacore-x/truckly/internal/infrastructure/webapi"
+	"github.com/dacore-x/truckly/internal/infrastructure/webhook"
 	v1 "github.com/dacore-x/truckly/internal/transport/http/v1"
 	"github.com/dacore-x/truckly/internal/usecase"
 )
@@ -43,8 +51,24 @@ func Run(cfg *config.Config) {
 	defer rdb.Close()
 
 	// Use cases
-	userUseCase := usecase.NewUserUseCase(
-		postgres.NewUserRepo(conn, appLogger),
+	userRepo := postgres.NewUserRepo(conn, appLogger)
+	userUseCase := usecase.NewUserUseCase(userRepo, appLogger)
+	sessionStore := cache.NewSessionStore(rdb, appLogger)
+	twoFactorRepo := postgres.NewTwoFactorRepo(conn, appLogger)
+	twoFactorStore := cache.NewTwoFactorStore(rdb, appLogger)
+	sessionUseCase := usecase.NewSessionUseCase(userRepo, sessionStore, twoFactorRepo, twoFactorStore, cfg.AUTH, appLogger)
+	roleRepo := postgres.NewRoleRepo(conn, appLogger)
+	twoFactorUseCase := usecase.NewTwoFactorUseCase(userRepo, roleRepo, twoFactorRepo, twoFactorStore, sessionStore, appLogger)
+	organizationRepo := postgres.NewOrganizationRepo(conn, appLogger)
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(
+		postgres.NewAPIKeyRepo(conn, appLogger),
+		organizationRepo,
+		cfg.APIKEY,
+		appLogger,
+	)
+	roleUseCase := usecase.NewRoleUseCase(
+		roleRepo,
+		cache.NewPermissionCache(rdb, appLogger),
 		appLogger,
 	)
 
@@ -53,18 +77,197 @@ func Run(cfg *config.Config) {
 		appLogger,
 	)
 
+	cityRepo := postgres.NewCityRepo(conn, appLogger)
+	cityUseCase := usecase.NewCityUseCase(cityRepo, appLogger)
+
+	zoneUseCase := usecase.NewZoneUseCase(
+		postgres.NewZoneRepo(conn, appLogger),
+		appLogger,
+	)
+
 	geoWebAPI := webapi.New(cfg.GEO, appLogger)
+
+	savedPlaceRepo := postgres.NewSavedPlaceRepo(conn, appLogger)
+	savedPlaceUseCase := usecase.NewSavedPlaceUseCase(savedPlaceRepo, geoWebAPI, appLogger)
 	priceEstimatorService := microservice.New(cfg.SERVICES, appLogger)
 
+	deliveryRepo := postgres.NewDeliveryRepo(conn, appLogger)
+	timelineRepo := postgres.NewTimelineRepo(conn, appLogger)
+
+	// Channels without provider settings are written to the local sink
+	notificationSink := notifier.NewFileSender(cfg.NOTIFY.SinkDir, appLogger)
+	notificationSenders := map[string]usecase.NotificationSender{
+		entity.ChannelEmail: notificationSink,
+		entity.ChannelSMS:   notificationSink,
+		entity.ChannelPush:  notificationSink,
+	}
+	if cfg.NOTIFY.SMTPHost != "" {
+		notificationSenders[entity.ChannelEmail] = notifier.NewSMTPSender(cfg.NOTIFY, appLogger)
+	}
+	if cfg.NOTIFY.SMSURL != "" {
+		notificationSenders[entity.ChannelSMS] = notifier.NewSMSSender(cfg.NOTIFY.SMSURL, cfg.NOTIFY.SMSAPIKey, appLogger)
+	}
+	if cfg.NOTIFY.PushServerKey != "" {
+		notificationSenders[entity.ChannelPush] = notifier.NewPushSender(cfg.NOTIFY.PushServerKey, appLogger)
+	}
+
+	notificationRepo := postgres.NewNotificationRepo(conn, appLogger)
+	accountRepo := postgres.NewAccountRepo(conn, appLogger)
+	accountUseCase := usecase.NewAccountUseCase(userRepo, accountRepo, sessionStore, notificationRepo, cfg.AUTH, appLogger)
+	organizationUseCase := usecase.NewOrganizationUseCase(organizationRepo, userRepo, geoWebAPI, notificationRepo, cfg.AUTH, appLogger)
+
+	// Codes are written to the local sink without SMS gateway
+	var smsSender usecase.SMSSender = notificationSink
+	if cfg.NOTIFY.SMSURL != "" {
+		smsSender = notifier.NewSMSSender(cfg.NOTIFY.SMSURL, cfg.NOTIFY.SMSAPIKey, appLogger)
+	}
+	phoneCodeStore := cache.NewPhoneCodeStore(rdb, appLogger)
+	phoneUseCase := usecase.NewPhoneUseCase(
+		userRepo,
+		accountRepo,
+		phoneCodeStore,
+		smsSender,
+		cfg.OTP,
+		appLogger,
+	)
+
+	notificationUseCase := usecase.NewNotificationUseCase(
+		notificationRepo,
+		notificationSenders,
+		map[string]usecase.NotificationComposer{
+			entity.NotifyEmailVerification: accountUseCase,
+			entity.NotifyPasswordReset:     accountUseCase,
+			entity.NotifyEmailChange:       accountUseCase,
+			entity.NotifyOrgInvitation:     organizationUseCase,
+		},
+		cfg.NOTIFY,
+		appLogger,
+	)
+	go notificationUseCase.Run(context.Background())
+
+	webhookUseCase := usecase.NewWebhookUseCase(
+		postgres.NewWebhookRepo(conn, appLogger),
+		webhook.NewClient(cfg.WEBHOOK.Timeout, appLogger),
+		cfg.WEBHOOK,
+		appLogger,
+	)
+	go webhookUseCase.Run(context.Background())
+
+	// Domain events written to the outbox are relayed to the bus
+	// and handled by each group of subscribers at least once
+	var eventBus usecase.EventBus = eventbus.NewMemoryBus(appLogger)
+	if cfg.OUTBOX.Bus == "redis" {
+		eventBus = eventbus.NewRedisStreamBus(rdb, cfg.OUTBOX.Stream, cfg.OUTBOX.StreamMaxLen, appLogger)
+	}
+	err = eventBus.Subscribe(context.Background(), "notifications", notificationUseCase.HandleEvent)
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+	err = eventBus.Subscribe(context.Background(), "webhooks", webhookUseCase.HandleEvent)
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+	err = eventBus.Subscribe(context.Background(), "sessions", sessionUseCase.HandleEvent)
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+	err = eventBus.Subscribe(context.Background(), "accounts", accountUseCase.HandleEvent)
+	if err != nil {
+		appLogger.Fatal(err)
+	}
+
+	outboxUseCase := usecase.NewOutboxUseCase(
+		postgres.NewOutboxRepo(conn, appLogger),
+		eventBus,
+		cfg.OUTBOX,
+		appLogger,
+	)
+	go outboxUseCase.Run(context.Background())
+
+	dispatchUseCase := usecase.NewDispatchUseCase(
+		postgres.NewDispatchRepo(conn, appLogger),
+		geoWebAPI,
+		timelineRepo,
+		notificationUseCase,
+		cfg.DISPATCH,
+		appLogger,
+	)
+
+	// Deliveries left in dispatch are released even if automatic dispatch is turned off
+	go dispatchUseCase.Run(context.Background())
+
+	// Automatic dispatch is optional, deliveries go to the open marketplace without it
+	var dispatcher usecase.DeliveryDispatcher
+	if cfg.DISPATCH.Enabled {
+		dispatcher = dispatchUseCase
+	}
+
 	deliveryUseCase := usecase.NewDeliveryUseCase(
-		postgres.NewDeliveryRepo(conn, appLogger),
+		deliveryRepo,
 		geoWebAPI,
 		priceEstimatorService,
+		dispatcher,
+		zoneUseCase,
+		cityRepo,
+		savedPlaceRepo,
+		organizationRepo,
+		timelineRepo,
 		appLogger,
 	)
 
-	geoUseCase := usecase.NewGeoUseCase(geoWebAPI, appLogger)
-	priceEstimatorUseCase := usecase.NewPriceEstimatorUseCase(priceEstimatorService, geoWebAPI, appLogger)
+	blobStore := blobstore.NewLocalStore(cfg.PROOF.StorageDir, appLogger)
+	proofUseCase := usecase.NewProofUseCase(
+		postgres.NewProofRepo(conn, appLogger),
+		deliveryRepo,
+		timelineRepo,
+		blobStore,
+		cfg.PROOF,
+		appLogger,
+	)
+
+	disputeUseCase := usecase.NewDisputeUseCase(
+		postgres.NewDisputeRepo(conn, appLogger),
+		deliveryRepo,
+		roleRepo,
+		timelineRepo,
+		blobStore,
+		cfg.PROOF,
+		appLogger,
+	)
+
+	profileUseCase := usecase.NewProfileUseCase(
+		userRepo,
+		postgres.NewProfileRepo(conn, appLogger),
+		accountRepo,
+		cityRepo,
+		phoneCodeStore,
+		blobStore,
+		notificationRepo,
+		cfg.AUTH,
+		appLogger,
+	)
+
+	reviewUseCase := usecase.NewReviewUseCase(
+		postgres.NewReviewRepo(conn, appLogger),
+		deliveryRepo,
+		timelineRepo,
+		appLogger,
+	)
+
+	chatUseCase := usecase.NewChatUseCase(
+		postgres.NewChatRepo(conn, appLogger),
+		pubsub.NewChatBroker(rdb, appLogger),
+		cfg.CHAT,
+		appLogger,
+	)
+
+	geoUseCase := usecase.NewGeoUseCase(
+		geoWebAPI,
+		cityRepo,
+		cache.NewSuggestCache(rdb, appLogger),
+		appLogger,
+	)
+	priceEstimatorUseCase := usecase.NewPriceEstimatorUseCase(priceEstimatorService, geoWebAPI, zoneUseCase, cityRepo, savedPlaceRepo, appLogger)
 
 	// Create HTTP server using Gin
 	gin.SetMode(gin.ReleaseMode)
@@ -82,6 +285,24 @@ func Run(cfg *config.Config) {
 		metricsUseCase,
 		geoUseCase,
 		priceEstimatorUseCase,
+		dispatchUseCase,
+		zoneUseCase,
+		cityUseCase,
+		savedPlaceUseCase,
+		proofUseCase,
+		reviewUseCase,
+		chatUseCase,
+		disputeUseCase,
+		notificationUseCase,
+		webhookUseCase,
+		sessionUseCase,
+		apiKeyUseCase,
+		accountUseCase,
+		phoneUseCase,
+		twoFactorUseCase,
+		roleUseCase,
+		organizationUseCase,
+		profileUseCase,
 		appLogger,
 		rdb,
 	)
@@ -0,0 +1,45 @@
+package dto
+
+// EmailVerifyRequestBody represents the request body with token
+// from the email verification link
+type EmailVerifyRequestBody struct {
+	Token string `json:"token" binding:"required,hexadecimal,len=64"`
+}
+
+// EmailChangeRequestBody represents the request body with token
+// from the link confirming the user's new email
+type EmailChangeRequestBody struct {
+	Token string `json:"token" binding:"required,hexadecimal,len=64"`
+}
+
+// PasswordForgotRequestBody represents the request body with email
+// of the account whose password is forgotten
+type PasswordForgotRequestBody struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PasswordResetRequestBody represents the request body with token
+// from the password reset link and new password
+type PasswordResetRequestBody struct {
+	Token    string `json:"token" binding:"required,hexadecimal,len=64"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// PasswordChangeRequestBody represents the request body with current
+// and new passwords of the logged in user
+type PasswordChangeRequestBody struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// PhoneVerifyRequestBody represents the request body with
+// one-time code sent to the user's phone number
+type PhoneVerifyRequestBody struct {
+	Code string `json:"code" binding:"required,numeric,len=6"`
+}
+
+// TwoFactorCodeRequestBody represents the request body with TOTP
+// code or recovery code of the user's second factor
+type TwoFactorCodeRequestBody struct {
+	Code string `json:"code" binding:"required,max=20"`
+}
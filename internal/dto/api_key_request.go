@@ -0,0 +1,16 @@
+package dto
+
+// APIKeyRequestBody represents the request body with name
+// and scopes of the client's api key
+type APIKeyRequestBody struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=deliveries:create deliveries:read price:estimate"`
+
+	// Key of the organization creates deliveries billed to it
+	OrganizationID int `json:"organization_id" binding:"omitempty,min=1"`
+}
+
+// APIKeyIdURI represents URI with api key's ID
+type APIKeyIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+// ChatMessageRequestBody represents the request body with message
+// sent by the participant of the delivery to the chat
+type ChatMessageRequestBody struct {
+	Text string `json:"text" binding:"required,max=2000"`
+}
+
+// ChatReadRequestBody represents the request body with id of the last
+// message read by the participant, all earlier messages are read too
+type ChatReadRequestBody struct {
+	LastMessageID int `json:"last_message_id" binding:"required,min=1"`
+}
+
+// ChatHistoryQuery represents query of the chat history page,
+// messages older than BeforeID are returned starting from the newest one
+type ChatHistoryQuery struct {
+	BeforeID int `form:"before_id" binding:"omitempty,min=1"`
+	Limit    int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
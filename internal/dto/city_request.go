@@ -0,0 +1,18 @@
+package dto
+
+// CityRequestBody represents the request body with data
+// sent by the admin to API to create or update city
+type CityRequestBody struct {
+	Name             string  `json:"name" binding:"required"`
+	GeocoderID       string  `json:"geocoder_id" binding:"required"`
+	Timezone         string  `json:"timezone" binding:"required"`
+	Currency         string  `json:"currency" binding:"required,len=3,uppercase"`
+	TariffMultiplier float64 `json:"tariff_multiplier" binding:"required,gt=0"`
+	EnabledTypes     []int64 `json:"enabled_types" binding:"required,min=1,dive,gte=1,lte=5"`
+	IsActive         bool    `json:"is_active"`
+}
+
+// CityIdURI represents URI with city's ID
+type CityIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
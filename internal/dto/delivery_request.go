@@ -1,12 +1,56 @@
 package dto
 
+import "time"
+
 // DeliveryCreateBody represents the request body with data
 // sent by the user to API to create new delivery order
 type DeliveryCreateBody struct {
 	TypeID    int           `json:"type_id" binding:"required,gte=1,lte=5"`
-	FromPoint *PointRequest `json:"from_point" binding:"required"`
-	ToPoint   *PointRequest `json:"to_point" binding:"required"`
+	FromPoint *PointRequest `json:"from_point" binding:"required_without_all=FromPlaceID FromSavedPlaceID"`
+	ToPoint   *PointRequest `json:"to_point" binding:"required_without_all=ToPlaceID ToSavedPlaceID"`
 	HasLoader bool          `json:"has_loader"`
+
+	// Ids of the suggestions chosen by the user, they take precedence over points
+	FromPlaceID string `json:"from_place_id"`
+	ToPlaceID   string `json:"to_place_id"`
+
+	// Ids of the places from the user's address book, they take precedence over suggestions
+	FromSavedPlaceID int `json:"from_saved_place_id" binding:"omitempty,min=1"`
+	ToSavedPlaceID   int `json:"to_saved_place_id" binding:"omitempty,min=1"`
+
+	// Cargo parameters are optional and used to check vehicle capacity
+	CargoWeight float64 `json:"cargo_weight" binding:"gte=0"` // in kg
+	CargoVolume float64 `json:"cargo_volume" binding:"gte=0"` // in m3
+
+	// Time windows are optional and used to plan courier's route
+	PickupWindow  *TimeWindowRequest `json:"pickup_window"`
+	DropoffWindow *TimeWindowRequest `json:"dropoff_window"`
+
+	// Contacts are optional, instructions of saved places are used if they are not set
+	PickupContact  *StopContactRequest `json:"pickup_contact"`
+	DropoffContact *StopContactRequest `json:"dropoff_contact"`
+
+	// Organization the delivery is created on behalf of, saved places are
+	// taken from its address book then
+	OrganizationID int `json:"organization_id" binding:"omitempty,min=1"`
+}
+
+// StopContactRequest represents contact person and access
+// instructions of pickup or dropoff point
+type StopContactRequest struct {
+	Name        string `json:"name" binding:"max=100"`
+	PhoneNumber string `json:"phone_number" binding:"max=20"`
+	Entrance    string `json:"entrance" binding:"max=20"`
+	Floor       string `json:"floor" binding:"max=20"`
+	Intercom    string `json:"intercom" binding:"max=20"`
+	Notes       string `json:"notes" binding:"max=500"`
+}
+
+// TimeWindowRequest represents period of time
+// when the courier should visit pickup or dropoff point
+type TimeWindowRequest struct {
+	From time.Time `json:"from" binding:"required"`
+	To   time.Time `json:"to" binding:"required,gtfield=From"`
 }
 
 // DeliveryIdURI represents URI with delivery's ID to get info
@@ -21,8 +65,12 @@ type DeliveryStatusChangeBody struct {
 	StatusID int `json:"status_id" binding:"required,gte=1,lte=4"`
 }
 
+// DeliveryListGeolocationQuery represents query of the courier's search
+// of new deliveries with pickup point within radius (in m) of the location
 type DeliveryListGeolocationQuery struct {
-	Latitude  float64 `form:"lat" binding:"required"`
-	Longitude float64 `form:"lon" binding:"required"`
+	Latitude  float64 `form:"lat" binding:"required,gte=-90,lte=90"`
+	Longitude float64 `form:"lon" binding:"required,gte=-180,lte=180"`
+	Radius    float64 `form:"radius" binding:"omitempty,gt=0,lte=50000"`
 	Page      int     `form:"page" binding:"required,min=1"`
+	CityID    int     `form:"-"` // courier's city, search is limited to it
 }
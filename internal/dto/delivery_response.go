@@ -3,16 +3,40 @@ package dto
 import "time"
 
 type DeliveryFullInfoResponse struct {
-	ID         int                 `json:"id"`
-	TypeID     int                 `json:"type_id"`
-	Courier    DeliveryCourierInfo `json:"courier"`
-	StatusID   int                 `json:"status_id"`
-	Price      float64             `json:"price"`
-	HasLoader  bool                `json:"has_loader"`
-	FromObject GeoObjectResponse   `json:"from_object"`
-	ToObject   GeoObjectResponse   `json:"to_object"`
-	Distance   int                 `json:"distance"`
-	Time       time.Time           `json:"time"`
+	ID          int                 `json:"id"`
+	ClientID    int                 `json:"-"`
+	CourierID   int                 `json:"-"`
+	TypeID      int                 `json:"type_id"`
+	Courier     DeliveryCourierInfo `json:"courier"`
+	StatusID    int                 `json:"status_id"`
+	Price       float64             `json:"price"`
+	HasLoader   bool                `json:"has_loader"`
+	CargoWeight float64             `json:"cargo_weight"`
+	CargoVolume float64             `json:"cargo_volume"`
+	FromObject  GeoObjectResponse   `json:"from_object"`
+	ToObject    GeoObjectResponse   `json:"to_object"`
+	Distance    int                 `json:"distance"`
+	Time        time.Time           `json:"time"`
+
+	// Contacts are shown to the client and to the courier while performing
+	// the delivery, others get them masked
+	PickupContact  *StopContactResponse `json:"pickup_contact"`
+	DropoffContact *StopContactResponse `json:"dropoff_contact"`
+
+	// PIN is shown only to the client to pass it to the recipient
+	PIN string `json:"pin,omitempty"`
+}
+
+// StopContactResponse represents contact person and access
+// instructions of pickup or dropoff point
+type StopContactResponse struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Entrance    string `json:"entrance"`
+	Floor       string `json:"floor"`
+	Intercom    string `json:"intercom"`
+	Notes       string `json:"notes"`
+	IsMasked    bool   `json:"is_masked"`
 }
 
 type DeliveryBriefResponse struct {
@@ -25,6 +49,10 @@ type DeliveryBriefResponse struct {
 	ToObject   string    `json:"to_object"`
 	Distance   int       `json:"distance"`
 	Time       time.Time `json:"time"`
+
+	// Great-circle distance from the search location to pickup in m,
+	// set only in the results of the search by geolocation
+	PickupDistance float64 `json:"pickup_distance,omitempty"`
 }
 
 type GeoObjectResponse struct {
@@ -39,3 +67,24 @@ type DeliveryCourierInfo struct {
 	Rating      float64   `json:"rating"`
 	CreatedAt   time.Time `json:"created_at"`
 }
+
+// RouteResponse represents the response body with
+// courier's pickups and dropoffs in order of visiting
+type RouteResponse struct {
+	Stops    []*RouteStopResponse `json:"stops"`
+	Distance float64              `json:"distance"` // in m
+	Duration float64              `json:"duration"` // in s
+}
+
+// RouteStopResponse represents pickup or dropoff point of courier's route
+type RouteStopResponse struct {
+	DeliveryID int        `json:"delivery_id"`
+	Type       string     `json:"type"`
+	Object     string     `json:"object"`
+	Latitude   float64    `json:"latitude"`
+	Longitude  float64    `json:"longitude"`
+	WindowFrom *time.Time `json:"window_from,omitempty"`
+	WindowTo   *time.Time `json:"window_to,omitempty"`
+	ETA        time.Time  `json:"eta"`
+	IsLate     bool       `json:"is_late"`
+}
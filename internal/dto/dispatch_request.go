@@ -0,0 +1,16 @@
+package dto
+
+// CourierPresenceRequestBody represents the request body with data
+// sent by the courier to API to go online/offline and share location
+type CourierPresenceRequestBody struct {
+	IsOnline  bool    `json:"is_online"`
+	TypeID    int     `json:"type_id" binding:"required,gte=1,lte=5"`
+	Latitude  float64 `json:"lat" binding:"required"`
+	Longitude float64 `json:"lon" binding:"required"`
+}
+
+// OfferIdURI represents URI with offer's ID to respond
+// to specific dispatch offer
+type OfferIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
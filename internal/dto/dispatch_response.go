@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// OfferResponse represents the response body
+// with pending dispatch offer sent to the courier
+type OfferResponse struct {
+	ID         int       `json:"id"`
+	DeliveryID int       `json:"delivery_id"`
+	TypeID     int       `json:"type_id"`
+	HasLoader  bool      `json:"has_loader"`
+	Price      float64   `json:"price"`
+	FromObject string    `json:"from_object"`
+	ToObject   string    `json:"to_object"`
+	Distance   float64   `json:"distance"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
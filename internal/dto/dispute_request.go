@@ -0,0 +1,48 @@
+package dto
+
+// DisputeRequestBody represents the request body sent by
+// the participant of the delivery to report a problem with it
+type DisputeRequestBody struct {
+	Category    string `json:"category" binding:"required,oneof=damaged lost no_show late other"`
+	Description string `json:"description" binding:"required,max=2000"`
+}
+
+// DisputeIdURI represents URI with dispute's ID
+type DisputeIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+// DisputeAttachmentURI represents URI with dispute's and its attachment's IDs
+type DisputeAttachmentURI struct {
+	ID           int `uri:"id" binding:"required,min=1"`
+	AttachmentID int `uri:"attachment_id" binding:"required,min=1"`
+}
+
+// DisputeListQuery represents query of the admins' queue of disputes,
+// status and assignee filters are optional
+type DisputeListQuery struct {
+	Status     string `form:"status" binding:"omitempty,oneof=open in_progress resolved rejected"`
+	AssigneeID int    `form:"assignee_id" binding:"omitempty,min=1"`
+	Page       int    `form:"page" binding:"required,min=1"`
+}
+
+// DisputeAssignBody represents the request body to assign the dispute to the admin
+type DisputeAssignBody struct {
+	AssigneeID int `json:"assignee_id" binding:"required,min=1"`
+}
+
+// DisputeStatusBody represents the request body to change status of unresolved dispute
+type DisputeStatusBody struct {
+	Status string `json:"status" binding:"required,oneof=open in_progress"`
+}
+
+// DisputeResolveBody represents the request body with admin's decision on the dispute,
+// refund goes to the client, penalty and ban apply to the penalized participant
+type DisputeResolveBody struct {
+	Status          string  `json:"status" binding:"required,oneof=resolved rejected"`
+	Resolution      string  `json:"resolution" binding:"required,max=2000"`
+	RefundAmount    float64 `json:"refund_amount" binding:"gte=0"`
+	PenaltyAmount   float64 `json:"penalty_amount" binding:"gte=0"`
+	PenalizedUserID int     `json:"penalized_user_id" binding:"omitempty,min=1"`
+	BanUser         bool    `json:"ban_user"`
+}
@@ -13,3 +13,12 @@ type PointRequest struct {
 	Lon float64 `json:"lon"`
 	Lat float64 `json:"lat"`
 }
+
+// SuggestQuery represents query of the address autocomplete,
+// suggestions are biased to the location if it is set
+type SuggestQuery struct {
+	Q     string   `form:"q" binding:"required,min=2,max=200"`
+	Lat   *float64 `form:"lat" binding:"required_with=Lon,omitempty,gte=-90,lte=90"`
+	Lon   *float64 `form:"lon" binding:"required_with=Lat,omitempty,gte=-180,lte=180"`
+	Limit int      `form:"limit" binding:"omitempty,min=1,max=20"`
+}
@@ -18,11 +18,25 @@ type Meta struct {
 
 // Item is a struct for JSON decoding "Item" field
 type Item struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
 	Address  string        `json:"address_name"`
 	FullName string        `json:"full_name"`
 	Point    PointResponse `json:"point"`
 }
 
+// SuggestionResponse represents the response body
+// with geo object suggested by the user's input
+type SuggestionResponse struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	FullAddress string        `json:"full_address"`
+	Type        string        `json:"type"`
+	Point       PointResponse `json:"point"`
+	Distance    float64       `json:"distance,omitempty"` // from user's location in m
+}
+
 // PointResponse is a struct of point Geo position with latitude and longitude
 type PointResponse struct {
 	Lon float64 `json:"lon"`
@@ -32,6 +46,17 @@ type PointResponse struct {
 // DistanceResponse is a struct for JSON decoding response
 type DistanceResponse struct {
 	Routes []struct {
+		SourceID int     `json:"source_id"`
+		TargetID int     `json:"target_id"`
 		Distance float64 `json:"distance"`
+		Duration float64 `json:"duration"`
+		Status   string  `json:"status"`
 	} `json:"routes"`
 }
+
+// RouteMatrix is a struct of distances (in m) and durations (in s)
+// from every source point (row) to every target point (column)
+type RouteMatrix struct {
+	Distances [][]float64
+	Durations [][]float64
+}
@@ -0,0 +1,7 @@
+package dto
+
+// MetricsQuery represents query of the admin's dashboard
+// filtering metrics by city, all cities are used if it is not set
+type MetricsQuery struct {
+	CityID int `form:"city_id" binding:"omitempty,min=1"`
+}
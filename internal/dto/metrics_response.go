@@ -37,6 +37,17 @@ type DeliveryTypesPercentPerDay struct {
 	LongTruckPercent float64 `json:"long_truck_percent"`
 }
 
+// ZoneMetricsPerDay represents the response body
+// with new and completed deliveries' counts and revenue
+// of the service zone per last 24 hours
+type ZoneMetricsPerDay struct {
+	ZoneID       int    `json:"zone_id"`
+	Name         string `json:"name"`
+	NewCnt       int    `json:"new_cnt"`
+	CompletedCnt int    `json:"completed_cnt"`
+	Revenue      int    `json:"revenue"`
+}
+
 // MetricsPerDayResponse represents the response body
 // with all metrics per last 24 hours
 type MetricsPerDayResponse struct {
@@ -44,6 +55,7 @@ type MetricsPerDayResponse struct {
 	Revenue              *RevenuePerDay              `json:"revenue"`
 	NewClientsCnt        *NewClientsCntPerDay        `json:"new_clients_cnt"`
 	DeliveryTypesPercent *DeliveryTypesPercentPerDay `json:"delivery_types_percent"`
+	Zones                []*ZoneMetricsPerDay        `json:"zones"`
 }
 
 // CurrentDelivery represents the response body
@@ -0,0 +1,10 @@
+package dto
+
+// NotificationPreferencesBody represents the request body with
+// channels the user wants to be notified via
+type NotificationPreferencesBody struct {
+	EmailEnabled *bool  `json:"email_enabled" binding:"required"`
+	SMSEnabled   *bool  `json:"sms_enabled" binding:"required"`
+	PushEnabled  *bool  `json:"push_enabled" binding:"required"`
+	PushToken    string `json:"push_token" binding:"max=4096"`
+}
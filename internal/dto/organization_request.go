@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// OrganizationRequestBody represents the request body with name
+// and billing details of the organization
+type OrganizationRequestBody struct {
+	Name           string `json:"name" binding:"required,max=200"`
+	LegalName      string `json:"legal_name" binding:"max=300"`
+	TaxID          string `json:"tax_id" binding:"omitempty,numeric,min=10,max=12"`
+	BillingEmail   string `json:"billing_email" binding:"omitempty,email"`
+	BillingAddress string `json:"billing_address" binding:"max=500"`
+}
+
+// OrganizationIdURI represents URI with organization's ID
+type OrganizationIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+// OrganizationMemberURI represents URI with organization's and member's IDs
+type OrganizationMemberURI struct {
+	ID     int `uri:"id" binding:"required,min=1"`
+	UserID int `uri:"user_id" binding:"required,min=1"`
+}
+
+// OrganizationPlaceURI represents URI with organization's and saved place's IDs
+type OrganizationPlaceURI struct {
+	ID      int `uri:"id" binding:"required,min=1"`
+	PlaceID int `uri:"place_id" binding:"required,min=1"`
+}
+
+// OrganizationInviteRequestBody represents the request body with email
+// of the invited person and their role in the organization
+type OrganizationInviteRequestBody struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=owner manager requester"`
+}
+
+// OrganizationAcceptRequestBody represents the request body with token
+// from the invitation link
+type OrganizationAcceptRequestBody struct {
+	Token string `json:"token" binding:"required,hexadecimal,len=64"`
+}
+
+// OrganizationMemberRoleRequestBody represents the request body with new role of the member
+type OrganizationMemberRoleRequestBody struct {
+	Role string `json:"role" binding:"required,oneof=owner manager requester"`
+}
+
+// OrganizationDeliveriesQuery represents query of the organization's deliveries list
+type OrganizationDeliveriesQuery struct {
+	Page int `form:"page" binding:"required,min=1"`
+}
+
+// OrganizationSpendingQuery represents query of the organization's spending report,
+// the last 30 days are used if the period is not set
+type OrganizationSpendingQuery struct {
+	From time.Time `form:"from" time_format:"2006-01-02"`
+	To   time.Time `form:"to" time_format:"2006-01-02"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// OrganizationSpendingResponse represents spending of the organization
+// on deliveries within the period, cancelled deliveries are not counted
+type OrganizationSpendingResponse struct {
+	From       time.Time                 `json:"from"`
+	To         time.Time                 `json:"to"`
+	Deliveries int                       `json:"deliveries"`
+	Total      float64                   `json:"total"`
+	Members    []*MemberSpendingResponse `json:"members"`
+}
+
+// MemberSpendingResponse represents spending on deliveries requested by the member
+type MemberSpendingResponse struct {
+	UserID     int     `json:"user_id"`
+	Surname    string  `json:"surname"`
+	Name       string  `json:"name"`
+	Deliveries int     `json:"deliveries"`
+	Total      float64 `json:"total"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// SavedPlaceRequestBody represents the request body with data
+// sent by the user to API to save or update address
+type SavedPlaceRequestBody struct {
+	Label string        `json:"label" binding:"required,max=100"`
+	Point *PointRequest `json:"point" binding:"required"`
+
+	// Object is resolved by the point if it is not set
+	Object   string `json:"object" binding:"max=300"`
+	Entrance string `json:"entrance" binding:"max=20"`
+	Floor    string `json:"floor" binding:"max=20"`
+	Notes    string `json:"notes" binding:"max=500"`
+}
+
+// SavedPlaceIdURI represents URI with saved place's ID
+type SavedPlaceIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
@@ -15,7 +15,15 @@ type EstimatePriceInternalRequestBody struct {
 // sent by the user to API to estimate delivery price
 type EstimatePriceRequestBody struct {
 	TypeID    int           `json:"type_id" binding:"required,gte=1,lte=5"`
-	FromPoint *PointRequest `json:"from_point" binding:"required"`
-	ToPoint   *PointRequest `json:"to_point" binding:"required"`
+	FromPoint *PointRequest `json:"from_point" binding:"required_without=FromSavedPlaceID"`
+	ToPoint   *PointRequest `json:"to_point" binding:"required_without=ToSavedPlaceID"`
 	HasLoader bool          `json:"has_loader"`
+
+	// Ids of the places from the user's address book, they take precedence over points
+	FromSavedPlaceID int `json:"from_saved_place_id" binding:"omitempty,min=1"`
+	ToSavedPlaceID   int `json:"to_saved_place_id" binding:"omitempty,min=1"`
+	UserID           int `json:"-"`
+
+	// City of the delivery, user's city is used if it is not set
+	CityID int `json:"city_id" binding:"omitempty,min=1"`
 }
@@ -2,5 +2,6 @@ package dto
 
 // EstimatePriceResponse represents body struct for decoding response
 type EstimatePriceResponse struct {
-	Price float64 `json:"price"`
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency,omitempty"`
 }
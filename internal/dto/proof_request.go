@@ -0,0 +1,14 @@
+package dto
+
+// ProofCompleteBody represents the request body sent by the courier
+// to complete the delivery with recipient's PIN at the dropoff point
+type ProofCompleteBody struct {
+	PIN   string        `json:"pin" binding:"required,len=4,numeric"`
+	Point *PointRequest `json:"point" binding:"required"`
+}
+
+// ProofFileURI represents URI with delivery's ID and kind of the proof file
+type ProofFileURI struct {
+	ID   int    `uri:"id" binding:"required,min=1"`
+	Kind string `uri:"kind" binding:"required,oneof=photo signature"`
+}
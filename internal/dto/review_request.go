@@ -0,0 +1,25 @@
+package dto
+
+// ReviewRequestBody represents the request body with data
+// sent by the participant of completed delivery to rate the other one
+type ReviewRequestBody struct {
+	Score   int      `json:"score" binding:"required,min=1,max=5"`
+	Comment string   `json:"comment" binding:"max=1000"`
+	Tags    []string `json:"tags" binding:"max=5,dive,required,max=30"`
+}
+
+// ReviewModerationBody represents the request body sent by the admin
+// to hide or show text of the review
+type ReviewModerationBody struct {
+	IsCommentHidden *bool `json:"is_comment_hidden" binding:"required"`
+}
+
+// ReviewIdURI represents URI with review's ID
+type ReviewIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+// ReviewListQuery represents query with page of reviews
+type ReviewListQuery struct {
+	Page int `form:"page" binding:"required,min=1"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+// SessionIdURI represents URI with session's ID
+type SessionIdURI struct {
+	ID string `uri:"id" binding:"required,hexadecimal,len=32"`
+}
+
+// SessionRefreshBody represents the request body with refresh token
+// sent by clients that do not keep cookies
+type SessionRefreshBody struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // UserSignUpRequestBody represents the request body with data
 // sent by the user to API to sign up in the application
 type UserSignUpRequestBody struct {
@@ -9,6 +11,7 @@ type UserSignUpRequestBody struct {
 	PhoneNumber string `json:"phone_number" binding:"required"`
 	Password    string `json:"password" binding:"required"`
 	IsCourier   bool   `json:"is_courier"`
+	CityID      int    `json:"city_id" binding:"omitempty,min=1"`
 }
 
 // UserLoginRequestBody represents the request body with data
@@ -16,6 +19,7 @@ type UserSignUpRequestBody struct {
 type UserLoginRequestBody struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"max=20"` // TOTP or recovery code if two-factor authentication is enabled
 }
 
 // UserBanParams represents URI with user's ID
@@ -23,3 +27,35 @@ type UserLoginRequestBody struct {
 type UserBanParams struct {
 	ID int `uri:"id" binding:"required,min=1"`
 }
+
+// UserIdURI represents URI with user's ID
+type UserIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+// RoleRequestBody represents the request body with the role granted to the user
+type RoleRequestBody struct {
+	Role string `json:"role" binding:"required,oneof=client courier dispatcher support finance super_admin"`
+}
+
+// UserRoleURI represents URI with user's ID and the role
+type UserRoleURI struct {
+	ID   int    `uri:"id" binding:"required,min=1"`
+	Role string `uri:"role" binding:"required"`
+}
+
+// UserUpdateRequestBody represents the request body with fields of the user's
+// profile to be changed, omitted fields are kept as they are
+type UserUpdateRequestBody struct {
+	Surname     *string `json:"surname" binding:"omitempty,max=100"`
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Email       *string `json:"email" binding:"omitempty,email,max=254"`
+	PhoneNumber *string `json:"phone_number" binding:"omitempty,max=20"`
+	CityID      *int    `json:"city_id" binding:"omitempty,min=1"`
+}
+
+// UserHistoryQuery represents query of the user's profile history,
+// snapshot of the profile at the time is returned if it is set
+type UserHistoryQuery struct {
+	At time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -5,9 +5,12 @@ import "time"
 // RoleMeta represents struct
 // with meta data about user's role
 type RoleMeta struct {
-	IsAdmin   bool `json:"is_admin"`
-	IsCourier bool `json:"is_courier"`
-	IsBanned  bool `json:"is_banned"`
+	IsAdmin         bool `json:"is_admin"`
+	IsCourier       bool `json:"is_courier"`
+	IsBanned        bool `json:"is_banned"`
+	CityID          int  `json:"city_id"`
+	IsEmailVerified bool `json:"is_email_verified"`
+	IsPhoneVerified bool `json:"is_phone_verified"`
 }
 
 // UserMeResponse represents the response body
@@ -18,6 +21,8 @@ type UserMeResponse struct {
 	Name        string    `json:"name"`
 	Email       string    `json:"email"`
 	PhoneNumber string    `json:"phone_number"`
+	HasAvatar   bool      `json:"has_avatar"`
+	AvatarKey   string    `json:"-"`
 	CreatedAt   time.Time `json:"created_at"`
 	Meta        *RoleMeta `json:"meta"`
 }
@@ -34,9 +39,30 @@ type UserInfoResponse struct {
 // UserMetaResponse represents the response body
 // with metadata about user
 type UserMetaResponse struct {
-	UserID    int     `json:"user_id"`
-	IsAdmin   bool    `json:"is_admin"`
-	IsCourier bool    `json:"is_courier"`
-	IsBanned  bool    `json:"is_banned"`
-	Rating    float32 `json:"rating"`
+	UserID          int     `json:"user_id"`
+	IsAdmin         bool    `json:"is_admin"`
+	IsCourier       bool    `json:"is_courier"`
+	IsBanned        bool    `json:"is_banned"`
+	Rating          float32 `json:"rating"`
+	CityID          int     `json:"city_id"`
+	IsEmailVerified bool    `json:"is_email_verified"`
+	IsPhoneVerified bool    `json:"is_phone_verified"`
+}
+
+// UserUpdateResponse represents the response body sent after the user's profile
+// is changed, new email is set only after it is confirmed by the link
+type UserUpdateResponse struct {
+	User               *UserMeResponse `json:"user"`
+	EmailChangePending bool            `json:"email_change_pending"`
+}
+
+// UserSnapshot represents the user's profile as it was at the time
+type UserSnapshot struct {
+	At          time.Time `json:"at"`
+	Surname     string    `json:"surname"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	PhoneNumber string    `json:"phone_number"`
+	CityID      int       `json:"city_id"`
+	AvatarKey   string    `json:"avatar_key"`
 }
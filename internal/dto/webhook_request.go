@@ -0,0 +1,27 @@
+package dto
+
+// WebhookRequestBody represents the request body with endpoint
+// of the client and events it is subscribed to
+type WebhookRequestBody struct {
+	URL    string   `json:"url" binding:"required,url,startswith=https://,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=delivery.created delivery.accepted delivery.picked_up delivery.completed delivery.cancelled"`
+
+	// Disabled webhook is enabled again by setting it to true
+	IsActive *bool `json:"is_active"`
+}
+
+// WebhookIdURI represents URI with webhook's ID
+type WebhookIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+// WebhookDeliveryURI represents URI with webhook's and its delivery's IDs
+type WebhookDeliveryURI struct {
+	ID         int `uri:"id" binding:"required,min=1"`
+	DeliveryID int `uri:"delivery_id" binding:"required,min=1"`
+}
+
+// WebhookDeliveryListQuery represents query with page of the webhook's delivery log
+type WebhookDeliveryListQuery struct {
+	Page int `form:"page" binding:"required,min=1"`
+}
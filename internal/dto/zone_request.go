@@ -0,0 +1,40 @@
+package dto
+
+import "encoding/json"
+
+// GeoJSONGeometry represents GeoJSON Polygon or MultiPolygon
+// geometry with positions in [longitude, latitude] order
+type GeoJSONGeometry struct {
+	Type        string          `json:"type" binding:"required,oneof=Polygon MultiPolygon"`
+	Coordinates json.RawMessage `json:"coordinates" binding:"required"`
+}
+
+// ZoneProperties represents settings of the service zone
+// stored in properties of its GeoJSON feature
+type ZoneProperties struct {
+	Name             string  `json:"name" binding:"required"`
+	AllowedTypes     []int64 `json:"allowed_types" binding:"required,min=1,dive,gte=1,lte=5"`
+	TariffMultiplier float64 `json:"tariff_multiplier" binding:"required,gt=0"`
+	IsActive         *bool   `json:"is_active"`
+}
+
+// ZoneFeature represents service zone as GeoJSON Feature,
+// it is used both to import and to export zones
+type ZoneFeature struct {
+	Type       string           `json:"type" binding:"required,eq=Feature"`
+	ID         int              `json:"id,omitempty"`
+	Geometry   *GeoJSONGeometry `json:"geometry" binding:"required"`
+	Properties *ZoneProperties  `json:"properties" binding:"required"`
+}
+
+// ZoneFeatureCollection represents list of service zones
+// as GeoJSON FeatureCollection
+type ZoneFeatureCollection struct {
+	Type     string         `json:"type" binding:"required,eq=FeatureCollection"`
+	Features []*ZoneFeature `json:"features" binding:"required,dive"`
+}
+
+// ZoneIdURI represents URI with service zone's ID
+type ZoneIdURI struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
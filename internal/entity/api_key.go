@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// Prefix of the plain api keys telling them apart from access tokens
+const APIKeyPrefix = "trk_"
+
+// Scopes of the api keys
+const (
+	ScopeDeliveriesCreate = "deliveries:create"
+	ScopeDeliveriesRead   = "deliveries:read"
+	ScopePriceEstimate    = "price:estimate"
+)
+
+// APIKey represents long-lived key of the client's integration,
+// only hash of the key is stored
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"-"`
+	OrgID      *int       `json:"organization_id"` // organization the key creates deliveries for
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // public part of the key identifying it
+	KeyHash    string     `json:"-"`
+	Key        string     `json:"-"` // plain key, known only on creation and rotation
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rate_limit"` // requests per minute, defined by the server
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// Allows checks if the key is granted the scope
+func (k *APIKey) Allows(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Types of the chat events delivered in real time
+const (
+	ChatEventMessage = "message"
+	ChatEventRead    = "read"
+)
+
+// ChatChannel represents state of the delivery's chat between client and courier
+type ChatChannel struct {
+	DeliveryID  int
+	ClientID    int
+	CourierID   int
+	StatusID    int
+	CompletedAt *time.Time
+}
+
+// ChatMessage represents message of the delivery's chat
+type ChatMessage struct {
+	ID         int        `json:"id"`
+	DeliveryID int        `json:"delivery_id"`
+	SenderID   int        `json:"sender_id"`
+	Text       string     `json:"text"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ChatEvent represents new message or read receipt of the chat
+type ChatEvent struct {
+	Type     string       `json:"type"`
+	Message  *ChatMessage `json:"message,omitempty"`
+	ReaderID int          `json:"reader_id,omitempty"`
+	ReadUpTo int          `json:"read_up_to,omitempty"` // id of the last read message
+}
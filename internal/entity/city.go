@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// City represents city where Truckly operates
+// with its own geocoder, timezone, currency and tariffs
+type City struct {
+	ID               int     `json:"id"`
+	Name             string  `json:"name"`
+	GeocoderID       string  `json:"geocoder_id"` // city id in 2GIS catalog
+	Timezone         string  `json:"timezone"`    // IANA time zone name
+	Currency         string  `json:"currency"`    // ISO 4217 code
+	TariffMultiplier float64 `json:"tariff_multiplier"`
+	EnabledTypes     []int64 `json:"enabled_types"`
+	IsActive         bool    `json:"is_active"`
+}
+
+// AllowsType reports whether the delivery type is enabled in the city
+func (c *City) AllowsType(typeID int) bool {
+	for _, t := range c.EnabledTypes {
+		if t == int64(typeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Now returns current time in the city's time zone
+func (c *City) Now() time.Time {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.Now()
+	}
+	return time.Now().In(loc)
+}
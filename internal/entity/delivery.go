@@ -6,15 +6,47 @@ import (
 
 // Delivery represents delivery data struct for internal use
 type Delivery struct {
-	ID        int       `json:"id"`
-	ClientID  int       `json:"client_id"`
-	CourierID int       `json:"courier_id"`
-	StatusID  int       `json:"status_id"`
-	TypeID    int       `json:"type_id"`
-	Geo       *Geo      `json:"geo"`
-	Price     float64   `json:"price"`
-	HasLoader bool      `json:"has_loader"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int        `json:"id"`
+	ClientID    int        `json:"client_id"`
+	CityID      int        `json:"city_id"`
+	CourierID   int        `json:"courier_id"`
+	StatusID    int        `json:"status_id"`
+	TypeID      int        `json:"type_id"`
+	Geo         *Geo       `json:"geo"`
+	Price       float64    `json:"price"`
+	HasLoader   bool       `json:"has_loader"`
+	CargoWeight float64    `json:"cargo_weight"` // in kg
+	CargoVolume float64    `json:"cargo_volume"` // in m3
+	PickedUpAt  *time.Time `json:"picked_up_at"`
+	ZoneID      *int       `json:"zone_id"`         // service zone of the pickup point
+	OrgID       *int       `json:"organization_id"` // organization the delivery is billed to
+	PIN         string     `json:"-"`               // one-time code of the recipient to complete the delivery
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Optional time windows of pickup and dropoff
+	PickupWindow  *TimeWindow `json:"pickup_window"`
+	DropoffWindow *TimeWindow `json:"dropoff_window"`
+
+	// Optional contact persons and access instructions of pickup and dropoff
+	PickupContact  *StopContact `json:"pickup_contact"`
+	DropoffContact *StopContact `json:"dropoff_contact"`
+}
+
+// StopContact represents person whom the courier meets at the stop
+// and instructions how to get to them
+type StopContact struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Entrance    string `json:"entrance"`
+	Floor       string `json:"floor"`
+	Intercom    string `json:"intercom"`
+	Notes       string `json:"notes"`
+}
+
+// TimeWindow represents period of time when the stop should be visited
+type TimeWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
 }
 
 // Geo represents geo data struct for internal use
@@ -26,4 +58,43 @@ type Geo struct {
 	ToLatitude    float64 `json:"to_latitude"`
 	ToObject      string  `json:"to_object"`
 	Distance      float64 `json:"distance"`
+
+	// Ids of the geo objects chosen from suggestions
+	// or the client's address book, points are resolved by them
+	FromPlaceID      string `json:"-"`
+	ToPlaceID        string `json:"-"`
+	FromSavedPlaceID int    `json:"-"`
+	ToSavedPlaceID   int    `json:"-"`
+}
+
+// VehicleCapacity represents limits of the delivery type's vehicle
+type VehicleCapacity struct {
+	TypeID    int     `json:"type_id"`
+	MaxActive int     `json:"max_active"`
+	MaxWeight float64 `json:"max_weight"` // in kg
+	MaxVolume float64 `json:"max_volume"` // in m3
+}
+
+// CourierLoad represents summary of courier's active deliveries
+type CourierLoad struct {
+	ActiveCnt int     `json:"active_cnt"`
+	Weight    float64 `json:"weight"` // in kg
+	Volume    float64 `json:"volume"` // in m3
+}
+
+// Route stop types
+const (
+	StopPickup  = "pickup"
+	StopDropoff = "dropoff"
+)
+
+// RouteStop represents pickup or dropoff point of courier's route
+type RouteStop struct {
+	DeliveryID int         `json:"delivery_id"`
+	Type       string      `json:"type"`
+	Object     string      `json:"object"`
+	Latitude   float64     `json:"latitude"`
+	Longitude  float64     `json:"longitude"`
+	Window     *TimeWindow `json:"window"`
+	ETA        time.Time   `json:"eta"`
 }
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Offer statuses of the automatic dispatch
+const (
+	OfferPending  = "pending"
+	OfferAccepted = "accepted"
+	OfferDeclined = "declined"
+	OfferExpired  = "expired"
+)
+
+// Offer represents delivery offer sent to a courier by the dispatcher
+type Offer struct {
+	ID         int       `json:"id"`
+	DeliveryID int       `json:"delivery_id"`
+	CourierID  int       `json:"courier_id"`
+	Status     string    `json:"status"`
+	Score      float64   `json:"score"`
+	Distance   float64   `json:"distance"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// CourierPresence represents courier's online status, vehicle and last known location
+type CourierPresence struct {
+	CourierID int     `json:"courier_id"`
+	IsOnline  bool    `json:"is_online"`
+	TypeID    int     `json:"type_id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// CourierCandidate represents online courier
+// that can receive an offer from the dispatcher
+type CourierCandidate struct {
+	CourierID int       `json:"courier_id"`
+	TypeID    int       `json:"type_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Rating    float64   `json:"rating"`
+	IdleSince time.Time `json:"idle_since"`
+	Distance  float64   `json:"distance"` // to pickup, in m
+	Duration  float64   `json:"duration"` // to pickup, in s
+	Score     float64   `json:"score"`
+}
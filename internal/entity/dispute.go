@@ -0,0 +1,77 @@
+package entity
+
+import "time"
+
+// Categories of the problems reported with disputes
+const (
+	DisputeDamaged = "damaged"
+	DisputeLost    = "lost"
+	DisputeNoShow  = "no_show"
+	DisputeLate    = "late"
+	DisputeOther   = "other"
+)
+
+// Statuses of the disputes, resolved and rejected ones are final
+const (
+	DisputeOpen       = "open"
+	DisputeInProgress = "in_progress"
+	DisputeResolved   = "resolved"
+	DisputeRejected   = "rejected"
+)
+
+// Kinds of the ledger entries
+const (
+	LedgerRefund  = "refund"
+	LedgerPenalty = "penalty"
+)
+
+// Dispute represents ticket of the delivery's participant to support
+type Dispute struct {
+	ID          int                  `json:"id"`
+	DeliveryID  int                  `json:"delivery_id"`
+	OpenerID    int                  `json:"opener_id"`
+	Category    string               `json:"category"`
+	Description string               `json:"description"`
+	Status      string               `json:"status"`
+	AssigneeID  *int                 `json:"assignee_id"`
+	Resolution  string               `json:"resolution"`
+	Attachments []*DisputeAttachment `json:"attachments"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	ResolvedAt  *time.Time           `json:"resolved_at"`
+}
+
+// IsClosed checks if the dispute is resolved or rejected
+func (d *Dispute) IsClosed() bool {
+	return d.Status == DisputeResolved || d.Status == DisputeRejected
+}
+
+// DisputeAttachment represents file attached to the dispute
+type DisputeAttachment struct {
+	ID        int       `json:"id"`
+	DisputeID int       `json:"dispute_id"`
+	FileKey   string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LedgerEntry represents money movement of the user, refunds
+// are positive and penalties are negative
+type LedgerEntry struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	DeliveryID int       `json:"delivery_id"`
+	DisputeID  *int      `json:"dispute_id"`
+	Kind       string    `json:"kind"`
+	Amount     float64   `json:"amount"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// DisputeResolution represents final decision on the dispute
+// with ledger entries to be recorded along with it
+type DisputeResolution struct {
+	DisputeID  int            `json:"dispute_id"`
+	Status     string         `json:"status"`
+	Resolution string         `json:"resolution"`
+	Entries    []*LedgerEntry `json:"entries"`
+	BannedID   int            `json:"banned_id,omitempty"`
+}
@@ -0,0 +1,79 @@
+package entity
+
+import "time"
+
+// Channels of the notifications
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelPush  = "push"
+)
+
+// Events users are notified about
+const (
+	NotifyDeliveryAccepted  = "delivery_accepted"
+	NotifyDeliveryPickedUp  = "delivery_picked_up"
+	NotifyDeliveryCompleted = "delivery_completed"
+	NotifyDeliveryCancelled = "delivery_cancelled"
+	NotifyNewOrderNearby    = "new_order_nearby"
+	NotifyDeliveryPIN       = "delivery_pin"
+	NotifyEmailVerification = "email_verification"
+	NotifyPasswordReset     = "password_reset"
+	NotifyEmailChange       = "email_change"
+	NotifyEmailChanged      = "email_changed"
+	NotifyOrgInvitation     = "org_invitation"
+)
+
+// Statuses of the notifications in the queue
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+)
+
+// Notification represents message to the user queued for sending via the channel
+type Notification struct {
+	ID            int        `json:"id"`
+	UserID        int        `json:"user_id"`
+	Event         string     `json:"event"`
+	Channel       string     `json:"channel"`
+	Recipient     string     `json:"recipient"` // email, phone number or push token
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	RefID         int        `json:"-"` // token the email's link is built from when it is sent
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// NotificationPreferences represents channels the user wants to be notified via
+type NotificationPreferences struct {
+	UserID       int    `json:"-"`
+	EmailEnabled bool   `json:"email_enabled"`
+	SMSEnabled   bool   `json:"sms_enabled"`
+	PushEnabled  bool   `json:"push_enabled"`
+	PushToken    string `json:"push_token"`
+}
+
+// NotificationRecipient represents contacts of the user with their preferences
+type NotificationRecipient struct {
+	UserID      int
+	Email       string
+	PhoneNumber string
+	Preferences *NotificationPreferences
+}
+
+// DeliveryNotice represents data of the delivery used in notifications
+type DeliveryNotice struct {
+	DeliveryID   int
+	ClientID     int
+	CourierID    int
+	FromObject   string
+	ToObject     string
+	Price        float64
+	DropoffPhone string // phone number of the dropoff contact if it is set
+	PIN          string // recipient's PIN, it is used only in the PIN notification
+}
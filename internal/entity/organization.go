@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Roles of the organization's members
+const (
+	OrgRoleOwner     = "owner"     // manages billing details and all members
+	OrgRoleManager   = "manager"   // manages requesters, address book and sees all deliveries
+	OrgRoleRequester = "requester" // creates deliveries on behalf of the organization
+)
+
+// Organization represents B2B account owning deliveries created by its members,
+// deliveries are billed to the organization using its billing details
+type Organization struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	LegalName      string    `json:"legal_name"`
+	TaxID          string    `json:"tax_id"`
+	BillingEmail   string    `json:"billing_email"`
+	BillingAddress string    `json:"billing_address"`
+	Role           string    `json:"role,omitempty"` // role of the requesting member
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// OrganizationMember represents user who is a member of the organization
+type OrganizationMember struct {
+	UserID   int       `json:"user_id"`
+	Surname  string    `json:"surname"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// OrganizationInvitation represents invitation sent by email to join the organization,
+// only hash of its token is stored
+type OrganizationInvitation struct {
+	ID             int       `json:"id"`
+	OrganizationID int       `json:"organization_id"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+	TokenHash      string    `json:"-"`
+	InvitedBy      int       `json:"invited_by"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the aggregates domain events belong to
+const (
+	AggregateDelivery = "delivery"
+	AggregateUser     = "user"
+)
+
+// Domain events written to the outbox along with the changes they describe
+const (
+	DeliveryCreated       = "delivery.created"
+	DeliveryAccepted      = "delivery.accepted"
+	DeliveryPickedUp      = "delivery.picked_up"
+	DeliveryStatusChanged = "delivery.status_changed"
+	DeliveryCompleted     = "delivery.completed"
+	DeliveryCancelled     = "delivery.cancelled"
+	UserCreated           = "user.created"
+	UserBanned            = "user.banned"
+	UserUnbanned          = "user.unbanned"
+)
+
+// OutboxEvent represents domain event of the aggregate, events of the same
+// aggregate are published in order of their ids
+type OutboxEvent struct {
+	ID            int             `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+
+	// Publishing state kept by the outbox, it isn't passed over the bus.
+	// Groups that handled the event are skipped when it's published again
+	Attempts      int      `json:"-"`
+	HandledGroups []string `json:"-"`
+}
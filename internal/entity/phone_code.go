@@ -0,0 +1,10 @@
+package entity
+
+// PhoneCode represents one-time code sent to the user's phone number,
+// only hash of the code is stored
+type PhoneCode struct {
+	UserID   int
+	Phone    string // in E.164 format
+	CodeHash string
+	Attempts int // of entering the code
+}
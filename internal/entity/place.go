@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// SavedPlace represents address saved by the user or shared in the organization's
+// address book to reuse it in deliveries
+type SavedPlace struct {
+	ID             int       `json:"id"`
+	UserID         int       `json:"-"`
+	OrganizationID int       `json:"-"`
+	Label          string    `json:"label"`
+	Latitude       float64   `json:"latitude"`
+	Longitude      float64   `json:"longitude"`
+	Object         string    `json:"object"`
+	Entrance       string    `json:"entrance"`
+	Floor          string    `json:"floor"`
+	Notes          string    `json:"notes"` // contacts and directions for the courier
+	CreatedAt      time.Time `json:"created_at"`
+}
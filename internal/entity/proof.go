@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Kinds of files attached to the proof of delivery
+const (
+	ProofPhoto     = "photo"
+	ProofSignature = "signature"
+)
+
+// Proof represents evidence that the delivery is handed over to the recipient
+type Proof struct {
+	DeliveryID   int        `json:"delivery_id"`
+	PIN          string     `json:"-"`
+	PINAttempts  int        `json:"-"`
+	PhotoKey     string     `json:"photo_key"`
+	SignatureKey string     `json:"signature_key"`
+	Latitude     float64    `json:"latitude"`
+	Longitude    float64    `json:"longitude"`
+	Distance     float64    `json:"distance"` // from the dropoff point in m
+	CompletedAt  *time.Time `json:"completed_at"`
+}
+
+// Types of the delivery timeline events
+const (
+	EventCreated           = "created"
+	EventAccepted          = "accepted"
+	EventPickedUp          = "picked_up"
+	EventStatusChanged     = "status_changed"
+	EventCancelled         = "cancelled"
+	EventPhotoUploaded     = "photo_uploaded"
+	EventSignatureUploaded = "signature_uploaded"
+	EventPINFailed         = "pin_failed"
+	EventCompleted         = "completed"
+	EventReviewed          = "reviewed"
+
+	EventDisputeOpened        = "dispute_opened"
+	EventDisputeAttachment    = "dispute_attachment_added"
+	EventDisputeAssigned      = "dispute_assigned"
+	EventDisputeStatusChanged = "dispute_status_changed"
+	EventDisputeResolved      = "dispute_resolved"
+)
+
+// DeliveryEvent represents entry of the delivery timeline
+type DeliveryEvent struct {
+	ID         int             `json:"id"`
+	DeliveryID int             `json:"delivery_id"`
+	Type       string          `json:"type"`
+	ActorID    *int            `json:"actor_id"`
+	Payload    json.RawMessage `json:"payload"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Roles of the review's author in the delivery
+const (
+	ReviewByClient  = "client"
+	ReviewByCourier = "courier"
+)
+
+// Review represents rating given by the participant of completed delivery to the other one
+type Review struct {
+	ID              int       `json:"id"`
+	DeliveryID      int       `json:"delivery_id"`
+	AuthorID        int       `json:"author_id"`
+	TargetID        int       `json:"target_id"`
+	AuthorRole      string    `json:"author_role"`
+	Score           int       `json:"score"`
+	Comment         string    `json:"comment"`
+	Tags            []string  `json:"tags"`
+	IsCommentHidden bool      `json:"is_comment_hidden"` // hidden by moderator
+	CreatedAt       time.Time `json:"created_at"`
+}
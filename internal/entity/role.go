@@ -0,0 +1,93 @@
+package entity
+
+// Roles of the users, a user may have several roles
+const (
+	RoleClient     = "client"
+	RoleCourier    = "courier"
+	RoleDispatcher = "dispatcher"
+	RoleSupport    = "support"
+	RoleFinance    = "finance"
+	RoleSuperAdmin = "super_admin"
+)
+
+// Permissions granted by the roles
+const (
+	PermDeliveriesCreate    = "deliveries:create"
+	PermDeliveriesPerform   = "deliveries:perform" // accept, pick up and complete deliveries
+	PermDeliveriesReadAny   = "deliveries:read_any"
+	PermDeliveriesCancelAny = "deliveries:cancel_any"
+	PermDisputesManage      = "disputes:manage"
+	PermDisputesResolve     = "disputes:resolve"
+	PermReviewsModerate     = "reviews:moderate"
+	PermUsersBan            = "users:ban"
+	PermUsersRead           = "users:read" // profiles' change history
+	PermMetricsRead         = "metrics:read"
+	PermZonesManage         = "zones:manage"
+	PermCitiesManage        = "cities:manage"
+	PermRolesManage         = "roles:manage"
+)
+
+// RolePermissions maps each role to the permissions it grants
+var RolePermissions = map[string][]string{
+	RoleClient:  {PermDeliveriesCreate},
+	RoleCourier: {PermDeliveriesPerform},
+	RoleDispatcher: {
+		PermDeliveriesReadAny, PermDeliveriesCancelAny, PermZonesManage, PermMetricsRead,
+	},
+	RoleSupport: {
+		PermDeliveriesReadAny, PermDeliveriesCancelAny, PermDisputesManage, PermDisputesResolve,
+		PermReviewsModerate, PermUsersBan, PermUsersRead,
+	},
+	RoleFinance: {PermDisputesResolve, PermMetricsRead},
+	RoleSuperAdmin: {
+		PermDeliveriesCreate, PermDeliveriesReadAny, PermDeliveriesCancelAny, PermDisputesManage,
+		PermDisputesResolve, PermReviewsModerate, PermUsersBan, PermUsersRead, PermMetricsRead, PermZonesManage,
+		PermCitiesManage, PermRolesManage,
+	},
+}
+
+// StaffRoles are roles of the company's employees, users with any of them
+// must pass the second factor, is_admin flag is kept only to exclude them from metrics
+var StaffRoles = []string{RoleDispatcher, RoleSupport, RoleFinance, RoleSuperAdmin}
+
+// Permissions returns distinct permissions granted by the roles
+func Permissions(roles []string) []string {
+	seen := make(map[string]bool)
+	results := make([]string, 0)
+	for _, role := range roles {
+		for _, p := range RolePermissions[role] {
+			if !seen[p] {
+				seen[p] = true
+				results = append(results, p)
+			}
+		}
+	}
+	return results
+}
+
+// IsStaffPermission checks if the permission is granted only by staff roles
+func IsStaffPermission(permission string) bool {
+	return permission != PermDeliveriesCreate && permission != PermDeliveriesPerform
+}
+
+// RolesGrant checks if any of the roles grants the permission
+func RolesGrant(roles []string, permission string) bool {
+	for _, p := range Permissions(roles) {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStaffRole checks if any of the roles is a staff role
+func HasStaffRole(roles []string) bool {
+	for _, role := range roles {
+		for _, staff := range StaffRoles {
+			if role == staff {
+				return true
+			}
+		}
+	}
+	return false
+}
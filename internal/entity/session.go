@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// Session represents logged in device of the user,
+// it lives while its refresh token is rotated in time
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     int       `json:"-"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"` // time of the last refresh
+	ExpiresAt  time.Time `json:"expires_at"`
+	TwoFactor  bool      `json:"two_factor"` // passed the second factor
+	Current    bool      `json:"current"`    // session of the request
+}
+
+// SessionClient represents device the session is used from
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+// AuthTokens represents tokens issued to the session, access token authorizes
+// requests and refresh token is exchanged for the new pair of tokens
+type AuthTokens struct {
+	SessionID        string    `json:"session_id"`
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// TwoFactor represents TOTP second factor of the user, it is
+// enabled after the user confirms enrollment with the first code
+type TwoFactor struct {
+	UserID    int
+	Secret    string
+	EnabledAt *time.Time
+	CreatedAt time.Time
+}
+
+// TwoFactorEnrollment represents secret shown to the user on enrollment,
+// authenticator apps add it by QR code of the URL or by the secret itself
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"otpauth_url"`
+}
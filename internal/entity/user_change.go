@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// Fields of the user's profile whose changes are recorded
+const (
+	FieldSurname     = "surname"
+	FieldName        = "name"
+	FieldEmail       = "email"
+	FieldPhoneNumber = "phone_number"
+	FieldCityID      = "city_id"
+	FieldAvatar      = "avatar" // key of the avatar in the blob store
+)
+
+// UserChange represents change of the field of the user's profile,
+// values are kept as strings whatever type the field has
+type UserChange struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	ChangedBy int       `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
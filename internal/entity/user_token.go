@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Purposes of the users' one-time tokens
+const (
+	TokenEmailVerification = "email_verification"
+	TokenPasswordReset     = "password_reset"
+	TokenEmailChange       = "email_change"
+)
+
+// UserToken represents single-use expiring token sent to the user by email,
+// only hash of the token is stored
+type UserToken struct {
+	ID        int
+	UserID    int
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	Email     string // new email of the email change token
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
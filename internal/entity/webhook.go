@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Delivery lifecycle events sent to webhooks, they are domain events of the outbox
+const (
+	WebhookDeliveryCreated   = DeliveryCreated
+	WebhookDeliveryAccepted  = DeliveryAccepted
+	WebhookDeliveryPickedUp  = DeliveryPickedUp
+	WebhookDeliveryCompleted = DeliveryCompleted
+	WebhookDeliveryCancelled = DeliveryCancelled
+)
+
+// Statuses of the webhook deliveries
+const (
+	WebhookPending   = "pending"
+	WebhookSucceeded = "succeeded"
+	WebhookFailed    = "failed"
+)
+
+// Webhook represents endpoint of the client receiving delivery events
+type Webhook struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"-"`
+	URL          string     `json:"url"`
+	Secret       string     `json:"-"`
+	Events       []string   `json:"events"`
+	IsActive     bool       `json:"is_active"`
+	FailureCount int        `json:"failure_count"` // consecutive failed attempts
+	DisabledAt   *time.Time `json:"disabled_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Subscribes checks if the webhook is subscribed to the event
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery represents sending of the event to the webhook with its attempts
+type WebhookDelivery struct {
+	ID             int             `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status"`
+	LastError      string          `json:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+
+	// Endpoint the delivery is sent to
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookEventData represents state of the delivery sent in webhook's payload
+type WebhookEventData struct {
+	DeliveryID int       `json:"delivery_id"`
+	ClientID   int       `json:"-"`
+	CourierID  *int      `json:"courier_id"`
+	StatusID   int       `json:"status_id"`
+	Price      float64   `json:"price"`
+	FromObject string    `json:"from_object"`
+	ToObject   string    `json:"to_object"`
+	CreatedAt  time.Time `json:"created_at"`
+}
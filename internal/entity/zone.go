@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/geohelper"
+)
+
+// ServiceZone represents area where deliveries are served
+// with its own settings of delivery types and tariffs
+type ServiceZone struct {
+	ID               int             `json:"id"`
+	Name             string          `json:"name"`
+	Geometry         json.RawMessage `json:"geometry"` // GeoJSON Polygon or MultiPolygon
+	AllowedTypes     []int64         `json:"allowed_types"`
+	TariffMultiplier float64         `json:"tariff_multiplier"`
+	IsActive         bool            `json:"is_active"`
+	CreatedAt        time.Time       `json:"created_at"`
+
+	// Parsed geometry used for point-in-polygon checks
+	Area geohelper.MultiPolygon `json:"-"`
+}
+
+// AllowsType reports whether the delivery type is served in the zone
+func (z *ServiceZone) AllowsType(typeID int) bool {
+	for _, t := range z.AllowedTypes {
+		if t == int64(typeID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+)
+
+// LocalStore is a blob store keeping files in the directory of local filesystem
+type LocalStore struct {
+	Dir       string
+	appLogger *logger.Logger
+}
+
+func NewLocalStore(dir string, l *logger.Logger) *LocalStore {
+	return &LocalStore{
+		Dir:       dir,
+		appLogger: l,
+	}
+}
+
+// Put writes the blob by the key replacing existing one,
+// file is written to the temporary one first so readers never see it partially
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		s.appLogger.Error(err)
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Get opens the blob by the key, caller must close it
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		s.appLogger.Error(err)
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = errors.New("file is not found")
+		s.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		s.appLogger.Error(err)
+		return nil, err
+	}
+	return f, nil
+}
+
+// path converts the key to the path inside the store's directory
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid key")
+	}
+	return filepath.Join(s.Dir, cleaned), nil
+}
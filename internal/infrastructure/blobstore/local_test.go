@@ -0,0 +1,34 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	testLogger := logrus.New()
+	store := NewLocalStore(t.TempDir(), logger.New(testLogger))
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "proofs/1/photo", strings.NewReader("first")))
+	require.NoError(t, store.Put(ctx, "proofs/1/photo", strings.NewReader("second")))
+
+	f, err := store.Get(ctx, "proofs/1/photo")
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	f.Close()
+	require.NoError(t, err)
+	require.Equal(t, "second", string(data))
+
+	_, err = store.Get(ctx, "proofs/2/photo")
+	require.EqualError(t, err, "file is not found")
+
+	err = store.Put(ctx, "../outside", strings.NewReader("data"))
+	require.EqualError(t, err, "invalid key")
+}
@@ -0,0 +1,97 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// subscription represents handler of the consumers' group
+type subscription struct {
+	group   string
+	handler func(context.Context, *entity.OutboxEvent) error
+}
+
+// MemoryBus is a struct that provides in-process
+// delivery of domain events, events are passed
+// to the handlers synchronously while publishing
+type MemoryBus struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+	appLogger     *logger.Logger
+}
+
+func NewMemoryBus(l *logger.Logger) *MemoryBus {
+	return &MemoryBus{appLogger: l}
+}
+
+// Publish passes the event to the handlers of the groups that haven't handled it yet,
+// each group is called on its own and the ones that succeed are added to the event's
+// handled groups. Publishing fails if any group fails so that the event is published
+// again to the failed groups only
+func (mb *MemoryBus) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	mb.mu.RLock()
+	subscriptions := mb.subscriptions
+	mb.mu.RUnlock()
+
+	var publishErr error
+	for _, s := range subscriptions {
+		if handled(event, s.group) {
+			continue
+		}
+		if err := s.handler(ctx, event); err != nil {
+			err = fmt.Errorf("%s failed to handle event %d: %w", s.group, event.ID, err)
+			mb.appLogger.Error(err)
+			if publishErr == nil {
+				publishErr = err
+			}
+			continue
+		}
+		event.HandledGroups = append(event.HandledGroups, s.group)
+	}
+	return publishErr
+}
+
+// handled reports whether the group has already handled the event
+func handled(event *entity.OutboxEvent, group string) bool {
+	for _, g := range event.HandledGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe adds handler of the group, it is removed when the context is done
+func (mb *MemoryBus) Subscribe(ctx context.Context, group string, handler func(context.Context, *entity.OutboxEvent) error) error {
+	s := &subscription{group: group, handler: handler}
+
+	mb.mu.Lock()
+	mb.subscriptions = append(mb.subscriptions, s)
+	mb.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		mb.unsubscribe(s)
+	}()
+	return nil
+}
+
+// unsubscribe removes the subscription, slice is copied
+// since it may be read by publishers at the moment
+func (mb *MemoryBus) unsubscribe(s *subscription) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	subscriptions := make([]*subscription, 0, len(mb.subscriptions))
+	for _, sub := range mb.subscriptions {
+		if sub != s {
+			subscriptions = append(subscriptions, sub)
+		}
+	}
+	mb.subscriptions = subscriptions
+}
@@ -0,0 +1,60 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBus(t *testing.T) {
+	testLogger := logrus.New()
+	bus := NewMemoryBus(logger.New(testLogger))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var webhooks, notifications []int
+	failing := true
+
+	require.NoError(t, bus.Subscribe(ctx, "notifications", func(ctx context.Context, e *entity.OutboxEvent) error {
+		if failing {
+			return fmt.Errorf("database is unavailable")
+		}
+		notifications = append(notifications, e.ID)
+		return nil
+	}))
+	require.NoError(t, bus.Subscribe(context.Background(), "webhooks", func(ctx context.Context, e *entity.OutboxEvent) error {
+		webhooks = append(webhooks, e.ID)
+		return nil
+	}))
+
+	event := &entity.OutboxEvent{ID: 1, AggregateType: entity.AggregateDelivery, AggregateID: 7, Type: entity.DeliveryCreated}
+
+	// Failed handler fails publishing so that the event is published again, it doesn't
+	// stop other groups from handling the event and they are not called again
+	err := bus.Publish(context.Background(), event)
+	require.EqualError(t, err, "notifications failed to handle event 1: database is unavailable")
+	require.Equal(t, []string{"webhooks"}, event.HandledGroups)
+
+	failing = false
+	require.NoError(t, bus.Publish(context.Background(), event))
+	require.Equal(t, []int{1}, webhooks)
+	require.Equal(t, []int{1}, notifications)
+	require.Equal(t, []string{"webhooks", "notifications"}, event.HandledGroups)
+
+	// Handler is removed when its context is done
+	cancel()
+	require.Eventually(t, func() bool {
+		bus.mu.RLock()
+		defer bus.mu.RUnlock()
+		return len(bus.subscriptions) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, bus.Publish(context.Background(), &entity.OutboxEvent{ID: 2}))
+	require.Equal(t, []int{1, 2}, webhooks)
+	require.Equal(t, []int{1}, notifications)
+}
@@ -0,0 +1,286 @@
+package eventbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Time the consumers' group is owned by the instance without renewal, it is renewed
+// every third of the time both between and during batches,
+// number of events read at once, delay after failed handling doubled for
+// each next failure up to the maximum and deliveries of the event to the group
+// after which it's moved to the dead letter stream
+var (
+	streamLease         = 15 * time.Second
+	streamBatchSize     = int64(100)
+	streamRetryDelay    = 5 * time.Second
+	streamMaxRetryDelay = 5 * time.Minute
+	streamMaxAttempts   = int64(10)
+)
+
+// leaseScript takes or renews ownership of the group's lock,
+// it returns 1 if the lock is owned by the token
+var leaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// RedisStreamBus is a struct that provides delivery
+// of domain events between app instances via redis stream,
+// each group of consumers reads the stream in order and
+// acknowledges events after they are handled
+type RedisStreamBus struct {
+	redisClient *redis.Client
+	stream      string
+	maxLen      int64
+	appLogger   *logger.Logger
+}
+
+func NewRedisStreamBus(rdb *redis.Client, stream string, maxLen int, l *logger.Logger) *RedisStreamBus {
+	return &RedisStreamBus{redisClient: rdb, stream: stream, maxLen: int64(maxLen), appLogger: l}
+}
+
+// Publish appends the event to the stream
+func (rb *RedisStreamBus) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		rb.appLogger.Error(err)
+		return err
+	}
+
+	err = rb.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: rb.stream,
+		MaxLen: rb.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": raw},
+	}).Err()
+	if err != nil {
+		rb.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Subscribe creates the group if it does not exist and starts consuming the stream
+// until the context is done. Only one instance consumes events of the group at a time
+// so that they are handled in order, failed events are handled again before the next ones
+// until they are moved to the dead letter stream. Groups consume the stream independently
+func (rb *RedisStreamBus) Subscribe(ctx context.Context, group string, handler func(context.Context, *entity.OutboxEvent) error) error {
+	err := rb.redisClient.XGroupCreateMkStream(ctx, rb.stream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		rb.appLogger.Error(err)
+		return err
+	}
+
+	token := make([]byte, 16)
+	if _, err = rand.Read(token); err != nil {
+		rb.appLogger.Error(err)
+		return err
+	}
+
+	go rb.consume(ctx, group, hex.EncodeToString(token), handler)
+	return nil
+}
+
+// consume reads events of the group while the instance owns its lock,
+// handling is retried with exponential backoff after failures
+func (rb *RedisStreamBus) consume(ctx context.Context, group, token string, handler func(context.Context, *entity.OutboxEvent) error) {
+	lockKey := fmt.Sprintf("%s:%s:lock", rb.stream, group)
+	retryDelay := streamRetryDelay
+
+	for ctx.Err() == nil {
+		owned, err := leaseScript.Run(ctx, rb.redisClient, []string{lockKey}, token, streamLease.Milliseconds()).Bool()
+		if err != nil && ctx.Err() == nil {
+			rb.appLogger.Error(err)
+		}
+		if err != nil || !owned {
+			sleep(ctx, streamLease/3)
+			continue
+		}
+
+		batchCtx, cancel := context.WithCancel(ctx)
+		renewing := make(chan struct{})
+		go func() {
+			defer close(renewing)
+			rb.holdLease(batchCtx, cancel, lockKey, token)
+		}()
+
+		err = rb.handleBatch(batchCtx, group, handler)
+		lost := batchCtx.Err() != nil && ctx.Err() == nil
+		cancel()
+		<-renewing
+
+		if lost {
+			rb.appLogger.Error(fmt.Errorf("lock of group %s of stream %s is lost while handling events", group, rb.stream))
+			continue
+		}
+		if err == nil {
+			retryDelay = streamRetryDelay
+			continue
+		}
+		if ctx.Err() == nil {
+			rb.appLogger.Error(err)
+			sleep(ctx, retryDelay)
+			retryDelay *= 2
+			if retryDelay > streamMaxRetryDelay {
+				retryDelay = streamMaxRetryDelay
+			}
+		}
+	}
+}
+
+// holdLease renews the group's lock while the batch is handled, the batch is cancelled
+// when the lock is taken by another instance or can't be renewed before it expires,
+// so that events of the group are never handled by two instances at once
+func (rb *RedisStreamBus) holdLease(ctx context.Context, cancel context.CancelFunc, lockKey, token string) {
+	ticker := time.NewTicker(streamLease / 3)
+	defer ticker.Stop()
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		owned, err := leaseScript.Run(ctx, rb.redisClient, []string{lockKey}, token, streamLease.Milliseconds()).Bool()
+		if err == nil && owned {
+			renewed = time.Now()
+			continue
+		}
+		if err != nil && ctx.Err() == nil {
+			rb.appLogger.Error(err)
+		}
+		if err == nil || time.Since(renewed) >= streamLease*2/3 {
+			cancel()
+			return
+		}
+	}
+}
+
+// handleBatch handles events left unacknowledged by the previous owner or failed attempt,
+// new events are read only when there are no such ones. Consumer is named after the group
+// so that pending events are taken over with the lock
+func (rb *RedisStreamBus) handleBatch(ctx context.Context, group string, handler func(context.Context, *entity.OutboxEvent) error) error {
+	for _, start := range []string{"0", ">"} {
+		args := &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: group,
+			Streams:  []string{rb.stream, start},
+			Count:    streamBatchSize,
+			Block:    -1,
+		}
+		if start == ">" {
+			args.Block = streamLease / 3
+		}
+
+		streams, err := rb.redisClient.XReadGroup(ctx, args).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		messages := streams[0].Messages
+		if len(messages) == 0 {
+			continue
+		}
+
+		for _, msg := range messages {
+			if err = rb.handleMessage(ctx, msg, handler); err != nil {
+				dead, deadErr := rb.deadLetter(ctx, group, msg, err)
+				if deadErr != nil {
+					return deadErr
+				}
+				if !dead {
+					return err
+				}
+			}
+			if err = rb.redisClient.XAck(ctx, rb.stream, group, msg.ID).Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// deadLetter moves the message the group has failed to handle too many times
+// to the group's dead letter stream, so that it doesn't block the next events.
+// It reports whether the message has been moved and can be acknowledged
+func (rb *RedisStreamBus) deadLetter(ctx context.Context, group string, msg redis.XMessage, handleErr error) (bool, error) {
+	pending, err := rb.redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: rb.stream,
+		Group:  group,
+		Start:  msg.ID,
+		End:    msg.ID,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		return false, err
+	}
+	if len(pending) == 0 || pending[0].RetryCount < streamMaxAttempts {
+		return false, nil
+	}
+
+	err = rb.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: fmt.Sprintf("%s:%s:dead", rb.stream, group),
+		MaxLen: rb.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"id": msg.ID, "event": msg.Values["event"], "error": handleErr.Error()},
+	}).Err()
+	if err != nil {
+		return false, err
+	}
+
+	rb.appLogger.Error(fmt.Errorf("message %s of stream %s is dead for %s after %d attempts: %w",
+		msg.ID, rb.stream, group, pending[0].RetryCount, handleErr))
+	return true, nil
+}
+
+// handleMessage decodes the event and passes it to the handler,
+// malformed messages are skipped since they can never be handled
+func (rb *RedisStreamBus) handleMessage(ctx context.Context, msg redis.XMessage, handler func(context.Context, *entity.OutboxEvent) error) error {
+	raw, ok := msg.Values["event"].(string)
+	if !ok {
+		rb.appLogger.Error(fmt.Errorf("message %s of stream %s has no event", msg.ID, rb.stream))
+		return nil
+	}
+
+	event := &entity.OutboxEvent{}
+	if err := json.Unmarshal([]byte(raw), event); err != nil {
+		rb.appLogger.Error(err)
+		return nil
+	}
+	return handler(ctx, event)
+}
+
+// sleep waits for the duration or until the context is done
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,88 @@
+package eventbus
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain shortens the lease before any consumer is started,
+// since consumers of the tests are stopped asynchronously
+func TestMain(m *testing.M) {
+	streamLease = 300 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+// newTestRedisStreamBus returns the bus backed by in-memory redis
+func newTestRedisStreamBus(t *testing.T) (*RedisStreamBus, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	testLogger := logrus.New()
+	return NewRedisStreamBus(rdb, "events", 1000, logger.New(testLogger)), mr
+}
+
+func TestRedisStreamBus_Lease(t *testing.T) {
+	event := &entity.OutboxEvent{ID: 1, AggregateType: entity.AggregateDelivery, AggregateID: 7, Type: entity.DeliveryCreated}
+
+	t.Run("lock is renewed while slow handler runs", func(t *testing.T) {
+		bus, mr := newTestRedisStreamBus(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		held := make(chan bool, 1)
+		require.NoError(t, bus.Subscribe(ctx, "webhooks", func(ctx context.Context, e *entity.OutboxEvent) error {
+			ok := true
+			for i := 0; i < 5; i++ {
+				time.Sleep(streamLease / 2)
+				mr.FastForward(streamLease * 2 / 3)
+				ok = ok && mr.Exists("events:webhooks:lock") && ctx.Err() == nil
+			}
+			held <- ok
+			return nil
+		}))
+		require.NoError(t, bus.Publish(context.Background(), event))
+
+		select {
+		case ok := <-held:
+			require.True(t, ok)
+		case <-time.After(10 * time.Second):
+			t.Fatal("event is not handled")
+		}
+	})
+
+	t.Run("batch is cancelled when lock is taken", func(t *testing.T) {
+		bus, mr := newTestRedisStreamBus(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cancelled := make(chan error, 1)
+		require.NoError(t, bus.Subscribe(ctx, "webhooks", func(ctx context.Context, e *entity.OutboxEvent) error {
+			require.NoError(t, mr.Set("events:webhooks:lock", "other"))
+			select {
+			case <-ctx.Done():
+				cancelled <- ctx.Err()
+			case <-time.After(5 * time.Second):
+				cancelled <- nil
+			}
+			return ctx.Err()
+		}))
+		require.NoError(t, bus.Publish(context.Background(), event))
+
+		select {
+		case err := <-cancelled:
+			require.ErrorIs(t, err, context.Canceled)
+		case <-time.After(10 * time.Second):
+			t.Fatal("event is not handled")
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// FileSender is a local stand-in of any channel for development and tests,
+// notifications are appended as JSON lines to the channel's file in the directory
+type FileSender struct {
+	Dir       string
+	mu        sync.Mutex
+	appLogger *logger.Logger
+}
+
+func NewFileSender(dir string, l *logger.Logger) *FileSender {
+	return &FileSender{
+		Dir:       dir,
+		appLogger: l,
+	}
+}
+
+// fileRecord is a line of the sink's file
+type fileRecord struct {
+	ID        int       `json:"id"`
+	Event     string    `json:"event"`
+	Recipient string    `json:"recipient"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	SentAt    time.Time `json:"sent_at"`
+}
+
+// Send appends the notification to the file of its channel
+func (s *FileSender) Send(ctx context.Context, n *entity.Notification) error {
+	line, err := json.Marshal(&fileRecord{
+		ID:        n.ID,
+		Event:     n.Event,
+		Recipient: n.Recipient,
+		Subject:   n.Subject,
+		Body:      n.Body,
+		SentAt:    time.Now(),
+	})
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err = os.MkdirAll(s.Dir, 0o750); err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.Dir, n.Channel+".log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	defer f.Close()
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// SendSMS appends the text to the file of the SMS channel
+func (s *FileSender) SendSMS(ctx context.Context, to, text string) error {
+	return s.Send(ctx, &entity.Notification{
+		Channel:   entity.ChannelSMS,
+		Recipient: to,
+		Body:      text,
+	})
+}
@@ -0,0 +1,90 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSender(t *testing.T) {
+	testLogger := logrus.New()
+	dir := t.TempDir()
+	sender := NewFileSender(dir, logger.New(testLogger))
+	ctx := context.Background()
+
+	for i := 1; i <= 2; i++ {
+		err := sender.Send(ctx, &entity.Notification{
+			ID:        i,
+			Event:     entity.NotifyDeliveryAccepted,
+			Channel:   entity.ChannelSMS,
+			Recipient: "+79990000000",
+			Body:      "Delivery is accepted",
+		})
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "sms.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var record fileRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	require.Equal(t, 2, record.ID)
+	require.Equal(t, "+79990000000", record.Recipient)
+}
+
+func TestSMSSender(t *testing.T) {
+	testLogger := logrus.New()
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{
+			name:   "sms is sent",
+			status: http.StatusOK,
+		},
+		{
+			name:    "gateway fails",
+			status:  http.StatusServiceUnavailable,
+			wantErr: "sms gateway responded with status 503",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+				var body smsRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				require.Equal(t, smsRequest{To: "+79990000000", Text: "Courier is on the way"}, body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			sender := NewSMSSender(server.URL, "secret", logger.New(testLogger))
+			err := sender.Send(context.Background(), &entity.Notification{
+				Channel:   entity.ChannelSMS,
+				Recipient: "+79990000000",
+				Body:      "Courier is on the way",
+			})
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Endpoint of the Firebase Cloud Messaging HTTP API
+const fcmURL = "https://fcm.googleapis.com/fcm/send"
+
+// PushSender is a struct that sends notifications
+// to users' devices via Firebase Cloud Messaging
+type PushSender struct {
+	url       string
+	serverKey string
+	client    *http.Client
+	appLogger *logger.Logger
+}
+
+func NewPushSender(serverKey string, l *logger.Logger) *PushSender {
+	return &PushSender{
+		url:       fcmURL,
+		serverKey: serverKey,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		appLogger: l,
+	}
+}
+
+// pushRequest is a request body of FCM
+type pushRequest struct {
+	To           string `json:"to"`
+	Notification struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	} `json:"notification"`
+}
+
+// pushResponse is a response body of FCM, errors of
+// the messages are reported in results with status 200
+type pushResponse struct {
+	Failure int `json:"failure"`
+	Results []struct {
+		Error string `json:"error"`
+	} `json:"results"`
+}
+
+// Send sends the notification to the device by its push token
+func (s *PushSender) Send(ctx context.Context, n *entity.Notification) error {
+	pr := &pushRequest{To: n.Recipient}
+	pr.Notification.Title = n.Subject
+	pr.Notification.Body = n.Body
+	body, err := json.Marshal(pr)
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+s.serverKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("push service responded with status %d", resp.StatusCode)
+		s.appLogger.Error(err)
+		return err
+	}
+
+	var result pushResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	if result.Failure > 0 {
+		err = fmt.Errorf("push is not delivered")
+		if len(result.Results) > 0 && result.Results[0].Error != "" {
+			err = fmt.Errorf("push is not delivered: %s", result.Results[0].Error)
+		}
+		s.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// SMSSender is a struct that sends notifications as SMS via HTTP gateway
+type SMSSender struct {
+	url       string
+	apiKey    string
+	client    *http.Client
+	appLogger *logger.Logger
+}
+
+func NewSMSSender(url, apiKey string, l *logger.Logger) *SMSSender {
+	return &SMSSender{
+		url:       url,
+		apiKey:    apiKey,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		appLogger: l,
+	}
+}
+
+// smsRequest is a request body of the SMS gateway
+type smsRequest struct {
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+// Send sends body of the notification to the recipient's phone number
+func (s *SMSSender) Send(ctx context.Context, n *entity.Notification) error {
+	return s.SendSMS(ctx, n.Recipient, n.Body)
+}
+
+// SendSMS sends the text to the phone number
+func (s *SMSSender) SendSMS(ctx context.Context, to, text string) error {
+	body, err := json.Marshal(&smsRequest{To: to, Text: text})
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		err = fmt.Errorf("sms gateway responded with status %d", resp.StatusCode)
+		s.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
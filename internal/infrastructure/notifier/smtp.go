@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// SMTPSender is a struct that sends notifications as emails via SMTP server
+type SMTPSender struct {
+	addr      string
+	auth      smtp.Auth
+	from      string
+	appLogger *logger.Logger
+}
+
+func NewSMTPSender(cfg *config.NOTIFY, l *logger.Logger) *SMTPSender {
+	var auth smtp.Auth
+	if cfg.SMTPUser != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return &SMTPSender{
+		addr:      fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort),
+		auth:      auth,
+		from:      cfg.SMTPFrom,
+		appLogger: l,
+	}
+}
+
+// Send sends the notification as plain text email
+func (s *SMTPSender) Send(ctx context.Context, n *entity.Notification) error {
+	return s.SendMail(ctx, n.Recipient, n.Subject, n.Body)
+}
+
+// SendMail sends plain text email
+func (s *SMTPSender) SendMail(ctx context.Context, to, subject, body string) error {
+	msg := buildEmail(s.from, to, subject, body)
+	err := smtp.SendMail(s.addr, s.auth, s.from, []string{to}, msg)
+	if err != nil {
+		s.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// buildEmail builds message of the email with headers, line breaks
+// are dropped from the headers so they can't be injected
+func buildEmail(from, to, subject, body string) []byte {
+	header := strings.NewReplacer("\r", "", "\n", "")
+	var b strings.Builder
+	b.WriteString("From: " + header.Replace(from) + "\r\n")
+	b.WriteString("To: " + header.Replace(to) + "\r\n")
+	b.WriteString("Subject: " + header.Replace(subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(body)
+	return []byte(b.String())
+}
@@ -0,0 +1,78 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ChatBroker is a struct that provides
+// real time delivery of chat events between
+// app instances via redis pub/sub
+type ChatBroker struct {
+	redisClient *redis.Client
+	appLogger   *logger.Logger
+}
+
+func NewChatBroker(rdb *redis.Client, l *logger.Logger) *ChatBroker {
+	return &ChatBroker{rdb, l}
+}
+
+// chatChannel returns name of the redis channel of the delivery's chat
+func chatChannel(deliveryID int) string {
+	return fmt.Sprintf("chat:%d", deliveryID)
+}
+
+// Publish sends the event to all subscribers of the delivery's chat
+func (cb *ChatBroker) Publish(ctx context.Context, deliveryID int, event *entity.ChatEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		cb.appLogger.Error(err)
+		return err
+	}
+
+	if err = cb.redisClient.Publish(ctx, chatChannel(deliveryID), raw).Err(); err != nil {
+		cb.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Subscribe subscribes to events of the delivery's chat, the returned
+// function must be called to unsubscribe and release the connection
+func (cb *ChatBroker) Subscribe(ctx context.Context, deliveryID int) (<-chan *entity.ChatEvent, func(), error) {
+	sub := cb.redisClient.Subscribe(ctx, chatChannel(deliveryID))
+
+	// Wait for confirmation so that no events are missed after return
+	if _, err := sub.Receive(ctx); err != nil {
+		cb.appLogger.Error(err)
+		sub.Close()
+		return nil, nil, err
+	}
+
+	events := make(chan *entity.ChatEvent)
+	go func() {
+		defer close(events)
+		for msg := range sub.Channel() {
+			event := &entity.ChatEvent{}
+			if err := json.Unmarshal([]byte(msg.Payload), event); err != nil {
+				cb.appLogger.Error(err)
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, func() { sub.Close() }, nil
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+)
+
+// PermissionCache is a struct that provides
+// all functions to cache users' permissions
+// in redis
+type PermissionCache struct {
+	redisClient *redis.Client
+	appLogger   *logger.Logger
+}
+
+func NewPermissionCache(rdb *redis.Client, l *logger.Logger) *PermissionCache {
+	return &PermissionCache{rdb, l}
+}
+
+// userPermissionsKey returns key of the user's cached permissions
+func userPermissionsKey(userID int) string {
+	return fmt.Sprintf("user_permissions:%d", userID)
+}
+
+// GetPermissions gets cached permissions of the user, false is returned on cache miss
+func (pc *PermissionCache) GetPermissions(ctx context.Context, userID int) ([]string, bool, error) {
+	data, err := pc.redisClient.Get(ctx, userPermissionsKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		pc.appLogger.Error(err)
+		return nil, false, err
+	}
+
+	permissions := make([]string, 0)
+	if err = json.Unmarshal(data, &permissions); err != nil {
+		pc.appLogger.Error(err)
+		return nil, false, err
+	}
+	return permissions, true, nil
+}
+
+// SetPermissions caches permissions of the user for the ttl
+func (pc *PermissionCache) SetPermissions(ctx context.Context, userID int, permissions []string, ttl time.Duration) error {
+	data, err := json.Marshal(permissions)
+	if err != nil {
+		pc.appLogger.Error(err)
+		return err
+	}
+
+	err = pc.redisClient.Set(ctx, userPermissionsKey(userID), data, ttl).Err()
+	if err != nil {
+		pc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeletePermissions invalidates cached permissions of the user
+func (pc *PermissionCache) DeletePermissions(ctx context.Context, userID int) error {
+	err := pc.redisClient.Del(ctx, userPermissionsKey(userID)).Err()
+	if err != nil {
+		pc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// useAttemptScript counts attempt of entering the code if it is not expired
+// and returns phone number, hash of the code and number of attempts
+var useAttemptScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return false
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+return {redis.call("HGET", KEYS[1], "phone"), redis.call("HGET", KEYS[1], "code_hash"), attempts}
+`)
+
+// PhoneCodeStore is a struct that provides
+// all functions to store one-time codes of
+// phone numbers' verification and their limits in redis
+type PhoneCodeStore struct {
+	redisClient *redis.Client
+	appLogger   *logger.Logger
+}
+
+func NewPhoneCodeStore(rdb *redis.Client, l *logger.Logger) *PhoneCodeStore {
+	return &PhoneCodeStore{rdb, l}
+}
+
+// phoneCodeKeys returns keys of the user's code, cool-down
+// between sending codes and counter of codes sent today
+func phoneCodeKeys(userID int) []string {
+	return []string{
+		fmt.Sprintf("phone_code:%d", userID),
+		fmt.Sprintf("phone_code:%d:cooldown", userID),
+		fmt.Sprintf("phone_code:%d:sends", userID),
+	}
+}
+
+// StartCooldown starts cool-down of sending codes to the user,
+// false is returned if the previous one is not over yet
+func (ps *PhoneCodeStore) StartCooldown(ctx context.Context, userID int, cooldown time.Duration) (bool, error) {
+	started, err := ps.redisClient.SetNX(ctx, phoneCodeKeys(userID)[1], 1, cooldown).Result()
+	if err != nil {
+		ps.appLogger.Error(err)
+		return false, err
+	}
+	return started, nil
+}
+
+// CountSend counts code sent to the user and returns number of codes sent within a day since the first one
+func (ps *PhoneCodeStore) CountSend(ctx context.Context, userID int) (int, error) {
+	key := phoneCodeKeys(userID)[2]
+	sends, err := ps.redisClient.Incr(ctx, key).Result()
+	if err != nil {
+		ps.appLogger.Error(err)
+		return 0, err
+	}
+
+	if sends == 1 {
+		if err = ps.redisClient.Expire(ctx, key, 24*time.Hour).Err(); err != nil {
+			ps.appLogger.Error(err)
+			return 0, err
+		}
+	}
+	return int(sends), nil
+}
+
+// SaveCode stores the code replacing the previous one of the user until the code expires
+func (ps *PhoneCodeStore) SaveCode(ctx context.Context, code *entity.PhoneCode, ttl time.Duration) error {
+	key := phoneCodeKeys(code.UserID)[0]
+	_, err := ps.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, "phone", code.Phone, "code_hash", code.CodeHash, "attempts", 0)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		ps.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UseAttempt counts attempt of entering the user's code and returns the code
+// with attempts made, nil is returned if the code is expired
+func (ps *PhoneCodeStore) UseAttempt(ctx context.Context, userID int) (*entity.PhoneCode, error) {
+	res, err := useAttemptScript.Run(ctx, ps.redisClient, phoneCodeKeys(userID)[:1]).Slice()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		ps.appLogger.Error(err)
+		return nil, err
+	}
+
+	phone, _ := res[0].(string)
+	codeHash, _ := res[1].(string)
+	attempts, _ := res[2].(int64)
+	return &entity.PhoneCode{
+		UserID:   userID,
+		Phone:    phone,
+		CodeHash: codeHash,
+		Attempts: int(attempts),
+	}, nil
+}
+
+// DeleteCode deletes the user's code
+func (ps *PhoneCodeStore) DeleteCode(ctx context.Context, userID int) error {
+	err := ps.redisClient.Del(ctx, phoneCodeKeys(userID)[0]).Err()
+	if err != nil {
+		ps.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// rotateScript replaces hash of the session's refresh token only if the current one
+// is presented and remembers the replaced hash to detect its reuse, it returns 1 on success.
+// The second factor's flag is not overwritten, it only gets the new expiration
+var rotateScript = redis.NewScript(`
+if redis.call("GET", KEYS[2]) ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[3], "PX", ARGV[4])
+redis.call("SET", KEYS[2], ARGV[2], "PX", ARGV[4])
+redis.call("SADD", KEYS[3], ARGV[1])
+redis.call("PEXPIRE", KEYS[3], ARGV[4])
+redis.call("PEXPIRE", KEYS[4], ARGV[4])
+return 1
+`)
+
+// twoFactorScript sets the second factor's flag of the session until the session expires,
+// it returns 0 if the session is not found
+var twoFactorScript = redis.NewScript(`
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	return 0
+end
+redis.call("SET", KEYS[4], "1", "PX", ttl)
+return 1
+`)
+
+// sessionRecord represents the session stored in redis,
+// user's id is kept since it is not marshalled for clients
+type sessionRecord struct {
+	*entity.Session
+	UserID int `json:"user_id"`
+}
+
+// SessionStore is a struct that provides
+// all functions to store users' sessions
+// and hashes of their refresh tokens in redis
+type SessionStore struct {
+	redisClient *redis.Client
+	appLogger   *logger.Logger
+}
+
+func NewSessionStore(rdb *redis.Client, l *logger.Logger) *SessionStore {
+	return &SessionStore{rdb, l}
+}
+
+// sessionKeys returns keys of the session, hash of its current refresh token,
+// hashes of the rotated ones and flag of the passed second factor. The flag is
+// kept apart from the session so that saving the session doesn't overwrite it
+func sessionKeys(sessionID string) []string {
+	return []string{
+		"session:" + sessionID,
+		"session:" + sessionID + ":refresh",
+		"session:" + sessionID + ":used",
+		"session:" + sessionID + ":two_factor",
+	}
+}
+
+// userSessionsKey returns key of the set of user's sessions' ids
+func userSessionsKey(userID int) string {
+	return fmt.Sprintf("user_sessions:%d", userID)
+}
+
+// CreateSession stores new session with hash of its refresh token until the session expires
+func (ss *SessionStore) CreateSession(ctx context.Context, session *entity.Session, refreshHash string) error {
+	raw, err := json.Marshal(&sessionRecord{session, session.UserID})
+	if err != nil {
+		ss.appLogger.Error(err)
+		return err
+	}
+
+	keys := sessionKeys(session.ID)
+	ttl := time.Until(session.ExpiresAt)
+	_, err = ss.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, keys[0], raw, ttl)
+		pipe.Set(ctx, keys[1], refreshHash, ttl)
+		if session.TwoFactor {
+			pipe.Set(ctx, keys[3], "1", ttl)
+		}
+		pipe.SAdd(ctx, userSessionsKey(session.UserID), session.ID)
+		return nil
+	})
+	if err != nil {
+		ss.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetSession gets the session by id, nil is returned if it is expired or revoked
+func (ss *SessionStore) GetSession(ctx context.Context, sessionID string) (*entity.Session, error) {
+	keys := sessionKeys(sessionID)
+	values, err := ss.redisClient.MGet(ctx, keys[0], keys[3]).Result()
+	if err != nil {
+		ss.appLogger.Error(err)
+		return nil, err
+	}
+	raw, ok := values[0].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	record := &sessionRecord{Session: &entity.Session{}}
+	if err = json.Unmarshal([]byte(raw), record); err != nil {
+		ss.appLogger.Error(err)
+		return nil, err
+	}
+	record.Session.UserID = record.UserID
+	record.Session.TwoFactor = record.Session.TwoFactor || values[1] != nil
+	return record.Session, nil
+}
+
+// RotateSession replaces the presented refresh token's hash with the new one and saves the session,
+// false is returned if the presented token is not the current one of the session
+func (ss *SessionStore) RotateSession(ctx context.Context, session *entity.Session, refreshHash, newRefreshHash string) (bool, error) {
+	raw, err := json.Marshal(&sessionRecord{session, session.UserID})
+	if err != nil {
+		ss.appLogger.Error(err)
+		return false, err
+	}
+
+	ttl := time.Until(session.ExpiresAt).Milliseconds()
+	rotated, err := rotateScript.Run(ctx, ss.redisClient, sessionKeys(session.ID), refreshHash, newRefreshHash, raw, ttl).Bool()
+	if err != nil {
+		ss.appLogger.Error(err)
+		return false, err
+	}
+	return rotated, nil
+}
+
+// MarkSessionTwoFactor remembers that the session has passed the second factor
+// until the session expires, the session itself is not changed so that concurrent
+// rotation of its refresh token can't be overwritten
+func (ss *SessionStore) MarkSessionTwoFactor(ctx context.Context, sessionID string) error {
+	marked, err := twoFactorScript.Run(ctx, ss.redisClient, sessionKeys(sessionID)).Bool()
+	if err != nil {
+		ss.appLogger.Error(err)
+		return err
+	}
+	if !marked {
+		err = fmt.Errorf("session is not found")
+		ss.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// IsRefreshHashUsed checks if the refresh token's hash has already been rotated in the session
+func (ss *SessionStore) IsRefreshHashUsed(ctx context.Context, sessionID, refreshHash string) (bool, error) {
+	used, err := ss.redisClient.SIsMember(ctx, sessionKeys(sessionID)[2], refreshHash).Result()
+	if err != nil {
+		ss.appLogger.Error(err)
+		return false, err
+	}
+	return used, nil
+}
+
+// GetSessions gets active sessions of the user, ids of the expired ones are removed from the user's set
+func (ss *SessionStore) GetSessions(ctx context.Context, userID int) ([]*entity.Session, error) {
+	ids, err := ss.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		ss.appLogger.Error(err)
+		return nil, err
+	}
+
+	results := make([]*entity.Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := ss.GetSession(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			if err = ss.redisClient.SRem(ctx, userSessionsKey(userID), id).Err(); err != nil {
+				ss.appLogger.Error(err)
+			}
+			continue
+		}
+		results = append(results, session)
+	}
+	return results, nil
+}
+
+// DeleteSession deletes the session of the user with its refresh tokens' hashes
+func (ss *SessionStore) DeleteSession(ctx context.Context, userID int, sessionID string) error {
+	_, err := ss.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKeys(sessionID)...)
+		pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+		return nil
+	})
+	if err != nil {
+		ss.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteSessions deletes all sessions of the user
+func (ss *SessionStore) DeleteSessions(ctx context.Context, userID int) error {
+	ids, err := ss.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		ss.appLogger.Error(err)
+		return err
+	}
+
+	keys := []string{userSessionsKey(userID)}
+	for _, id := range ids {
+		keys = append(keys, sessionKeys(id)...)
+	}
+
+	if err = ss.redisClient.Del(ctx, keys...).Err(); err != nil {
+		ss.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
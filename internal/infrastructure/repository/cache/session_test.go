@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSessionStore returns the store backed by in-memory redis
+func newTestSessionStore(t *testing.T) (*SessionStore, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	testLogger := logrus.New()
+	return NewSessionStore(rdb, logger.New(testLogger)), mr
+}
+
+func newTestSession(id string, userID int) *entity.Session {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &entity.Session{
+		ID:         id,
+		UserID:     userID,
+		UserAgent:  "curl/8.0",
+		IP:         "10.0.0.1",
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(time.Hour),
+	}
+}
+
+func TestSessionStore_RotateSession(t *testing.T) {
+	store, _ := newTestSessionStore(t)
+	ctx := context.Background()
+
+	session := newTestSession("s1", 1)
+	require.NoError(t, store.CreateSession(ctx, session, "hash1"))
+
+	got, err := store.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	require.Equal(t, session, got)
+
+	// Current token is rotated and remembered as used
+	session.IP = "10.0.0.2"
+	rotated, err := store.RotateSession(ctx, session, "hash1", "hash2")
+	require.NoError(t, err)
+	require.True(t, rotated)
+
+	got, err = store.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.2", got.IP)
+
+	used, err := store.IsRefreshHashUsed(ctx, "s1", "hash1")
+	require.NoError(t, err)
+	require.True(t, used)
+
+	used, err = store.IsRefreshHashUsed(ctx, "s1", "hash2")
+	require.NoError(t, err)
+	require.False(t, used)
+
+	// Rotated and unknown tokens can't be rotated again
+	rotated, err = store.RotateSession(ctx, session, "hash1", "hash3")
+	require.NoError(t, err)
+	require.False(t, rotated)
+
+	rotated, err = store.RotateSession(ctx, session, "unknown", "hash3")
+	require.NoError(t, err)
+	require.False(t, rotated)
+}
+
+func TestSessionStore_Expiry(t *testing.T) {
+	store, mr := newTestSessionStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.CreateSession(ctx, newTestSession("s1", 1), "hash1"))
+	longer := newTestSession("s2", 1)
+	longer.ExpiresAt = longer.ExpiresAt.Add(time.Hour)
+	require.NoError(t, store.CreateSession(ctx, longer, "hash2"))
+
+	mr.FastForward(time.Hour + time.Minute)
+
+	got, err := store.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	rotated, err := store.RotateSession(ctx, newTestSession("s1", 1), "hash1", "hash3")
+	require.NoError(t, err)
+	require.False(t, rotated)
+
+	// Expired session is removed from the user's set when sessions are listed
+	sessions, err := store.GetSessions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, "s2", sessions[0].ID)
+
+	ids, err := mr.SMembers(userSessionsKey(1))
+	require.NoError(t, err)
+	require.Equal(t, []string{"s2"}, ids)
+}
+
+func TestSessionStore_DeleteSessions(t *testing.T) {
+	store, mr := newTestSessionStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.CreateSession(ctx, newTestSession("s1", 1), "hash1"))
+	require.NoError(t, store.CreateSession(ctx, newTestSession("s2", 1), "hash2"))
+	require.NoError(t, store.CreateSession(ctx, newTestSession("s3", 2), "hash3"))
+
+	rotated, err := store.RotateSession(ctx, newTestSession("s1", 1), "hash1", "hash4")
+	require.NoError(t, err)
+	require.True(t, rotated)
+
+	require.NoError(t, store.DeleteSessions(ctx, 1))
+
+	// Sessions of the user are deleted with hashes of their tokens
+	for _, id := range []string{"s1", "s2"} {
+		for _, key := range sessionKeys(id) {
+			require.False(t, mr.Exists(key), key)
+		}
+	}
+	require.False(t, mr.Exists(userSessionsKey(1)))
+
+	sessions, err := store.GetSessions(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+}
+
+func TestSessionStore_MarkSessionTwoFactor(t *testing.T) {
+	store, mr := newTestSessionStore(t)
+	ctx := context.Background()
+
+	session := newTestSession("s1", 1)
+	require.NoError(t, store.CreateSession(ctx, session, "hash1"))
+	require.NoError(t, store.MarkSessionTwoFactor(ctx, "s1"))
+
+	// Rotation with the session read before the mark keeps the flag and the refresh token
+	rotated, err := store.RotateSession(ctx, session, "hash1", "hash2")
+	require.NoError(t, err)
+	require.True(t, rotated)
+
+	got, err := store.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	require.True(t, got.TwoFactor)
+
+	used, err := store.IsRefreshHashUsed(ctx, "s1", "hash2")
+	require.NoError(t, err)
+	require.False(t, used)
+
+	// Flag expires with the session
+	require.Equal(t, mr.TTL(sessionKeys("s1")[0]), mr.TTL(sessionKeys("s1")[3]))
+
+	require.Error(t, store.MarkSessionTwoFactor(ctx, "unknown"))
+	require.False(t, mr.Exists(sessionKeys("unknown")[3]))
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+)
+
+// Suggestions of the same input are shared between users while typing,
+// addresses rarely change so they can be stored for a while
+const suggestTTL = 10 * time.Minute
+
+// SuggestCache is a struct that provides
+// all functions to store address suggestions in redis
+type SuggestCache struct {
+	redisClient *redis.Client
+	appLogger   *logger.Logger
+}
+
+func NewSuggestCache(rdb *redis.Client, l *logger.Logger) *SuggestCache {
+	return &SuggestCache{rdb, l}
+}
+
+// GetSuggestions gets suggestions stored by the key,
+// cache miss and any redis error are reported as not found
+func (sc *SuggestCache) GetSuggestions(ctx context.Context, key string) ([]*dto.SuggestionResponse, bool) {
+	raw, err := sc.redisClient.Get(ctx, "suggest:"+key).Bytes()
+	if err == redis.Nil {
+		return nil, false
+	}
+	if err != nil {
+		sc.appLogger.Error(err)
+		return nil, false
+	}
+
+	var suggestions []*dto.SuggestionResponse
+	if err = json.Unmarshal(raw, &suggestions); err != nil {
+		sc.appLogger.Error(err)
+		return nil, false
+	}
+	return suggestions, true
+}
+
+// SetSuggestions stores suggestions by the key, failure is only logged
+// since the suggestions can always be requested again
+func (sc *SuggestCache) SetSuggestions(ctx context.Context, key string, suggestions []*dto.SuggestionResponse) {
+	raw, err := json.Marshal(suggestions)
+	if err != nil {
+		sc.appLogger.Error(err)
+		return
+	}
+
+	if err = sc.redisClient.Set(ctx, "suggest:"+key, raw, suggestTTL).Err(); err != nil {
+		sc.appLogger.Error(err)
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+)
+
+// TwoFactorStore is a struct that provides
+// all functions to count attempts
+// of passing the second factor in redis
+type TwoFactorStore struct {
+	redisClient *redis.Client
+	appLogger   *logger.Logger
+}
+
+func NewTwoFactorStore(rdb *redis.Client, l *logger.Logger) *TwoFactorStore {
+	return &TwoFactorStore{rdb, l}
+}
+
+// twoFactorAttemptsKey returns key of the counter of the user's attempts
+func twoFactorAttemptsKey(userID int) string {
+	return fmt.Sprintf("two_factor_failures:%d", userID)
+}
+
+// countAttemptScript counts the attempt and starts the window with the first one,
+// it returns number of attempts within the window
+var countAttemptScript = redis.NewScript(`
+local attempts = redis.call("INCR", KEYS[1])
+if attempts == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return attempts
+`)
+
+// CountAttempt counts unsuccessful or ongoing attempt of the user and returns number
+// of attempts within the window since the first one, the counter is reset by success
+func (ts *TwoFactorStore) CountAttempt(ctx context.Context, userID int, window time.Duration) (int, error) {
+	attempts, err := countAttemptScript.Run(ctx, ts.redisClient, []string{twoFactorAttemptsKey(userID)},
+		window.Milliseconds()).Int()
+	if err != nil {
+		ts.appLogger.Error(err)
+		return 0, err
+	}
+	return attempts, nil
+}
+
+// ResetAttempts resets counter of the user's attempts
+func (ts *TwoFactorStore) ResetAttempts(ctx context.Context, userID int) error {
+	err := ts.redisClient.Del(ctx, twoFactorAttemptsKey(userID)).Err()
+	if err != nil {
+		ts.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// AccountRepo is a struct that provides
+// all functions to execute SQL queries
+// related to users' email and phone verification and passwords
+type AccountRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewAccountRepo(db *sql.DB, l *logger.Logger) *AccountRepo {
+	return &AccountRepo{db, l}
+}
+
+// CreateUserToken creates a new token record and invalidates unused tokens
+// of the user with the same purpose, so only the latest sent token works
+func (ar *AccountRepo) CreateUserToken(ctx context.Context, token *entity.UserToken) error {
+	tx, err := ar.Begin()
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query1 := `
+		UPDATE user_tokens
+		SET used_at = now()
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`
+	_, err = tx.ExecContext(ctx, query1, token.UserID, token.Purpose)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+
+	query2 := `
+		INSERT INTO user_tokens(user_id, purpose, token_hash, expires_at, email)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, query2, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt,
+		token.Email).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// RenewUserToken replaces hash of the token if it is not used or expired yet, the token
+// is returned without the hash. Nil is returned if the token is no longer valid
+func (ar *AccountRepo) RenewUserToken(ctx context.Context, id int, tokenHash string) (*entity.UserToken, error) {
+	query := `
+		UPDATE user_tokens
+		SET token_hash = $2
+		WHERE id = $1 AND used_at IS NULL AND expires_at > now()
+		RETURNING id, user_id, purpose, expires_at, COALESCE(email, ''), created_at
+	`
+	token := &entity.UserToken{}
+	err := ar.QueryRowContext(ctx, query, id, tokenHash).Scan(&token.ID, &token.UserID, &token.Purpose,
+		&token.ExpiresAt, &token.Email, &token.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	return token, nil
+}
+
+// VerifyEmail uses the email verification token and marks email of its user as verified,
+// id of the user is returned
+func (ar *AccountRepo) VerifyEmail(ctx context.Context, tokenHash string) (int, error) {
+	tx, err := ar.Begin()
+	if err != nil {
+		ar.appLogger.Error(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	userID, err := useUserToken(ctx, tx, entity.TokenEmailVerification, tokenHash)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return 0, err
+	}
+
+	query := `UPDATE meta SET email_verified = true WHERE user_id = $1`
+	_, err = tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		ar.appLogger.Error(err)
+		return 0, err
+	}
+	return userID, nil
+}
+
+// ChangeEmail uses the email change token, replaces email of its user with the new one
+// and marks it as verified. The recorded change is returned
+func (ar *AccountRepo) ChangeEmail(ctx context.Context, tokenHash string) (*entity.UserChange, error) {
+	tx, err := ar.Begin()
+	if err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query1 := `
+		UPDATE user_tokens
+		SET used_at = now()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id, email
+	`
+	change := &entity.UserChange{Field: entity.FieldEmail}
+	err = tx.QueryRowContext(ctx, query1, tokenHash, entity.TokenEmailChange).Scan(&change.UserID, &change.NewValue)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("token is invalid or expired")
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+
+	current, err := lockProfile(ctx, tx, change.UserID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	change.OldValue = current[entity.FieldEmail]
+	change.ChangedBy = change.UserID
+
+	err = changeProfileField(ctx, tx, change)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+
+	query2 := `UPDATE meta SET email_verified = true WHERE user_id = $1`
+	_, err = tx.ExecContext(ctx, query2, change.UserID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	return change, nil
+}
+
+// ResetPassword uses the password reset token and sets new password of its user,
+// id of the user is returned
+func (ar *AccountRepo) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (int, error) {
+	tx, err := ar.Begin()
+	if err != nil {
+		ar.appLogger.Error(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	userID, err := useUserToken(ctx, tx, entity.TokenPasswordReset, tokenHash)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return 0, err
+	}
+
+	query := `UPDATE users SET hash_password = $1 WHERE id = $2`
+	_, err = tx.ExecContext(ctx, query, passwordHash, userID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		ar.appLogger.Error(err)
+		return 0, err
+	}
+	return userID, nil
+}
+
+// UpdatePassword sets new password of the user
+func (ar *AccountRepo) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	query := `UPDATE users SET hash_password = $1 WHERE id = $2`
+	result, err := ar.ExecContext(ctx, query, passwordHash, userID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("user is not found")
+		ar.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// VerifyPhone sets the user's phone number in E.164 format and marks it as verified,
+// the number rewritten to E.164 is recorded to the profile's history
+func (ar *AccountRepo) VerifyPhone(ctx context.Context, userID int, phone string) error {
+	tx, err := ar.Begin()
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := lockProfile(ctx, tx, userID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+
+	if current[entity.FieldPhoneNumber] != phone {
+		err = changeProfileField(ctx, tx, &entity.UserChange{
+			UserID:    userID,
+			Field:     entity.FieldPhoneNumber,
+			OldValue:  current[entity.FieldPhoneNumber],
+			NewValue:  phone,
+			ChangedBy: userID,
+		})
+		if err != nil {
+			ar.appLogger.Error(err)
+			return err
+		}
+	}
+
+	query := `UPDATE meta SET phone_verified = true WHERE user_id = $1`
+	_, err = tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// useUserToken marks the token as used if it is not used or expired yet and returns id of its user
+func useUserToken(ctx context.Context, tx *sql.Tx, purpose, tokenHash string) (int, error) {
+	query := `
+		UPDATE user_tokens
+		SET used_at = now()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id
+	`
+	var userID int
+	err := tx.QueryRowContext(ctx, query, tokenHash, purpose).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("token is invalid or expired")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
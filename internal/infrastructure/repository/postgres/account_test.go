@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountRepo_ResetPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewAccountRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name      string
+		tokenHash string
+		rows      *sqlmock.Rows
+		want      int
+		error     error
+	}{
+		{
+			name:      "token is used",
+			tokenHash: "valid",
+			rows:      sqlmock.NewRows([]string{"user_id"}).AddRow(3),
+			want:      3,
+		},
+		{
+			name:      "token is used or expired",
+			tokenHash: "expired",
+			rows:      sqlmock.NewRows([]string{"user_id"}),
+			error:     fmt.Errorf("token is invalid or expired"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				UPDATE user_tokens
+				SET used_at = now()
+				WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+				RETURNING user_id
+			`)).
+				WithArgs(tt.tokenHash, entity.TokenPasswordReset).
+				WillReturnRows(tt.rows)
+			if tt.error == nil {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET hash_password = $1 WHERE id = $2`)).
+					WithArgs("hash", tt.want).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			got, err := repo.ResetPassword(context.Background(), tt.tokenHash, "hash")
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccountRepo_UpdatePassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewAccountRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name   string
+		userID int
+		result sql.Result
+		error  error
+	}{
+		{
+			name:   "password is updated",
+			userID: 1,
+			result: sqlmock.NewResult(0, 1),
+		},
+		{
+			name:   "user is not found",
+			userID: 2,
+			result: sqlmock.NewResult(0, 0),
+			error:  fmt.Errorf("user is not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET hash_password = $1 WHERE id = $2`)).
+				WithArgs("hash", tt.userID).
+				WillReturnResult(tt.result)
+
+			err := repo.UpdatePassword(context.Background(), tt.userID, "hash")
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccountRepo_ChangeEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewAccountRepo(db, logger.New(testLogger))
+
+	now := time.Now()
+	tests := []struct {
+		name      string
+		updateErr error
+		want      *entity.UserChange
+		error     error
+	}{
+		{
+			name: "email is changed",
+			want: &entity.UserChange{
+				ID:        5,
+				UserID:    3,
+				Field:     entity.FieldEmail,
+				OldValue:  "old@yandex.ru",
+				NewValue:  "new@yandex.ru",
+				ChangedBy: 3,
+				ChangedAt: now,
+			},
+		},
+		{
+			name:      "email is taken by another user",
+			updateErr: &pq.Error{Code: "23505"},
+			error:     fmt.Errorf("email is already taken"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				UPDATE user_tokens
+				SET used_at = now()
+				WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+				RETURNING user_id, email
+			`)).
+				WithArgs("valid", entity.TokenEmailChange).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id", "email"}).AddRow(3, "new@yandex.ru"))
+			mock.ExpectQuery(regexp.QuoteMeta(lockProfileQuery)).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(profileColumns).
+					AddRow("Иванов", "Иван", "old@yandex.ru", "+79157650030", 1, ""))
+			update := mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET email = $1 WHERE id = $2`)).
+				WithArgs("new@yandex.ru", 3)
+			if tt.updateErr == nil {
+				update.WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(insertUserChangeQuery)).
+					WithArgs(3, entity.FieldEmail, "old@yandex.ru", "new@yandex.ru", 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "changed_at"}).AddRow(5, now))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE meta SET email_verified = true WHERE user_id = $1`)).
+					WithArgs(3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				update.WillReturnError(tt.updateErr)
+				mock.ExpectRollback()
+			}
+
+			got, err := repo.ChangeEmail(context.Background(), "valid")
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.Nil(t, deep.Equal(tt.want, got))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccountRepo_VerifyPhone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewAccountRepo(db, logger.New(testLogger))
+
+	now := time.Now()
+	tests := []struct {
+		name    string
+		current string
+	}{
+		{
+			name:    "number is rewritten to E.164 and recorded",
+			current: "8 (915) 765-00-30",
+		},
+		{
+			name:    "number in E.164 is kept",
+			current: "+79157650030",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(lockProfileQuery)).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(profileColumns).
+					AddRow("Иванов", "Иван", "ivanov@yandex.ru", tt.current, 1, ""))
+			if tt.current != "+79157650030" {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET phone_number = $1 WHERE id = $2`)).
+					WithArgs("+79157650030", 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE meta SET phone_verified = false WHERE user_id = $1`)).
+					WithArgs(3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(insertUserChangeQuery)).
+					WithArgs(3, entity.FieldPhoneNumber, tt.current, "+79157650030", 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "changed_at"}).AddRow(5, now))
+			}
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE meta SET phone_verified = true WHERE user_id = $1`)).
+				WithArgs(3).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			err := repo.VerifyPhone(context.Background(), 3, "+79157650030")
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
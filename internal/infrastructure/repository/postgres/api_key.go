@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// APIKeyRepo is a struct that provides
+// all functions to execute SQL queries
+// related to clients' api keys
+type APIKeyRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewAPIKeyRepo(db *sql.DB, l *logger.Logger) *APIKeyRepo {
+	return &APIKeyRepo{db, l}
+}
+
+// CreateAPIKey creates a new api key record and attaches its id to the key
+func (ar *APIKeyRepo) CreateAPIKey(ctx context.Context, key *entity.APIKey) error {
+	query := `
+		INSERT INTO api_keys(user_id, organization_id, name, prefix, key_hash, scopes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err := ar.QueryRowContext(ctx, query, key.UserID, key.OrgID, key.Name, key.Prefix, key.KeyHash,
+		pq.Array(key.Scopes)).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetAPIKeys fetches api keys of the user which are not revoked
+func (ar *APIKeyRepo) GetAPIKeys(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	query := `
+		SELECT id, user_id, organization_id, name, prefix, key_hash, scopes, last_used_at, created_at, revoked_at
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY id
+	`
+	rows, err := ar.QueryContext(ctx, query, userID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.APIKey, 0)
+	for rows.Next() {
+		result, err := scanAPIKey(rows)
+		if err != nil {
+			ar.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetAPIKeyByPrefix fetches the api key by its public part, revoked keys are fetched too
+func (ar *APIKeyRepo) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	query := `
+		SELECT id, user_id, organization_id, name, prefix, key_hash, scopes, last_used_at, created_at, revoked_at
+		FROM api_keys
+		WHERE prefix = $1
+	`
+	key, err := scanAPIKey(ar.QueryRowContext(ctx, query, prefix))
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("api key is not found")
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		ar.appLogger.Error(err)
+		return nil, err
+	}
+	return key, nil
+}
+
+// RotateAPIKey replaces the user's key which is not revoked with the new one keeping its settings
+func (ar *APIKeyRepo) RotateAPIKey(ctx context.Context, key *entity.APIKey) error {
+	query := `
+		UPDATE api_keys
+		SET prefix = $1, key_hash = $2, last_used_at = NULL
+		WHERE id = $3 AND user_id = $4 AND revoked_at IS NULL
+		RETURNING organization_id, name, scopes, created_at
+	`
+	var orgID sql.NullInt64
+	err := ar.QueryRowContext(ctx, query, key.Prefix, key.KeyHash, key.ID, key.UserID).
+		Scan(&orgID, &key.Name, pq.Array(&key.Scopes), &key.CreatedAt)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("api key is not found")
+		ar.appLogger.Error(err)
+		return err
+	}
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	if orgID.Valid {
+		id := int(orgID.Int64)
+		key.OrgID = &id
+	}
+	return nil
+}
+
+// RevokeAPIKey revokes the user's key, revoked keys are kept for audit
+func (ar *APIKeyRepo) RevokeAPIKey(ctx context.Context, userID, keyID int) error {
+	query := `UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := ar.ExecContext(ctx, query, keyID, userID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("api key is not found")
+		ar.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// TouchAPIKey records use of the key, time is updated at most once a minute
+func (ar *APIKeyRepo) TouchAPIKey(ctx context.Context, keyID int) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = now()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+	`
+	_, err := ar.ExecContext(ctx, query, keyID)
+	if err != nil {
+		ar.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// scanAPIKey scans the api key from the row
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (*entity.APIKey, error) {
+	key := &entity.APIKey{}
+	var orgID sql.NullInt64
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.UserID, &orgID, &key.Name, &key.Prefix, &key.KeyHash, pq.Array(&key.Scopes),
+		&lastUsedAt, &key.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if orgID.Valid {
+		id := int(orgID.Int64)
+		key.OrgID = &id
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepo_GetAPIKeyByPrefix(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewAPIKeyRepo(db, logger.New(testLogger))
+
+	columns := []string{"id", "user_id", "organization_id", "name", "prefix", "key_hash", "scopes",
+		"last_used_at", "created_at", "revoked_at"}
+	orgID := 5
+	createdAt := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		prefix string
+		rows   *sqlmock.Rows
+		want   *entity.APIKey
+		error  error
+	}{
+		{
+			name:   "api key is found",
+			prefix: "trk_0a1b2c3d4e5f",
+			rows: sqlmock.NewRows(columns).
+				AddRow(1, 3, nil, "shop", "trk_0a1b2c3d4e5f", "hash", []byte("{deliveries:create,deliveries:read}"),
+					nil, createdAt, nil),
+			want: &entity.APIKey{
+				ID:        1,
+				UserID:    3,
+				Name:      "shop",
+				Prefix:    "trk_0a1b2c3d4e5f",
+				KeyHash:   "hash",
+				Scopes:    []string{entity.ScopeDeliveriesCreate, entity.ScopeDeliveriesRead},
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name:   "api key of the organization is found",
+			prefix: "trk_0a1b2c3d4e61",
+			rows: sqlmock.NewRows(columns).
+				AddRow(3, 3, orgID, "erp", "trk_0a1b2c3d4e61", "hash", []byte("{deliveries:create}"),
+					nil, createdAt, nil),
+			want: &entity.APIKey{
+				ID:        3,
+				UserID:    3,
+				OrgID:     &orgID,
+				Name:      "erp",
+				Prefix:    "trk_0a1b2c3d4e61",
+				KeyHash:   "hash",
+				Scopes:    []string{entity.ScopeDeliveriesCreate},
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name:   "revoked api key is found",
+			prefix: "trk_0a1b2c3d4e60",
+			rows: sqlmock.NewRows(columns).
+				AddRow(2, 3, nil, "old", "trk_0a1b2c3d4e60", "hash", []byte("{price:estimate}"),
+					createdAt, createdAt, createdAt),
+			want: &entity.APIKey{
+				ID:         2,
+				UserID:     3,
+				Name:       "old",
+				Prefix:     "trk_0a1b2c3d4e60",
+				KeyHash:    "hash",
+				Scopes:     []string{entity.ScopePriceEstimate},
+				LastUsedAt: &createdAt,
+				CreatedAt:  createdAt,
+				RevokedAt:  &createdAt,
+			},
+		},
+		{
+			name:   "api key is not found",
+			prefix: "trk_ffffffffffff",
+			rows:   sqlmock.NewRows(columns),
+			error:  fmt.Errorf("api key is not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				SELECT id, user_id, organization_id, name, prefix, key_hash, scopes, last_used_at, created_at, revoked_at
+				FROM api_keys
+				WHERE prefix = $1
+			`)).
+				WithArgs(tt.prefix).
+				WillReturnRows(tt.rows)
+
+			got, err := repo.GetAPIKeyByPrefix(context.Background(), tt.prefix)
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.Nil(t, deep.Equal(tt.want, got))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyRepo_RevokeAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewAPIKeyRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name   string
+		userID int
+		keyID  int
+		rows   int64
+		error  error
+	}{
+		{
+			name:   "api key is revoked",
+			userID: 3,
+			keyID:  1,
+			rows:   1,
+		},
+		{
+			name:   "api key of another user",
+			userID: 4,
+			keyID:  1,
+			rows:   0,
+			error:  fmt.Errorf("api key is not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(regexp.QuoteMeta(
+				`UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+			)).
+				WithArgs(tt.keyID, tt.userID).
+				WillReturnResult(sqlmock.NewResult(0, tt.rows))
+
+			err := repo.RevokeAPIKey(context.Background(), tt.userID, tt.keyID)
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ChatRepo is a struct that provides
+// all functions to execute SQL queries
+// related to deliveries' chats
+type ChatRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewChatRepo(db *sql.DB, l *logger.Logger) *ChatRepo {
+	return &ChatRepo{db, l}
+}
+
+// GetChatChannel fetches participants and state of the delivery's chat
+func (cr *ChatRepo) GetChatChannel(ctx context.Context, deliveryID int) (*entity.ChatChannel, error) {
+	query := `
+		SELECT deliveries.id, client_id, courier_id, status_id, delivery_proofs.completed_at
+		FROM deliveries
+		LEFT JOIN delivery_proofs ON deliveries.id = delivery_proofs.delivery_id
+		WHERE deliveries.id = $1
+	`
+	channel := &entity.ChatChannel{}
+	var courierID sql.NullInt64
+	var completedAt sql.NullTime
+	err := cr.QueryRowContext(ctx, query, deliveryID).Scan(&channel.DeliveryID, &channel.ClientID, &courierID,
+		&channel.StatusID, &completedAt)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("delivery is not found")
+		cr.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		cr.appLogger.Error(err)
+		return nil, err
+	}
+
+	channel.CourierID = int(courierID.Int64)
+	if completedAt.Valid {
+		channel.CompletedAt = &completedAt.Time
+	}
+	return channel, nil
+}
+
+// CreateMessage creates a new message record and attaches its id to the message
+func (cr *ChatRepo) CreateMessage(ctx context.Context, msg *entity.ChatMessage) error {
+	query := `
+		INSERT INTO chat_messages(delivery_id, sender_id, text)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err := cr.QueryRowContext(ctx, query, msg.DeliveryID, msg.SenderID, msg.Text).Scan(&msg.ID, &msg.CreatedAt)
+	if err != nil {
+		cr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetMessages fetches messages of the delivery's chat older than beforeID,
+// the newest go first, beforeID 0 stands for the latest messages
+func (cr *ChatRepo) GetMessages(ctx context.Context, deliveryID, beforeID, limit int) ([]*entity.ChatMessage, error) {
+	query := `
+		SELECT id, delivery_id, sender_id, text, read_at, created_at
+		FROM chat_messages
+		WHERE delivery_id = $1 AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+	rows, err := cr.QueryContext(ctx, query, deliveryID, beforeID, limit)
+	if err != nil {
+		cr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.ChatMessage, 0)
+	for rows.Next() {
+		result := &entity.ChatMessage{}
+		var readAt sql.NullTime
+		err = rows.Scan(&result.ID, &result.DeliveryID, &result.SenderID, &result.Text, &readAt, &result.CreatedAt)
+		if err != nil {
+			cr.appLogger.Error(err)
+			return nil, err
+		}
+		if readAt.Valid {
+			result.ReadAt = &readAt.Time
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		cr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// MarkRead marks messages of the other participant up to the message as read
+// and returns the number of newly read messages
+func (cr *ChatRepo) MarkRead(ctx context.Context, deliveryID, readerID, upToID int) (int, error) {
+	query := `
+		UPDATE chat_messages
+		SET read_at = now()
+		WHERE delivery_id = $1 AND sender_id <> $2 AND id <= $3 AND read_at IS NULL
+	`
+	result, err := cr.ExecContext(ctx, query, deliveryID, readerID, upToID)
+	if err != nil {
+		cr.appLogger.Error(err)
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		cr.appLogger.Error(err)
+		return 0, err
+	}
+	return int(rows), nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatRepo_GetMessages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewChatRepo(db, logger.New(testLogger))
+
+	columns := []string{"id", "delivery_id", "sender_id", "text", "read_at", "created_at"}
+	sentAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	readAt := sentAt.Add(time.Minute)
+
+	tests := []struct {
+		name     string
+		beforeID int
+		rows     *sqlmock.Rows
+		want     []*entity.ChatMessage
+	}{
+		{
+			name:     "latest messages",
+			beforeID: 0,
+			rows: sqlmock.NewRows(columns).
+				AddRow(12, 1, 3, "I'm at the entrance", nil, sentAt.Add(time.Minute)).
+				AddRow(11, 1, 2, "Call me please", readAt, sentAt),
+			want: []*entity.ChatMessage{
+				{ID: 12, DeliveryID: 1, SenderID: 3, Text: "I'm at the entrance", CreatedAt: sentAt.Add(time.Minute)},
+				{ID: 11, DeliveryID: 1, SenderID: 2, Text: "Call me please", ReadAt: &readAt, CreatedAt: sentAt},
+			},
+		},
+		{
+			name:     "no older messages",
+			beforeID: 11,
+			rows:     sqlmock.NewRows(columns),
+			want:     []*entity.ChatMessage{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				SELECT id, delivery_id, sender_id, text, read_at, created_at
+				FROM chat_messages
+				WHERE delivery_id = $1 AND ($2 = 0 OR id < $2)
+				ORDER BY id DESC
+				LIMIT $3
+			`)).
+				WithArgs(1, tt.beforeID, 20).
+				WillReturnRows(tt.rows)
+
+			got, err := repo.GetMessages(context.Background(), 1, tt.beforeID, 20)
+			require.NoError(t, err)
+			require.Nil(t, deep.Equal(tt.want, got))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
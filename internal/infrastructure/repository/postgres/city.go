@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// CityRepo is a struct that provides
+// all functions to execute SQL queries
+// related to cities
+type CityRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewCityRepo(db *sql.DB, l *logger.Logger) *CityRepo {
+	return &CityRepo{db, l}
+}
+
+// CreateCity creates a new city record and attaches its id to the city
+func (cr *CityRepo) CreateCity(ctx context.Context, city *entity.City) error {
+	query := `
+		INSERT INTO cities(name, geocoder_id, timezone, currency, tariff_multiplier, enabled_types, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	err := cr.QueryRowContext(ctx, query, city.Name, city.GeocoderID, city.Timezone, city.Currency,
+		city.TariffMultiplier, pq.Array(city.EnabledTypes), city.IsActive).Scan(&city.ID)
+	if err != nil {
+		cr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateCity updates settings of the city
+func (cr *CityRepo) UpdateCity(ctx context.Context, city *entity.City) error {
+	query := `
+		UPDATE cities
+		SET name = $1, geocoder_id = $2, timezone = $3, currency = $4,
+			tariff_multiplier = $5, enabled_types = $6, is_active = $7
+		WHERE id = $8
+	`
+	result, err := cr.ExecContext(ctx, query, city.Name, city.GeocoderID, city.Timezone, city.Currency,
+		city.TariffMultiplier, pq.Array(city.EnabledTypes), city.IsActive, city.ID)
+	if err != nil {
+		cr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		cr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("city is not found")
+		cr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetCities fetches all cities ordered by id
+func (cr *CityRepo) GetCities(ctx context.Context) ([]*entity.City, error) {
+	query := `
+		SELECT id, name, geocoder_id, timezone, currency, tariff_multiplier, enabled_types, is_active
+		FROM cities
+		ORDER BY id
+	`
+	rows, err := cr.QueryContext(ctx, query)
+	if err != nil {
+		cr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.City, 0)
+	for rows.Next() {
+		result := &entity.City{}
+		err = rows.Scan(&result.ID, &result.Name, &result.GeocoderID, &result.Timezone, &result.Currency,
+			&result.TariffMultiplier, pq.Array(&result.EnabledTypes), &result.IsActive)
+		if err != nil {
+			cr.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		cr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetCityByID fetches the city by id
+func (cr *CityRepo) GetCityByID(ctx context.Context, cityID int) (*entity.City, error) {
+	query := `
+		SELECT id, name, geocoder_id, timezone, currency, tariff_multiplier, enabled_types, is_active
+		FROM cities
+		WHERE id = $1
+	`
+	city := &entity.City{}
+	err := cr.QueryRowContext(ctx, query, cityID).Scan(&city.ID, &city.Name, &city.GeocoderID, &city.Timezone,
+		&city.Currency, &city.TariffMultiplier, pq.Array(&city.EnabledTypes), &city.IsActive)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("city is not found")
+		cr.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		cr.appLogger.Error(err)
+		return nil, err
+	}
+	return city, nil
+}
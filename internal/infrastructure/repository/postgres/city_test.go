@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCityRepo_GetCityByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewCityRepo(db, logger.New(testLogger))
+
+	columns := []string{"id", "name", "geocoder_id", "timezone", "currency", "tariff_multiplier", "enabled_types", "is_active"}
+
+	tests := []struct {
+		name   string
+		cityID int
+		rows   *sqlmock.Rows
+		want   *entity.City
+		error  error
+	}{
+		{
+			name:   "city is found",
+			cityID: 1,
+			rows: sqlmock.NewRows(columns).
+				AddRow(1, "Moscow", "4504222397630173", "Europe/Moscow", "RUB", 1., []byte("{1,2,3,4,5}"), true),
+			want: &entity.City{
+				ID:               1,
+				Name:             "Moscow",
+				GeocoderID:       "4504222397630173",
+				Timezone:         "Europe/Moscow",
+				Currency:         "RUB",
+				TariffMultiplier: 1,
+				EnabledTypes:     []int64{1, 2, 3, 4, 5},
+				IsActive:         true,
+			},
+		},
+		{
+			name:   "city is not found",
+			cityID: 2,
+			rows:   sqlmock.NewRows(columns),
+			error:  fmt.Errorf("city is not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				SELECT id, name, geocoder_id, timezone, currency, tariff_multiplier, enabled_types, is_active
+				FROM cities
+				WHERE id = $1
+			`)).
+				WithArgs(tt.cityID).
+				WillReturnRows(tt.rows)
+
+			got, err := repo.GetCityByID(context.Background(), tt.cityID)
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.Nil(t, deep.Equal(tt.want, got))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
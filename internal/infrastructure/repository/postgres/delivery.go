@@ -44,24 +44,56 @@ func (dr *DeliveryRepo) CreateDelivery(ctx context.Context, delivery *entity.Del
 	}
 
 	q2 := `
-	INSERT INTO deliveries(client_id, status_id, type_id, geo_id, price, has_loader)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	INSERT INTO deliveries(client_id, status_id, type_id, geo_id, price, has_loader, cargo_weight, cargo_volume,
+		pickup_from, pickup_to, dropoff_from, dropoff_to, zone_id, city_id, organization_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	RETURNING id
 	`
 
-	res, err := tx.ExecContext(ctx, q2, delivery.ClientID, 1, delivery.TypeID, lastInsertID, delivery.Price, delivery.HasLoader)
+	pickupFrom, pickupTo := windowToNullTime(delivery.PickupWindow)
+	dropoffFrom, dropoffTo := windowToNullTime(delivery.DropoffWindow)
+	err = tx.QueryRowContext(ctx, q2, delivery.ClientID, 1, delivery.TypeID, lastInsertID, delivery.Price, delivery.HasLoader,
+		delivery.CargoWeight, delivery.CargoVolume, pickupFrom, pickupTo, dropoffFrom, dropoffTo, delivery.ZoneID, delivery.CityID,
+		delivery.OrgID).Scan(&delivery.ID)
 	if err != nil {
 		dr.appLogger.Error(err)
 		return err
 	}
+	delivery.StatusID = 1
 
-	rows, err := res.RowsAffected()
+	q3 := `INSERT INTO delivery_proofs(delivery_id, pin) VALUES ($1, $2)`
+	_, err = tx.ExecContext(ctx, q3, delivery.ID, delivery.PIN)
 	if err != nil {
 		dr.appLogger.Error(err)
 		return err
 	}
 
-	if rows != 1 {
-		err := fmt.Errorf("expected to affect 1 row, affected %d", rows)
+	q4 := `
+	INSERT INTO delivery_contacts(delivery_id, stop_type, name, phone_number, entrance, floor, intercom, notes)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	contacts := []struct {
+		stopType string
+		contact  *entity.StopContact
+	}{
+		{entity.StopPickup, delivery.PickupContact},
+		{entity.StopDropoff, delivery.DropoffContact},
+	}
+	for _, c := range contacts {
+		if c.contact == nil {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, q4, delivery.ID, c.stopType, c.contact.Name, c.contact.PhoneNumber,
+			c.contact.Entrance, c.contact.Floor, c.contact.Intercom, c.contact.Notes)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return err
+		}
+	}
+
+	err = addOutboxEvent(ctx, tx, entity.AggregateDelivery, delivery.ID, entity.DeliveryCreated,
+		map[string]interface{}{"client_id": delivery.ClientID, "price": delivery.Price})
+	if err != nil {
 		dr.appLogger.Error(err)
 		return err
 	}
@@ -73,17 +105,20 @@ func (dr *DeliveryRepo) CreateDelivery(ctx context.Context, delivery *entity.Del
 	return nil
 }
 
-func (dr *DeliveryRepo) GetDeliveryByID(ctx context.Context, clientID, deliveryID int) (*dto.DeliveryFullInfoResponse, error) {
+// GetDeliveryByID fetches the delivery if the user is its participant or manages
+// its organization, any delivery is fetched if readAny is set
+func (dr *DeliveryRepo) GetDeliveryByID(ctx context.Context, clientID, deliveryID int, readAny bool) (*dto.DeliveryFullInfoResponse, error) {
 	query := `
-		SELECT deliveries.id, type_id, courier_id, status_id, price, has_loader,
+		SELECT deliveries.id, client_id, type_id, courier_id, status_id, price, has_loader, cargo_weight, cargo_volume,
        	geo.from_latitude, geo.from_longitude, geo.from_object, geo.to_latitude, geo.to_longitude, geo.to_object,
-       	geo.distance, deliveries.created_at
+       	geo.distance, deliveries.created_at, COALESCE(delivery_proofs.pin, '')
 		FROM deliveries
 		INNER JOIN geo ON deliveries.geo_id = geo.id
-		WHERE (client_id = $1 OR courier_id = $1 OR $1 IN (
-		    SELECT users.id
-		    FROM users INNER JOIN meta ON users.id = meta.user_id
-		    WHERE is_admin = true
+		LEFT JOIN delivery_proofs ON deliveries.id = delivery_proofs.delivery_id
+		WHERE ($3 OR client_id = $1 OR courier_id = $1 OR organization_id IN (
+		    SELECT organization_id
+		    FROM organization_members
+		    WHERE user_id = $1 AND role IN ('owner', 'manager')
 		)) AND deliveries.id = $2`
 
 	queryCourier := `
@@ -92,12 +127,17 @@ func (dr *DeliveryRepo) GetDeliveryByID(ctx context.Context, clientID, deliveryI
 		ON users.id = meta.user_id
 		WHERE users.id = $1`
 
+	queryContacts := `
+		SELECT stop_type, name, phone_number, entrance, floor, intercom, notes
+		FROM delivery_contacts
+		WHERE delivery_id = $1`
+
 	response := &dto.DeliveryFullInfoResponse{}
-	row := dr.QueryRowContext(ctx, query, clientID, deliveryID)
+	row := dr.QueryRowContext(ctx, query, clientID, deliveryID, readAny)
 	var courierID sql.NullInt64
-	err := row.Scan(&response.ID, &response.TypeID, &courierID, &response.StatusID, &response.Price, &response.HasLoader,
-		&response.FromObject.Latitude, &response.FromObject.Longitude, &response.FromObject.Object, &response.ToObject.Latitude,
-		&response.ToObject.Longitude, &response.ToObject.Object, &response.Distance, &response.Time)
+	err := row.Scan(&response.ID, &response.ClientID, &response.TypeID, &courierID, &response.StatusID, &response.Price, &response.HasLoader,
+		&response.CargoWeight, &response.CargoVolume, &response.FromObject.Latitude, &response.FromObject.Longitude, &response.FromObject.Object, &response.ToObject.Latitude,
+		&response.ToObject.Longitude, &response.ToObject.Object, &response.Distance, &response.Time, &response.PIN)
 
 	if err == sql.ErrNoRows {
 		err = fmt.Errorf("user with this id doesn't have permission to get delivery")
@@ -111,6 +151,7 @@ func (dr *DeliveryRepo) GetDeliveryByID(ctx context.Context, clientID, deliveryI
 	}
 
 	if courierID.Valid {
+		response.CourierID = int(courierID.Int64)
 		row = dr.QueryRowContext(ctx, queryCourier, courierID.Int64)
 		err = row.Scan(&response.Courier.Name, &response.Courier.PhoneNumber, &response.Courier.Rating, &response.Courier.CreatedAt)
 		if err != nil {
@@ -119,24 +160,121 @@ func (dr *DeliveryRepo) GetDeliveryByID(ctx context.Context, clientID, deliveryI
 		}
 	}
 
+	rows, err := dr.QueryContext(ctx, queryContacts, deliveryID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stopType string
+		contact := &dto.StopContactResponse{}
+		err = rows.Scan(&stopType, &contact.Name, &contact.PhoneNumber, &contact.Entrance, &contact.Floor, &contact.Intercom, &contact.Notes)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return nil, err
+		}
+		if stopType == entity.StopPickup {
+			response.PickupContact = contact
+		} else {
+			response.DropoffContact = contact
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
 	return response, nil
 }
 
-func (dr *DeliveryRepo) GetActiveDeliveryAmount(ctx context.Context, courierID int) (int, error) {
-	query := `SELECT COUNT(id) FROM deliveries WHERE status_id = 2 AND courier_id = $1`
-	var amount int
+// GetVehicleCapacity fetches limits of the delivery type's vehicle
+func (dr *DeliveryRepo) GetVehicleCapacity(ctx context.Context, typeID int) (*entity.VehicleCapacity, error) {
+	query := `SELECT id, max_active, max_weight, max_volume FROM delivery_types WHERE id = $1`
+	capacity := &entity.VehicleCapacity{}
+	row := dr.QueryRowContext(ctx, query, typeID)
+	err := row.Scan(&capacity.TypeID, &capacity.MaxActive, &capacity.MaxWeight, &capacity.MaxVolume)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	return capacity, nil
+}
+
+// GetCourierPresence fetches courier's vehicle and last known location,
+// returns nil if courier has never shared it
+func (dr *DeliveryRepo) GetCourierPresence(ctx context.Context, courierID int) (*entity.CourierPresence, error) {
+	query := `SELECT user_id, is_online, type_id, latitude, longitude FROM courier_presence WHERE user_id = $1`
+	presence := &entity.CourierPresence{}
 	row := dr.QueryRowContext(ctx, query, courierID)
-	err := row.Scan(&amount)
+	err := row.Scan(&presence.CourierID, &presence.IsOnline, &presence.TypeID, &presence.Latitude, &presence.Longitude)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
 		dr.appLogger.Error(err)
-		return 0, err
+		return nil, err
 	}
-	return amount, nil
+	return presence, nil
 }
 
-func (dr *DeliveryRepo) AcceptDelivery(ctx context.Context, courierID, deliveryID int) error {
-	query := `UPDATE deliveries SET courier_id = $1, status_id = 2 WHERE id = $2`
-	result, err := dr.ExecContext(ctx, query, courierID, deliveryID)
+// GetActiveDeliveriesByCourierID fetches all courier's active deliveries with their geo data
+func (dr *DeliveryRepo) GetActiveDeliveriesByCourierID(ctx context.Context, courierID int) ([]*entity.Delivery, error) {
+	query := `
+		SELECT deliveries.id, client_id, status_id, type_id, price, has_loader, cargo_weight, cargo_volume,
+			picked_up_at, created_at, pickup_from, pickup_to, dropoff_from, dropoff_to, geo.from_latitude, geo.from_longitude, geo.from_object,
+			geo.to_latitude, geo.to_longitude, geo.to_object, geo.distance
+		FROM deliveries INNER JOIN geo ON deliveries.geo_id = geo.id
+		WHERE status_id = 2 AND courier_id = $1
+		ORDER BY deliveries.id`
+
+	rows, err := dr.QueryContext(ctx, query, courierID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.Delivery, 0)
+	for rows.Next() {
+		result := &entity.Delivery{CourierID: courierID, Geo: &entity.Geo{}}
+		var pickedUpAt, pickupFrom, pickupTo, dropoffFrom, dropoffTo sql.NullTime
+		err = rows.Scan(&result.ID, &result.ClientID, &result.StatusID, &result.TypeID, &result.Price, &result.HasLoader,
+			&result.CargoWeight, &result.CargoVolume, &pickedUpAt, &result.CreatedAt,
+			&pickupFrom, &pickupTo, &dropoffFrom, &dropoffTo,
+			&result.Geo.FromLatitude, &result.Geo.FromLongitude, &result.Geo.FromObject,
+			&result.Geo.ToLatitude, &result.Geo.ToLongitude, &result.Geo.ToObject, &result.Geo.Distance)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return nil, err
+		}
+		if pickedUpAt.Valid {
+			result.PickedUpAt = &pickedUpAt.Time
+		}
+		result.PickupWindow = nullTimeToWindow(pickupFrom, pickupTo)
+		result.DropoffWindow = nullTimeToWindow(dropoffFrom, dropoffTo)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// PickUpDelivery marks that courier has picked up the cargo of active delivery
+func (dr *DeliveryRepo) PickUpDelivery(ctx context.Context, deliveryID int) error {
+	tx, err := dr.Begin()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE deliveries SET picked_up_at = now() WHERE id = $1 AND status_id = 2 AND picked_up_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, deliveryID)
 	if err != nil {
 		dr.appLogger.Error(err)
 		return err
@@ -148,13 +286,122 @@ func (dr *DeliveryRepo) AcceptDelivery(ctx context.Context, courierID, deliveryI
 	}
 
 	if rows != 1 {
-		err = fmt.Errorf("error accepting delivery")
+		err = fmt.Errorf("delivery is not active or already picked up")
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	err = addOutboxEvent(ctx, tx, entity.AggregateDelivery, deliveryID, entity.DeliveryPickedUp, nil)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// AcceptDelivery assigns the courier to the delivery from the open marketplace
+// if the courier's vehicle fits it and has enough free capacity
+func (dr *DeliveryRepo) AcceptDelivery(ctx context.Context, courierID, deliveryID int) error {
+	tx, err := dr.Begin()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	err = assignCourier(ctx, tx, courierID, deliveryID, false)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
 		dr.appLogger.Error(err)
 		return err
 	}
 	return nil
 }
 
+// assignCourier assigns the courier to the delivery waiting for one within the transaction.
+// Courier's row is locked till the end of the transaction, so concurrent assignments
+// of the same courier are checked against vehicle capacity one by one. Deliveries
+// being dispatched can only be assigned by the accepted offer
+func assignCourier(ctx context.Context, tx *sql.Tx, courierID, deliveryID int, byOffer bool) error {
+	query1 := `SELECT id FROM users WHERE id = $1 FOR UPDATE`
+	var id int
+	err := tx.QueryRowContext(ctx, query1, courierID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("courier is not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	query2 := `SELECT type_id, cargo_weight, cargo_volume FROM deliveries WHERE id = $1`
+	delivery := &entity.Delivery{}
+	err = tx.QueryRowContext(ctx, query2, deliveryID).Scan(&delivery.TypeID, &delivery.CargoWeight, &delivery.CargoVolume)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("delivery is not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Courier's vehicle type is taken from the shared presence,
+	// otherwise it is considered to match the delivery type
+	query3 := `
+		SELECT delivery_types.id, max_active, max_weight, max_volume, load.cnt, load.weight, load.volume
+		FROM delivery_types, (
+			SELECT COUNT(id) AS cnt, COALESCE(SUM(cargo_weight), 0) AS weight, COALESCE(SUM(cargo_volume), 0) AS volume
+			FROM deliveries
+			WHERE status_id = 2 AND courier_id = $1
+		) AS load
+		WHERE delivery_types.id = COALESCE((SELECT type_id FROM courier_presence WHERE user_id = $1), $2)
+	`
+	capacity := &entity.VehicleCapacity{}
+	load := &entity.CourierLoad{}
+	err = tx.QueryRowContext(ctx, query3, courierID, delivery.TypeID).Scan(&capacity.TypeID, &capacity.MaxActive,
+		&capacity.MaxWeight, &capacity.MaxVolume, &load.ActiveCnt, &load.Weight, &load.Volume)
+	if err != nil {
+		return err
+	}
+
+	if capacity.TypeID < delivery.TypeID {
+		return fmt.Errorf("vehicle doesn't fit delivery type")
+	}
+	if load.ActiveCnt >= capacity.MaxActive {
+		return fmt.Errorf("active deliveries limit reached")
+	}
+	if load.Weight+delivery.CargoWeight > capacity.MaxWeight || load.Volume+delivery.CargoVolume > capacity.MaxVolume {
+		return fmt.Errorf("not enough vehicle capacity")
+	}
+
+	query4 := `
+		UPDATE deliveries
+		SET courier_id = $1, status_id = 2, is_dispatching = false, dispatching_since = NULL
+		WHERE id = $2 AND status_id = 1 AND (is_dispatching = false OR $3)
+	`
+	result, err := tx.ExecContext(ctx, query4, courierID, deliveryID, byOffer)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return fmt.Errorf("delivery is no longer available")
+	}
+
+	return addOutboxEvent(ctx, tx, entity.AggregateDelivery, deliveryID, entity.DeliveryAccepted,
+		map[string]int{"courier_id": courierID})
+}
+
 func (dr *DeliveryRepo) IsDeliveryPerformer(ctx context.Context, courierID, deliveryID int) (bool, error) {
 	query := `SELECT COUNT(id) FROM deliveries WHERE courier_id = $1 AND id = $2`
 	var amount int
@@ -188,8 +435,27 @@ func (dr *DeliveryRepo) IsDeliveryOwner(ctx context.Context, clientID, deliveryI
 }
 
 func (dr *DeliveryRepo) ChangeDeliveryStatus(ctx context.Context, statusID, deliveryID int) error {
+	return dr.changeStatus(ctx, statusID, deliveryID, entity.DeliveryStatusChanged,
+		map[string]int{"status_id": statusID})
+}
+
+// CancelDelivery cancels the delivery on behalf of its client
+func (dr *DeliveryRepo) CancelDelivery(ctx context.Context, clientID, deliveryID int) error {
+	return dr.changeStatus(ctx, 4, deliveryID, entity.DeliveryCancelled,
+		map[string]int{"cancelled_by": clientID})
+}
+
+// changeStatus sets status of the delivery and writes the event of the change to the outbox
+func (dr *DeliveryRepo) changeStatus(ctx context.Context, statusID, deliveryID int, event string, payload interface{}) error {
+	tx, err := dr.Begin()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `UPDATE deliveries SET status_id = $1 WHERE id = $2`
-	result, err := dr.ExecContext(ctx, query, statusID, deliveryID)
+	result, err := tx.ExecContext(ctx, query, statusID, deliveryID)
 	if err != nil {
 		dr.appLogger.Error(err)
 		return err
@@ -205,24 +471,36 @@ func (dr *DeliveryRepo) ChangeDeliveryStatus(ctx context.Context, statusID, deli
 		dr.appLogger.Error(err)
 		return err
 	}
+
+	err = addOutboxEvent(ctx, tx, entity.AggregateDelivery, deliveryID, event, payload)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
 	return nil
 }
 
-func (dr *DeliveryRepo) GetDeliveriesByGeolocation(ctx context.Context, q *dto.DeliveryListGeolocationQuery, searchD float64) ([]*dto.DeliveryBriefResponse, error) {
+// GetDeliveriesByGeolocation fetches new deliveries with pickup point within the great-circle
+// radius of the location ordered by distance to pickup, earth_box is used to filter
+// candidates by the spatial index and earth_distance to drop the corners of the box
+func (dr *DeliveryRepo) GetDeliveriesByGeolocation(ctx context.Context, q *dto.DeliveryListGeolocationQuery) ([]*dto.DeliveryBriefResponse, error) {
 	query := `
-	SELECT deliveries.id, type_id, has_loader, status_id, price, geo.from_object, geo.to_object, geo.distance, created_at
+	SELECT deliveries.id, type_id, has_loader, status_id, price, geo.from_object, geo.to_object, geo.distance, created_at,
+		earth_distance(ll_to_earth(geo.from_latitude, geo.from_longitude), ll_to_earth($1, $2)) AS pickup_distance
 	FROM deliveries INNER JOIN geo ON deliveries.geo_id = geo.id
-	WHERE (geo.from_latitude BETWEEN $1 AND $2) AND (geo.from_longitude BETWEEN $3 AND $4) AND status_id = 1
-	LIMIT 10 OFFSET $5
+	WHERE earth_box(ll_to_earth($1, $2), $3) @> ll_to_earth(geo.from_latitude, geo.from_longitude)
+		AND earth_distance(ll_to_earth(geo.from_latitude, geo.from_longitude), ll_to_earth($1, $2)) <= $3
+		AND status_id = 1 AND is_dispatching = false AND city_id = $5
+	ORDER BY pickup_distance, deliveries.id
+	LIMIT 10 OFFSET $4
 	`
 
-	latSearchFrom := q.Latitude - searchD
-	latSearchTo := q.Latitude + searchD
-
-	lonSearchFrom := q.Longitude - searchD
-	lonSearchTo := q.Longitude + searchD
-
-	rows, err := dr.QueryContext(ctx, query, latSearchFrom, latSearchTo, lonSearchFrom, lonSearchTo, (q.Page-1)*10)
+	rows, err := dr.QueryContext(ctx, query, q.Latitude, q.Longitude, q.Radius, (q.Page-1)*10, q.CityID)
 	if err != nil {
 		dr.appLogger.Error(err)
 		return nil, err
@@ -232,7 +510,8 @@ func (dr *DeliveryRepo) GetDeliveriesByGeolocation(ctx context.Context, q *dto.D
 	results := make([]*dto.DeliveryBriefResponse, 0)
 	for rows.Next() {
 		result := &dto.DeliveryBriefResponse{}
-		err = rows.Scan(&result.ID, &result.TypeID, &result.HasLoader, &result.StatusID, &result.Price, &result.FromObject, &result.ToObject, &result.Distance, &result.Time)
+		err = rows.Scan(&result.ID, &result.TypeID, &result.HasLoader, &result.StatusID, &result.Price, &result.FromObject, &result.ToObject, &result.Distance, &result.Time,
+			&result.PickupDistance)
 		if err != nil {
 			dr.appLogger.Error(err)
 			return nil, err
@@ -335,3 +614,19 @@ func (dr *DeliveryRepo) GetDeliveriesByCourierID(ctx context.Context, courierID
 
 	return results, nil
 }
+
+// windowToNullTime converts optional time window to nullable bounds
+func windowToNullTime(w *entity.TimeWindow) (sql.NullTime, sql.NullTime) {
+	if w == nil {
+		return sql.NullTime{}, sql.NullTime{}
+	}
+	return sql.NullTime{Time: w.From, Valid: true}, sql.NullTime{Time: w.To, Valid: true}
+}
+
+// nullTimeToWindow converts nullable bounds to optional time window
+func nullTimeToWindow(from, to sql.NullTime) *entity.TimeWindow {
+	if !from.Valid || !to.Valid {
+		return nil
+	}
+	return &entity.TimeWindow{From: from.Time, To: to.Time}
+}
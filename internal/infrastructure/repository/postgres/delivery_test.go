@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/dto"
 	"github.com/dacore-x/truckly/internal/entity"
 	"github.com/dacore-x/truckly/pkg/logger"
 	"github.com/go-test/deep"
@@ -28,11 +31,11 @@ func TestDeliveryRepo_CreateDeliverySuccess(t *testing.T) {
 		delivery *entity.Delivery
 	}
 	tests := []struct {
-		name   string
-		args   args
-		rows   *sqlmock.Rows
-		result driver.Result
-		error  error
+		name         string
+		args         args
+		rows         *sqlmock.Rows
+		deliveryRows *sqlmock.Rows
+		error        error
 	}{
 		{
 			name: "delivery usual case",
@@ -52,12 +55,15 @@ func TestDeliveryRepo_CreateDeliverySuccess(t *testing.T) {
 						ToObject:      "улица веселая д.10",
 						Distance:      1200,
 					},
-					Price:     1220,
-					HasLoader: true,
+					Price:       1220,
+					HasLoader:   true,
+					CargoWeight: 12.5,
+					CargoVolume: 0.3,
+					PIN:         "0427",
 				},
 			},
-			rows:   sqlmock.NewRows([]string{"id"}).AddRow(1),
-			result: sqlmock.NewResult(1, 1),
+			rows:         sqlmock.NewRows([]string{"id"}).AddRow(1),
+			deliveryRows: sqlmock.NewRows([]string{"id"}).AddRow(1),
 		},
 		{
 			name: "delivery usual case without type id",
@@ -81,8 +87,40 @@ func TestDeliveryRepo_CreateDeliverySuccess(t *testing.T) {
 					HasLoader: false,
 				},
 			},
-			rows:   sqlmock.NewRows([]string{"id"}).AddRow(2),
-			result: sqlmock.NewResult(2, 1),
+			rows:         sqlmock.NewRows([]string{"id"}).AddRow(2),
+			deliveryRows: sqlmock.NewRows([]string{"id"}).AddRow(2),
+		},
+		{
+			name: "delivery with dropoff contact",
+			args: args{
+				ctx: context.Background(),
+				delivery: &entity.Delivery{
+					ID:       3,
+					ClientID: 1,
+					StatusID: 1,
+					TypeID:   1,
+					Geo: &entity.Geo{
+						FromLongitude: 37.22,
+						FromLatitude:  55.77,
+						FromObject:    "улица веселая д.3",
+						ToLongitude:   37.22,
+						ToLatitude:    55.77,
+						ToObject:      "улица веселая д.10",
+						Distance:      1200,
+					},
+					Price: 1220,
+					DropoffContact: &entity.StopContact{
+						Name:        "Иван",
+						PhoneNumber: "+79990001122",
+						Entrance:    "3",
+						Floor:       "5",
+						Intercom:    "35K",
+						Notes:       "leave at the door",
+					},
+				},
+			},
+			rows:         sqlmock.NewRows([]string{"id"}).AddRow(3),
+			deliveryRows: sqlmock.NewRows([]string{"id"}).AddRow(3),
 		},
 	}
 	for _, tt := range tests {
@@ -106,17 +144,37 @@ func TestDeliveryRepo_CreateDeliverySuccess(t *testing.T) {
 				WillReturnRows(tt.rows).
 				WillReturnError(tt.error)
 
-			mock.ExpectExec(regexp.QuoteMeta(`
-				INSERT INTO deliveries(client_id, status_id, type_id, geo_id, price, has_loader)
-				VALUES ($1, $2, $3, $4, $5, $6)
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				INSERT INTO deliveries(client_id, status_id, type_id, geo_id, price, has_loader, cargo_weight, cargo_volume,
+					pickup_from, pickup_to, dropoff_from, dropoff_to, zone_id, city_id, organization_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+				RETURNING id
 			`)).
-				WithArgs(tt.args.delivery.ClientID, 1, tt.args.delivery.TypeID, tt.args.delivery.ID, tt.args.delivery.Price, tt.args.delivery.HasLoader).
-				WillReturnResult(tt.result)
+				WithArgs(tt.args.delivery.ClientID, 1, tt.args.delivery.TypeID, tt.args.delivery.ID, tt.args.delivery.Price, tt.args.delivery.HasLoader,
+					tt.args.delivery.CargoWeight, tt.args.delivery.CargoVolume, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+					tt.args.delivery.ZoneID, tt.args.delivery.CityID, tt.args.delivery.OrgID).
+				WillReturnRows(tt.deliveryRows)
+
+			mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO delivery_proofs(delivery_id, pin) VALUES ($1, $2)`)).
+				WithArgs(tt.args.delivery.ID, tt.args.delivery.PIN).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			if c := tt.args.delivery.DropoffContact; c != nil {
+				mock.ExpectExec(regexp.QuoteMeta(`
+					INSERT INTO delivery_contacts(delivery_id, stop_type, name, phone_number, entrance, floor, intercom, notes)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				`)).
+					WithArgs(tt.args.delivery.ID, "dropoff", c.Name, c.PhoneNumber, c.Entrance, c.Floor, c.Intercom, c.Notes).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			expectOutboxEvent(mock, entity.AggregateDelivery, tt.args.delivery.ID, entity.DeliveryCreated)
 
 			mock.ExpectCommit()
 
 			err := repo.CreateDelivery(context.Background(), tt.args.delivery)
 			require.Nil(t, deep.Equal(tt.error, err))
+			require.NoError(t, mock.ExpectationsWereMet())
 
 		})
 	}
@@ -196,3 +254,175 @@ func TestDeliveryRepo_CreateDeliveryRollback(t *testing.T) {
 		})
 	}
 }
+
+// expectAssignCourier expects queries of the courier's assignment up to the update
+// of the delivery, vehicle type with its limits and the courier's load are returned by capacity
+func expectAssignCourier(mock sqlmock.Sqlmock, courierID, deliveryID int, capacity *sqlmock.Rows) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM users WHERE id = $1 FOR UPDATE`)).
+		WithArgs(courierID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(courierID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT type_id, cargo_weight, cargo_volume FROM deliveries WHERE id = $1`)).
+		WithArgs(deliveryID).
+		WillReturnRows(sqlmock.NewRows([]string{"type_id", "cargo_weight", "cargo_volume"}).AddRow(1, 20, 0.2))
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT delivery_types.id, max_active, max_weight, max_volume, load.cnt, load.weight, load.volume
+		FROM delivery_types, (
+			SELECT COUNT(id) AS cnt, COALESCE(SUM(cargo_weight), 0) AS weight, COALESCE(SUM(cargo_volume), 0) AS volume
+			FROM deliveries
+			WHERE status_id = 2 AND courier_id = $1
+		) AS load
+		WHERE delivery_types.id = COALESCE((SELECT type_id FROM courier_presence WHERE user_id = $1), $2)
+	`)).
+		WithArgs(courierID, 1).
+		WillReturnRows(capacity)
+}
+
+const assignCourierQuery = `
+	UPDATE deliveries
+	SET courier_id = $1, status_id = 2, is_dispatching = false, dispatching_since = NULL
+	WHERE id = $2 AND status_id = 1 AND (is_dispatching = false OR $3)
+`
+
+var capacityColumns = []string{"id", "max_active", "max_weight", "max_volume", "cnt", "weight", "volume"}
+
+func TestDeliveryRepo_AcceptDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewDeliveryRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name     string
+		capacity *sqlmock.Rows
+		update   bool // whether capacity is enough to update the delivery
+		updated  int64
+		error    error
+	}{
+		{
+			name:     "courier without active deliveries",
+			capacity: sqlmock.NewRows(capacityColumns).AddRow(1, 3, 100, 1, 0, 0, 0),
+			update:   true,
+			updated:  1,
+		},
+		{
+			name:     "active deliveries limit is reached",
+			capacity: sqlmock.NewRows(capacityColumns).AddRow(1, 3, 100, 1, 3, 30, 0.3),
+			error:    fmt.Errorf("active deliveries limit reached"),
+		},
+		{
+			name:     "not enough weight left",
+			capacity: sqlmock.NewRows(capacityColumns).AddRow(1, 3, 100, 1, 2, 90, 0.3),
+			error:    fmt.Errorf("not enough vehicle capacity"),
+		},
+		{
+			name:     "delivery is taken meanwhile",
+			capacity: sqlmock.NewRows(capacityColumns).AddRow(2, 3, 500, 3, 1, 100, 1),
+			update:   true,
+			updated:  0,
+			error:    fmt.Errorf("delivery is no longer available"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			expectAssignCourier(mock, 2, 10, tt.capacity)
+			if tt.update {
+				mock.ExpectExec(regexp.QuoteMeta(assignCourierQuery)).
+					WithArgs(2, 10, false).
+					WillReturnResult(sqlmock.NewResult(0, tt.updated))
+			}
+			if tt.error == nil {
+				expectOutboxEvent(mock, entity.AggregateDelivery, 10, entity.DeliveryAccepted)
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := repo.AcceptDelivery(context.Background(), 2, 10)
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeliveryRepo_GetDeliveriesByGeolocation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewDeliveryRepo(db, logger.New(testLogger))
+
+	query := `
+	SELECT deliveries.id, type_id, has_loader, status_id, price, geo.from_object, geo.to_object, geo.distance, created_at,
+		earth_distance(ll_to_earth(geo.from_latitude, geo.from_longitude), ll_to_earth($1, $2)) AS pickup_distance
+	FROM deliveries INNER JOIN geo ON deliveries.geo_id = geo.id
+	WHERE earth_box(ll_to_earth($1, $2), $3) @> ll_to_earth(geo.from_latitude, geo.from_longitude)
+		AND earth_distance(ll_to_earth(geo.from_latitude, geo.from_longitude), ll_to_earth($1, $2)) <= $3
+		AND status_id = 1 AND is_dispatching = false AND city_id = $5
+	ORDER BY pickup_distance, deliveries.id
+	LIMIT 10 OFFSET $4
+	`
+	columns := []string{"id", "type_id", "has_loader", "status_id", "price", "from_object", "to_object", "distance", "created_at", "pickup_distance"}
+	createdAt := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query *dto.DeliveryListGeolocationQuery
+		rows  *sqlmock.Rows
+		want  []*dto.DeliveryBriefResponse
+		error error
+	}{
+		{
+			name: "deliveries within radius",
+			query: &dto.DeliveryListGeolocationQuery{
+				Latitude:  55.751244,
+				Longitude: 37.618423,
+				Radius:    3000,
+				Page:      2,
+				CityID:    1,
+			},
+			rows: sqlmock.NewRows(columns).
+				AddRow(4, 1, false, 1, 350., "Moscow, Tverskaya 1", "Moscow, Arbat 10", 2500, createdAt, 120.5).
+				AddRow(2, 2, true, 1, 900., "Moscow, Petrovka 5", "Moscow, Lenina 3", 7000, createdAt, 1740.2),
+			want: []*dto.DeliveryBriefResponse{
+				{
+					ID: 4, TypeID: 1, HasLoader: false, StatusID: 1, Price: 350,
+					FromObject: "Moscow, Tverskaya 1", ToObject: "Moscow, Arbat 10",
+					Distance: 2500, Time: createdAt, PickupDistance: 120.5,
+				},
+				{
+					ID: 2, TypeID: 2, HasLoader: true, StatusID: 1, Price: 900,
+					FromObject: "Moscow, Petrovka 5", ToObject: "Moscow, Lenina 3",
+					Distance: 7000, Time: createdAt, PickupDistance: 1740.2,
+				},
+			},
+		},
+		{
+			name: "no deliveries within radius",
+			query: &dto.DeliveryListGeolocationQuery{
+				Latitude:  55.751244,
+				Longitude: 37.618423,
+				Radius:    500,
+				Page:      1,
+				CityID:    2,
+			},
+			rows:  sqlmock.NewRows(columns),
+			error: fmt.Errorf("results not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(query)).
+				WithArgs(tt.query.Latitude, tt.query.Longitude, tt.query.Radius, (tt.query.Page-1)*10, tt.query.CityID).
+				WillReturnRows(tt.rows)
+
+			got, err := repo.GetDeliveriesByGeolocation(context.Background(), tt.query)
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.Nil(t, deep.Equal(tt.want, got))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// DispatchRepo is a struct that provides
+// all functions to execute SQL queries
+// related to automatic dispatch of deliveries
+type DispatchRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewDispatchRepo(db *sql.DB, l *logger.Logger) *DispatchRepo {
+	return &DispatchRepo{db, l}
+}
+
+// SetCourierPresence creates or updates courier's online status and location,
+// idle time is reset only when courier goes online
+func (dr *DispatchRepo) SetCourierPresence(ctx context.Context, courierID int, req *dto.CourierPresenceRequestBody) error {
+	query := `
+		INSERT INTO courier_presence(user_id, is_online, type_id, latitude, longitude)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET is_online = EXCLUDED.is_online,
+			type_id = EXCLUDED.type_id,
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			idle_since = CASE WHEN courier_presence.is_online THEN courier_presence.idle_since ELSE now() END,
+			updated_at = now()
+	`
+	_, err := dr.ExecContext(ctx, query, courierID, req.IsOnline, req.TypeID, req.Latitude, req.Longitude)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetDispatchCandidates fetches online couriers of the delivery's city whose vehicle fits delivery type
+// and has enough free capacity for its cargo, who have no pending offers
+// and haven't been offered this delivery before
+func (dr *DispatchRepo) GetDispatchCandidates(ctx context.Context, deliveryID int) ([]*entity.CourierCandidate, error) {
+	query := `
+		SELECT courier_presence.user_id, courier_presence.type_id, latitude, longitude, rating, idle_since
+		FROM courier_presence
+		INNER JOIN meta ON courier_presence.user_id = meta.user_id
+		INNER JOIN delivery_types ON courier_presence.type_id = delivery_types.id
+		INNER JOIN deliveries ON deliveries.id = $1
+		LEFT JOIN (
+			SELECT courier_id, COUNT(id) AS cnt, SUM(cargo_weight) AS weight, SUM(cargo_volume) AS volume
+			FROM deliveries
+			WHERE status_id = 2
+			GROUP BY courier_id
+		) AS load ON load.courier_id = courier_presence.user_id
+		WHERE is_online = true AND is_courier = true AND is_banned = false
+			AND meta.city_id = deliveries.city_id
+			AND courier_presence.type_id >= deliveries.type_id
+			AND courier_presence.updated_at > now() - interval '5 minutes'
+			AND COALESCE(load.cnt, 0) < delivery_types.max_active
+			AND COALESCE(load.weight, 0) + deliveries.cargo_weight <= delivery_types.max_weight
+			AND COALESCE(load.volume, 0) + deliveries.cargo_volume <= delivery_types.max_volume
+			AND courier_presence.user_id NOT IN (
+				SELECT courier_id FROM dispatch_offers WHERE status = 'pending' OR delivery_id = $1
+			)
+	`
+	rows, err := dr.QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.CourierCandidate, 0)
+	for rows.Next() {
+		result := &entity.CourierCandidate{}
+		err = rows.Scan(&result.CourierID, &result.TypeID, &result.Latitude, &result.Longitude, &result.Rating, &result.IdleSince)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// SetDeliveryDispatching hides delivery from the open marketplace
+// while dispatcher is offering it to couriers and returns it back otherwise
+func (dr *DispatchRepo) SetDeliveryDispatching(ctx context.Context, deliveryID int, dispatching bool) error {
+	query := `
+		UPDATE deliveries
+		SET is_dispatching = $1, dispatching_since = CASE WHEN $1 THEN now() END
+		WHERE id = $2
+	`
+	result, err := dr.ExecContext(ctx, query, dispatching, deliveryID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("expected to affect 1 row, affected %d", rows)
+		dr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// ReleaseStaleDispatches passes deliveries whose dispatcher has stopped to the open marketplace.
+// Dispatch is stale if it started and its last offer expired more than staleAfter ago,
+// pending offers of such dispatches are expired. Number of released deliveries is returned
+func (dr *DispatchRepo) ReleaseStaleDispatches(ctx context.Context, staleAfter time.Duration) (int, error) {
+	tx, err := dr.Begin()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query1 := `
+		UPDATE deliveries
+		SET is_dispatching = false, dispatching_since = NULL
+		WHERE is_dispatching = true AND dispatching_since < now() - $1 * interval '1 second'
+			AND NOT EXISTS (
+				SELECT 1 FROM dispatch_offers
+				WHERE delivery_id = deliveries.id AND expires_at > now() - $1 * interval '1 second'
+			)
+		RETURNING id
+	`
+	rows, err := tx.QueryContext(ctx, query1, staleAfter.Seconds())
+	if err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			dr.appLogger.Error(err)
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+
+	if len(ids) != 0 {
+		query2 := `
+			UPDATE dispatch_offers
+			SET status = 'expired', responded_at = now()
+			WHERE delivery_id = ANY($1) AND status = 'pending'
+		`
+		_, err = tx.ExecContext(ctx, query2, pq.Array(ids))
+		if err != nil {
+			dr.appLogger.Error(err)
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// GetDeliveryStatus fetches current status of the delivery
+func (dr *DispatchRepo) GetDeliveryStatus(ctx context.Context, deliveryID int) (int, error) {
+	query := `SELECT status_id FROM deliveries WHERE id = $1`
+	var statusID int
+	err := dr.QueryRowContext(ctx, query, deliveryID).Scan(&statusID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+	return statusID, nil
+}
+
+// CreateOffer creates a new pending offer record and attaches its id to the offer
+func (dr *DispatchRepo) CreateOffer(ctx context.Context, offer *entity.Offer) error {
+	query := `
+		INSERT INTO dispatch_offers(delivery_id, courier_id, score, distance, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	err := dr.QueryRowContext(ctx, query, offer.DeliveryID, offer.CourierID, offer.Score, offer.Distance, offer.ExpiresAt).Scan(&offer.ID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	offer.Status = entity.OfferPending
+	return nil
+}
+
+// GetOfferStatus fetches current status of the offer
+func (dr *DispatchRepo) GetOfferStatus(ctx context.Context, offerID int) (string, error) {
+	query := `SELECT status FROM dispatch_offers WHERE id = $1`
+	var status string
+	err := dr.QueryRowContext(ctx, query, offerID).Scan(&status)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return "", err
+	}
+	return status, nil
+}
+
+// ExpireOffer marks the offer as expired if courier hasn't responded to it yet
+func (dr *DispatchRepo) ExpireOffer(ctx context.Context, offerID int) (bool, error) {
+	query := `
+		UPDATE dispatch_offers
+		SET status = 'expired', responded_at = now()
+		WHERE id = $1 AND status = 'pending'
+	`
+	result, err := dr.ExecContext(ctx, query, offerID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// DeclineOffer marks courier's pending offer as declined
+func (dr *DispatchRepo) DeclineOffer(ctx context.Context, courierID, offerID int) error {
+	query := `
+		UPDATE dispatch_offers
+		SET status = 'declined', responded_at = now()
+		WHERE id = $1 AND courier_id = $2 AND status = 'pending' AND expires_at > now()
+	`
+	result, err := dr.ExecContext(ctx, query, offerID, courierID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("offer is not found or expired")
+		dr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// AcceptOffer marks courier's pending offer as accepted and assigns the courier
+// to the delivery in one transaction, vehicle capacity is checked the same way
+// as for deliveries from the open marketplace. Id of the assigned delivery is returned
+func (dr *DispatchRepo) AcceptOffer(ctx context.Context, courierID, offerID int) (int, error) {
+	tx, err := dr.Begin()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	q1 := `
+		UPDATE dispatch_offers
+		SET status = 'accepted', responded_at = now()
+		WHERE id = $1 AND courier_id = $2 AND status = 'pending' AND expires_at > now()
+		RETURNING delivery_id
+	`
+	var deliveryID int
+	err = tx.QueryRowContext(ctx, q1, offerID, courierID).Scan(&deliveryID)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("offer is not found or expired")
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+	if err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+
+	err = assignCourier(ctx, tx, courierID, deliveryID, true)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		dr.appLogger.Error(err)
+		return 0, err
+	}
+	return deliveryID, nil
+}
+
+// GetPendingOffersByCourierID fetches courier's offers waiting for response
+func (dr *DispatchRepo) GetPendingOffersByCourierID(ctx context.Context, courierID int) ([]*dto.OfferResponse, error) {
+	query := `
+		SELECT dispatch_offers.id, deliveries.id, type_id, has_loader, price,
+			geo.from_object, geo.to_object, dispatch_offers.distance, expires_at
+		FROM dispatch_offers
+		INNER JOIN deliveries ON dispatch_offers.delivery_id = deliveries.id
+		INNER JOIN geo ON deliveries.geo_id = geo.id
+		WHERE dispatch_offers.courier_id = $1 AND status = 'pending' AND expires_at > now()
+		ORDER BY expires_at
+	`
+	rows, err := dr.QueryContext(ctx, query, courierID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*dto.OfferResponse, 0)
+	for rows.Next() {
+		result := &dto.OfferResponse{}
+		err = rows.Scan(&result.ID, &result.DeliveryID, &result.TypeID, &result.HasLoader, &result.Price,
+			&result.FromObject, &result.ToObject, &result.Distance, &result.ExpiresAt)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
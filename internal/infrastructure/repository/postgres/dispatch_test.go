@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatchRepo_AcceptOffer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewDispatchRepo(db, logger.New(testLogger))
+
+	type args struct {
+		courierID int
+		offerID   int
+	}
+	tests := []struct {
+		name       string
+		args       args
+		deliveryID int
+		result     driver.Result
+		error      error
+	}{
+		{
+			name: "offer usual case",
+			args: args{
+				courierID: 2,
+				offerID:   1,
+			},
+			deliveryID: 10,
+			result:     sqlmock.NewResult(0, 1),
+		},
+		{
+			name: "delivery is taken or cancelled",
+			args: args{
+				courierID: 3,
+				offerID:   2,
+			},
+			deliveryID: 11,
+			result:     sqlmock.NewResult(0, 0),
+			error:      fmt.Errorf("delivery is no longer available"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				UPDATE dispatch_offers
+				SET status = 'accepted', responded_at = now()
+				WHERE id = $1 AND courier_id = $2 AND status = 'pending' AND expires_at > now()
+				RETURNING delivery_id
+			`)).
+				WithArgs(tt.args.offerID, tt.args.courierID).
+				WillReturnRows(sqlmock.NewRows([]string{"delivery_id"}).AddRow(tt.deliveryID))
+
+			expectAssignCourier(mock, tt.args.courierID, tt.deliveryID,
+				sqlmock.NewRows(capacityColumns).AddRow(1, 3, 100, 1, 0, 0, 0))
+			mock.ExpectExec(regexp.QuoteMeta(assignCourierQuery)).
+				WithArgs(tt.args.courierID, tt.deliveryID, true).
+				WillReturnResult(tt.result)
+
+			if tt.error == nil {
+				expectOutboxEvent(mock, entity.AggregateDelivery, tt.deliveryID, entity.DeliveryAccepted)
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			got, err := repo.AcceptOffer(context.Background(), tt.args.courierID, tt.args.offerID)
+			require.Nil(t, deep.Equal(tt.error, err))
+			if tt.error == nil {
+				require.Equal(t, tt.deliveryID, got)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDispatchRepo_ExpireOffer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewDispatchRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name    string
+		offerID int
+		result  driver.Result
+		want    bool
+	}{
+		{
+			name:    "offer is still pending",
+			offerID: 1,
+			result:  sqlmock.NewResult(0, 1),
+			want:    true,
+		},
+		{
+			name:    "courier has already responded",
+			offerID: 2,
+			result:  sqlmock.NewResult(0, 0),
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(regexp.QuoteMeta(`
+				UPDATE dispatch_offers
+				SET status = 'expired', responded_at = now()
+				WHERE id = $1 AND status = 'pending'
+			`)).
+				WithArgs(tt.offerID).
+				WillReturnResult(tt.result)
+
+			got, err := repo.ExpireOffer(context.Background(), tt.offerID)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDispatchRepo_ReleaseStaleDispatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewDispatchRepo(db, logger.New(testLogger))
+
+	releaseQuery := regexp.QuoteMeta(`
+		UPDATE deliveries
+		SET is_dispatching = false, dispatching_since = NULL
+		WHERE is_dispatching = true AND dispatching_since < now() - $1 * interval '1 second'
+			AND NOT EXISTS (
+				SELECT 1 FROM dispatch_offers
+				WHERE delivery_id = deliveries.id AND expires_at > now() - $1 * interval '1 second'
+			)
+		RETURNING id
+	`)
+
+	tests := []struct {
+		name string
+		ids  []int
+	}{
+		{
+			name: "stale dispatches are released",
+			ids:  []int{10, 11},
+		},
+		{
+			name: "nothing is stale",
+			ids:  []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := sqlmock.NewRows([]string{"id"})
+			for _, id := range tt.ids {
+				rows.AddRow(id)
+			}
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(releaseQuery).
+				WithArgs(float64(90)).
+				WillReturnRows(rows)
+			if len(tt.ids) != 0 {
+				mock.ExpectExec(regexp.QuoteMeta(`
+					UPDATE dispatch_offers
+					SET status = 'expired', responded_at = now()
+					WHERE delivery_id = ANY($1) AND status = 'pending'
+				`)).
+					WithArgs(sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+
+			got, err := repo.ReleaseStaleDispatches(context.Background(), 90*time.Second)
+			require.NoError(t, err)
+			require.Equal(t, len(tt.ids), got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// DisputeRepo is a struct that provides
+// all functions to execute SQL queries
+// related to disputes and ledger
+type DisputeRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewDisputeRepo(db *sql.DB, l *logger.Logger) *DisputeRepo {
+	return &DisputeRepo{db, l}
+}
+
+// CreateDispute creates a new dispute record and attaches its id and timestamps to the dispute
+func (dr *DisputeRepo) CreateDispute(ctx context.Context, dispute *entity.Dispute) error {
+	query := `
+		INSERT INTO disputes(delivery_id, opener_id, category, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at, updated_at
+	`
+	err := dr.QueryRowContext(ctx, query, dispute.DeliveryID, dispute.OpenerID, dispute.Category,
+		dispute.Description).Scan(&dispute.ID, &dispute.Status, &dispute.CreatedAt, &dispute.UpdatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		err = fmt.Errorf("dispute is already opened")
+		dr.appLogger.Error(err)
+		return err
+	}
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetDisputeByID fetches the dispute with its attachments
+func (dr *DisputeRepo) GetDisputeByID(ctx context.Context, disputeID int) (*entity.Dispute, error) {
+	query := `
+		SELECT id, delivery_id, opener_id, category, description, status, assignee_id, resolution,
+		       created_at, updated_at, resolved_at
+		FROM disputes
+		WHERE id = $1
+	`
+	disputes, err := dr.queryDisputes(ctx, query, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(disputes) == 0 {
+		err = fmt.Errorf("dispute is not found")
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	dispute := disputes[0]
+
+	queryAttachments := `
+		SELECT id, dispute_id, file_key, created_at
+		FROM dispute_attachments
+		WHERE dispute_id = $1
+		ORDER BY id
+	`
+	rows, err := dr.QueryContext(ctx, queryAttachments, disputeID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	dispute.Attachments = make([]*entity.DisputeAttachment, 0)
+	for rows.Next() {
+		attachment := &entity.DisputeAttachment{}
+		err = rows.Scan(&attachment.ID, &attachment.DisputeID, &attachment.FileKey, &attachment.CreatedAt)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return nil, err
+		}
+		dispute.Attachments = append(dispute.Attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// GetDisputesByDeliveryID fetches all disputes of the delivery without attachments
+func (dr *DisputeRepo) GetDisputesByDeliveryID(ctx context.Context, deliveryID int) ([]*entity.Dispute, error) {
+	query := `
+		SELECT id, delivery_id, opener_id, category, description, status, assignee_id, resolution,
+		       created_at, updated_at, resolved_at
+		FROM disputes
+		WHERE delivery_id = $1
+		ORDER BY id
+	`
+	return dr.queryDisputes(ctx, query, deliveryID)
+}
+
+// GetDisputes fetches page of the disputes queue filtered by status and assignee,
+// the oldest go first so that they are handled in order
+func (dr *DisputeRepo) GetDisputes(ctx context.Context, query *dto.DisputeListQuery) ([]*entity.Dispute, error) {
+	q := `
+		SELECT id, delivery_id, opener_id, category, description, status, assignee_id, resolution,
+		       created_at, updated_at, resolved_at
+		FROM disputes
+		WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR assignee_id = $2)
+		ORDER BY created_at, id
+		LIMIT 10 OFFSET $3
+	`
+	return dr.queryDisputes(ctx, q, query.Status, query.AssigneeID, (query.Page-1)*10)
+}
+
+// AddAttachment creates a new attachment record and attaches its id to the attachment
+func (dr *DisputeRepo) AddAttachment(ctx context.Context, attachment *entity.DisputeAttachment) error {
+	query := `
+		INSERT INTO dispute_attachments(dispute_id, file_key)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+	err := dr.QueryRowContext(ctx, query, attachment.DisputeID, attachment.FileKey).Scan(&attachment.ID, &attachment.CreatedAt)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// AssignDispute assigns unresolved dispute to the admin and takes it in progress
+func (dr *DisputeRepo) AssignDispute(ctx context.Context, disputeID, assigneeID int) error {
+	query := `
+		UPDATE disputes
+		SET assignee_id = $1, status = 'in_progress', updated_at = now()
+		WHERE id = $2 AND status IN ('open', 'in_progress')
+	`
+	return dr.updateOpenDispute(ctx, query, assigneeID, disputeID)
+}
+
+// SetDisputeStatus changes status of unresolved dispute
+func (dr *DisputeRepo) SetDisputeStatus(ctx context.Context, disputeID int, status string) error {
+	query := `
+		UPDATE disputes
+		SET status = $1, updated_at = now()
+		WHERE id = $2 AND status IN ('open', 'in_progress')
+	`
+	return dr.updateOpenDispute(ctx, query, status, disputeID)
+}
+
+// ResolveDispute closes unresolved dispute, records ledger entries
+// of the resolution and bans penalized user in one transaction
+func (dr *DisputeRepo) ResolveDispute(ctx context.Context, resolution *entity.DisputeResolution) error {
+	tx, err := dr.Begin()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	q1 := `
+		UPDATE disputes
+		SET status = $1, resolution = $2, updated_at = now(), resolved_at = now()
+		WHERE id = $3 AND status IN ('open', 'in_progress')
+	`
+	result, err := tx.ExecContext(ctx, q1, resolution.Status, resolution.Resolution, resolution.DisputeID)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("dispute is already closed")
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	q2 := `
+		INSERT INTO ledger_entries(user_id, delivery_id, dispute_id, kind, amount)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	for _, entry := range resolution.Entries {
+		err = tx.QueryRowContext(ctx, q2, entry.UserID, entry.DeliveryID, entry.DisputeID, entry.Kind,
+			entry.Amount).Scan(&entry.ID, &entry.CreatedAt)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return err
+		}
+	}
+
+	if resolution.BannedID != 0 {
+		err = setBanTx(ctx, tx, banUserQuery, resolution.BannedID, entity.UserBanned)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// updateOpenDispute executes update of unresolved dispute
+// and checks that it has been found
+func (dr *DisputeRepo) updateOpenDispute(ctx context.Context, query string, args ...interface{}) error {
+	result, err := dr.ExecContext(ctx, query, args...)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		dr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("dispute is not found or already closed")
+		dr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// queryDisputes executes query selecting disputes and scans them
+func (dr *DisputeRepo) queryDisputes(ctx context.Context, query string, args ...interface{}) ([]*entity.Dispute, error) {
+	rows, err := dr.QueryContext(ctx, query, args...)
+	if err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.Dispute, 0)
+	for rows.Next() {
+		result := &entity.Dispute{}
+		var assigneeID sql.NullInt64
+		var resolvedAt sql.NullTime
+		err = rows.Scan(&result.ID, &result.DeliveryID, &result.OpenerID, &result.Category, &result.Description,
+			&result.Status, &assigneeID, &result.Resolution, &result.CreatedAt, &result.UpdatedAt, &resolvedAt)
+		if err != nil {
+			dr.appLogger.Error(err)
+			return nil, err
+		}
+		if assigneeID.Valid {
+			id := int(assigneeID.Int64)
+			result.AssigneeID = &id
+		}
+		if resolvedAt.Valid {
+			result.ResolvedAt = &resolvedAt.Time
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		dr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
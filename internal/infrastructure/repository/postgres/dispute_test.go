@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisputeRepo_ResolveDispute(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewDisputeRepo(db, logger.New(testLogger))
+
+	createdAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	disputeID := 7
+
+	tests := []struct {
+		name          string
+		resolution    *entity.DisputeResolution
+		disputeRows   int64
+		bannedRows    int64
+		wantCommitted bool
+		error         error
+	}{
+		{
+			name: "dispute is resolved with refund and penalty",
+			resolution: &entity.DisputeResolution{
+				DisputeID:  disputeID,
+				Status:     entity.DisputeResolved,
+				Resolution: "parcel is damaged by courier",
+				Entries: []*entity.LedgerEntry{
+					{UserID: 2, DeliveryID: 1, DisputeID: &disputeID, Kind: entity.LedgerRefund, Amount: 500},
+					{UserID: 3, DeliveryID: 1, DisputeID: &disputeID, Kind: entity.LedgerPenalty, Amount: -200},
+				},
+			},
+			disputeRows:   1,
+			wantCommitted: true,
+		},
+		{
+			name: "dispute is rejected without entries",
+			resolution: &entity.DisputeResolution{
+				DisputeID:  disputeID,
+				Status:     entity.DisputeRejected,
+				Resolution: "no evidence",
+				Entries:    []*entity.LedgerEntry{},
+			},
+			disputeRows:   1,
+			wantCommitted: true,
+		},
+		{
+			name: "penalized user is banned with the resolution",
+			resolution: &entity.DisputeResolution{
+				DisputeID:  disputeID,
+				Status:     entity.DisputeResolved,
+				Resolution: "courier stole the parcel",
+				Entries: []*entity.LedgerEntry{
+					{UserID: 3, DeliveryID: 1, DisputeID: &disputeID, Kind: entity.LedgerPenalty, Amount: -200},
+				},
+				BannedID: 3,
+			},
+			disputeRows:   1,
+			bannedRows:    1,
+			wantCommitted: true,
+		},
+		{
+			name: "failed ban rolls back the resolution",
+			resolution: &entity.DisputeResolution{
+				DisputeID:  disputeID,
+				Status:     entity.DisputeRejected,
+				Resolution: "no evidence",
+				Entries:    []*entity.LedgerEntry{},
+				BannedID:   3,
+			},
+			disputeRows: 1,
+			bannedRows:  0,
+			error:       fmt.Errorf("expected to affect 1 row, affected 0"),
+		},
+		{
+			name: "dispute is already closed",
+			resolution: &entity.DisputeResolution{
+				DisputeID:  disputeID,
+				Status:     entity.DisputeRejected,
+				Resolution: "no evidence",
+				Entries:    []*entity.LedgerEntry{},
+			},
+			disputeRows: 0,
+			error:       fmt.Errorf("dispute is already closed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`
+				UPDATE disputes
+				SET status = $1, resolution = $2, updated_at = now(), resolved_at = now()
+				WHERE id = $3 AND status IN ('open', 'in_progress')
+			`)).
+				WithArgs(tt.resolution.Status, tt.resolution.Resolution, tt.resolution.DisputeID).
+				WillReturnResult(sqlmock.NewResult(0, tt.disputeRows))
+
+			if tt.disputeRows == 1 {
+				for i, entry := range tt.resolution.Entries {
+					mock.ExpectQuery(regexp.QuoteMeta(`
+						INSERT INTO ledger_entries(user_id, delivery_id, dispute_id, kind, amount)
+						VALUES ($1, $2, $3, $4, $5)
+						RETURNING id, created_at
+					`)).
+						WithArgs(entry.UserID, entry.DeliveryID, entry.DisputeID, entry.Kind, entry.Amount).
+						WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(i+1, createdAt))
+				}
+			}
+
+			if tt.resolution.BannedID != 0 {
+				mock.ExpectExec(regexp.QuoteMeta(banUserQuery)).
+					WithArgs(tt.resolution.BannedID).
+					WillReturnResult(sqlmock.NewResult(0, tt.bannedRows))
+				if tt.bannedRows == 1 {
+					expectOutboxEvent(mock, entity.AggregateUser, tt.resolution.BannedID, entity.UserBanned)
+				}
+			}
+
+			if tt.wantCommitted {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := repo.ResolveDispute(context.Background(), tt.resolution)
+			require.Nil(t, deep.Equal(tt.error, err))
+			for i, entry := range tt.resolution.Entries {
+				require.Equal(t, i+1, entry.ID)
+				require.Equal(t, createdAt, entry.CreatedAt)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
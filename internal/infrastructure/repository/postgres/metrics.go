@@ -22,7 +22,7 @@ func NewMetricsRepo(db *sql.DB, l *logger.Logger) *MetricsRepo {
 // GetDeliveriesCntPerDay fetches new and completed deliveries' counts per last 24 hours
 // and differences in percents between previous and current day for corresponding values
 // from the database and returns it
-func (mr *MetricsRepo) GetDeliveriesCntPerDay(ctx context.Context) (*dto.DeliveriesCntPerDay, error) {
+func (mr *MetricsRepo) GetDeliveriesCntPerDay(ctx context.Context, cityID int) (*dto.DeliveriesCntPerDay, error) {
 	tx, err := mr.Begin()
 	if err != nil {
 		mr.appLogger.Error(err)
@@ -36,9 +36,10 @@ func (mr *MetricsRepo) GetDeliveriesCntPerDay(ctx context.Context) (*dto.Deliver
 		SELECT COUNT(*) as cnt
 		FROM deliveries
 		WHERE EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400
+			AND ($1 = 0 OR city_id = $1)
 	`
 	// Count new deliveries per last 24 hours
-	rowNewToday := mr.QueryRowContext(ctx, queryNewToday)
+	rowNewToday := mr.QueryRowContext(ctx, queryNewToday, cityID)
 
 	err = rowNewToday.Scan(&resp.NewCnt)
 	if err == sql.ErrNoRows {
@@ -53,11 +54,12 @@ func (mr *MetricsRepo) GetDeliveriesCntPerDay(ctx context.Context) (*dto.Deliver
 		FROM deliveries
 		WHERE EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400
 			AND status_id=3
+			AND ($1 = 0 OR city_id = $1)
 		GROUP BY status_id
 	`
 
 	// Count completed deliveries per last 24 hours
-	rowCompletedToday := mr.QueryRowContext(ctx, queryCompletedToday)
+	rowCompletedToday := mr.QueryRowContext(ctx, queryCompletedToday, cityID)
 
 	// Count completed deliveries per last 24 hours
 	err = rowCompletedToday.Scan(&resp.CompletedCnt)
@@ -76,10 +78,11 @@ func (mr *MetricsRepo) GetDeliveriesCntPerDay(ctx context.Context) (*dto.Deliver
 		FROM deliveries
 		WHERE EXTRACT(EPOCH FROM (NOW() - created_at)) >= 86400
 			AND EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400 * 2
+			AND ($1 = 0 OR city_id = $1)
 	`
 
 	// Count new deliveries per previous 24 hours
-	rowNewYesterday := mr.QueryRowContext(ctx, queryNewYesterday)
+	rowNewYesterday := mr.QueryRowContext(ctx, queryNewYesterday, cityID)
 
 	err = rowNewYesterday.Scan(&newYesterdayCnt)
 	if err == sql.ErrNoRows {
@@ -108,11 +111,12 @@ func (mr *MetricsRepo) GetDeliveriesCntPerDay(ctx context.Context) (*dto.Deliver
 		WHERE EXTRACT(EPOCH FROM (NOW() - created_at)) >= 86400
 			AND EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400 * 2
 			AND status_id=3
+			AND ($1 = 0 OR city_id = $1)
 		GROUP BY status_id
 	`
 
 	// Count completed deliveries per previous 24 hours
-	rowCompletedYesterday := mr.QueryRowContext(ctx, queryCompletedYesterday)
+	rowCompletedYesterday := mr.QueryRowContext(ctx, queryCompletedYesterday, cityID)
 
 	err = rowCompletedYesterday.Scan(&completedYesterdayCnt)
 	if err == sql.ErrNoRows {
@@ -144,7 +148,7 @@ func (mr *MetricsRepo) GetDeliveriesCntPerDay(ctx context.Context) (*dto.Deliver
 
 // GetRevenuePerDay fetches revenue sum per last 24 hours and difference in percents
 // between previous and current day for revenue from the database and returns it
-func (mr *MetricsRepo) GetRevenuePerDay(ctx context.Context) (*dto.RevenuePerDay, error) {
+func (mr *MetricsRepo) GetRevenuePerDay(ctx context.Context, cityID int) (*dto.RevenuePerDay, error) {
 	tx, err := mr.Begin()
 	if err != nil {
 		mr.appLogger.Error(err)
@@ -159,10 +163,11 @@ func (mr *MetricsRepo) GetRevenuePerDay(ctx context.Context) (*dto.RevenuePerDay
 		FROM deliveries
 		WHERE EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400
 			AND status_id = 3
+			AND ($1 = 0 OR city_id = $1)
 	`
 
 	// Revenue sum per last 24 hours
-	rowRevenueToday := mr.QueryRowContext(ctx, queryRevenueToday)
+	rowRevenueToday := mr.QueryRowContext(ctx, queryRevenueToday, cityID)
 
 	err = rowRevenueToday.Scan(&resp.Revenue)
 	if err != nil {
@@ -179,10 +184,11 @@ func (mr *MetricsRepo) GetRevenuePerDay(ctx context.Context) (*dto.RevenuePerDay
 		WHERE EXTRACT(EPOCH FROM (NOW() - created_at)) >= 86400
 			AND EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400 * 2
 			AND status_id = 3
+			AND ($1 = 0 OR city_id = $1)
 	`
 
 	// Revenue sum per previous 24 hours
-	rowRevenueYesterday := mr.QueryRowContext(ctx, queryRevenueYesterday)
+	rowRevenueYesterday := mr.QueryRowContext(ctx, queryRevenueYesterday, cityID)
 
 	err = rowRevenueYesterday.Scan(&revenueYesterday)
 	if err != nil {
@@ -213,7 +219,7 @@ func (mr *MetricsRepo) GetRevenuePerDay(ctx context.Context) (*dto.RevenuePerDay
 // GetNewClientsCntPerDay fetches new registered clients' count per last 24 hours
 // and difference in percents between previous and current day for new registered clients' count
 // from the database and returns itfrom the database and returns it
-func (mr *MetricsRepo) GetNewClientsCntPerDay(ctx context.Context) (*dto.NewClientsCntPerDay, error) {
+func (mr *MetricsRepo) GetNewClientsCntPerDay(ctx context.Context, cityID int) (*dto.NewClientsCntPerDay, error) {
 	tx, err := mr.Begin()
 	if err != nil {
 		mr.appLogger.Error(err)
@@ -229,10 +235,11 @@ func (mr *MetricsRepo) GetNewClientsCntPerDay(ctx context.Context) (*dto.NewClie
 		WHERE EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400 
 			AND meta.is_courier = FALSE
 			AND meta.is_admin = FALSE
+			AND ($1 = 0 OR meta.city_id = $1)
 	`
 
 	// New registered clients' count per last 24 hours
-	rowCntToday := mr.QueryRowContext(ctx, queryCntToday)
+	rowCntToday := mr.QueryRowContext(ctx, queryCntToday, cityID)
 
 	err = rowCntToday.Scan(&resp.NewClientsCnt)
 	if err != nil {
@@ -250,10 +257,11 @@ func (mr *MetricsRepo) GetNewClientsCntPerDay(ctx context.Context) (*dto.NewClie
 		AND EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400 * 2
 		AND meta.is_courier = FALSE
 		AND meta.is_admin = FALSE
+		AND ($1 = 0 OR meta.city_id = $1)
 `
 
 	// New registered clients' count per previous 24 hours
-	rowCntYesterday := mr.QueryRowContext(ctx, queryCntYesterday)
+	rowCntYesterday := mr.QueryRowContext(ctx, queryCntYesterday, cityID)
 
 	err = rowCntYesterday.Scan(&cntYesterday)
 	if err != nil {
@@ -283,15 +291,16 @@ func (mr *MetricsRepo) GetNewClientsCntPerDay(ctx context.Context) (*dto.NewClie
 
 // GetDeliveryTypesPercentPerDay fetches different delivery types' percentages per last 24 hours
 // from the database and returns it
-func (mr *MetricsRepo) GetDeliveryTypesPercentPerDay(ctx context.Context) (*dto.DeliveryTypesPercentPerDay, error) {
+func (mr *MetricsRepo) GetDeliveryTypesPercentPerDay(ctx context.Context, cityID int) (*dto.DeliveryTypesPercentPerDay, error) {
 	query := `
 		SELECT type_id, ROUND(COUNT(type_id) / SUM(COUNT(type_id)) OVER() * 100, 3)
 		FROM deliveries
 		WHERE EXTRACT(EPOCH FROM (NOW() - created_at)) < 86400
+			AND ($1 = 0 OR city_id = $1)
 		GROUP BY type_id
 		ORDER BY type_id 
     `
-	rows, err := mr.QueryContext(ctx, query)
+	rows, err := mr.QueryContext(ctx, query, cityID)
 	if err != nil {
 		mr.appLogger.Error(err)
 		return nil, err
@@ -328,15 +337,54 @@ func (mr *MetricsRepo) GetDeliveryTypesPercentPerDay(ctx context.Context) (*dto.
 	return resp, nil
 }
 
+// GetZonesPerDay fetches new and completed deliveries' counts and revenue
+// of every service zone per last 24 hours from the database and returns it
+func (mr *MetricsRepo) GetZonesPerDay(ctx context.Context, cityID int) ([]*dto.ZoneMetricsPerDay, error) {
+	query := `
+		SELECT service_zones.id, service_zones.name, COUNT(deliveries.id),
+			COUNT(deliveries.id) FILTER (WHERE status_id = 3),
+			COALESCE(SUM(price) FILTER (WHERE status_id = 3), 0)::bigint
+		FROM service_zones
+		LEFT JOIN deliveries ON deliveries.zone_id = service_zones.id
+			AND EXTRACT(EPOCH FROM (NOW() - deliveries.created_at)) < 86400
+			AND ($1 = 0 OR deliveries.city_id = $1)
+		GROUP BY service_zones.id, service_zones.name
+		ORDER BY service_zones.id
+	`
+	rows, err := mr.QueryContext(ctx, query, cityID)
+	if err != nil {
+		mr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := make([]*dto.ZoneMetricsPerDay, 0)
+	for rows.Next() {
+		zone := &dto.ZoneMetricsPerDay{}
+		if err := rows.Scan(&zone.ZoneID, &zone.Name, &zone.NewCnt, &zone.CompletedCnt, &zone.Revenue); err != nil {
+			mr.appLogger.Error(err)
+			return nil, err
+		}
+		resp = append(resp, zone)
+	}
+
+	if err = rows.Err(); err != nil {
+		mr.appLogger.Error(err)
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetCurrentDeliveries fetches list of brief information about current deliveries
 // from the database and returns it
-func (mr *MetricsRepo) GetCurrentDeliveries(context.Context) (*dto.MetricsDeliveriesResponse, error) {
+func (mr *MetricsRepo) GetCurrentDeliveries(ctx context.Context, cityID int) (*dto.MetricsDeliveriesResponse, error) {
 	query := `
 		SELECT from_object, from_longitude, from_latitude, to_object, price
 		FROM deliveries INNER JOIN geo ON deliveries.geo_id = geo.id
 		WHERE status_id = 1
+			AND ($1 = 0 OR city_id = $1)
 	`
-	rows, err := mr.QueryContext(context.Background(), query)
+	rows, err := mr.QueryContext(ctx, query, cityID)
 	if err != nil {
 		mr.appLogger.Error(err)
 		return nil, err
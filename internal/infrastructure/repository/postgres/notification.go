@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// NotificationRepo is a struct that provides
+// all functions to execute SQL queries
+// related to notifications
+type NotificationRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewNotificationRepo(db *sql.DB, l *logger.Logger) *NotificationRepo {
+	return &NotificationRepo{db, l}
+}
+
+// GetPreferences fetches notification preferences of the user,
+// defaults are returned if the user hasn't set them
+func (nr *NotificationRepo) GetPreferences(ctx context.Context, userID int) (*entity.NotificationPreferences, error) {
+	query := `
+		SELECT COALESCE(email_enabled, TRUE), COALESCE(sms_enabled, FALSE),
+		       COALESCE(push_enabled, TRUE), COALESCE(push_token, '')
+		FROM users
+		LEFT JOIN notification_preferences ON users.id = notification_preferences.user_id
+		WHERE users.id = $1
+	`
+	prefs := &entity.NotificationPreferences{UserID: userID}
+	err := nr.QueryRowContext(ctx, query, userID).Scan(&prefs.EmailEnabled, &prefs.SMSEnabled,
+		&prefs.PushEnabled, &prefs.PushToken)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("user is not found")
+		nr.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		nr.appLogger.Error(err)
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// SetPreferences creates or replaces notification preferences of the user
+func (nr *NotificationRepo) SetPreferences(ctx context.Context, prefs *entity.NotificationPreferences) error {
+	query := `
+		INSERT INTO notification_preferences(user_id, email_enabled, sms_enabled, push_enabled, push_token)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email_enabled = $2, sms_enabled = $3, push_enabled = $4, push_token = $5
+	`
+	_, err := nr.ExecContext(ctx, query, prefs.UserID, prefs.EmailEnabled, prefs.SMSEnabled,
+		prefs.PushEnabled, prefs.PushToken)
+	if err != nil {
+		nr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetRecipient fetches contacts of the user with notification preferences
+func (nr *NotificationRepo) GetRecipient(ctx context.Context, userID int) (*entity.NotificationRecipient, error) {
+	query := `
+		SELECT email, phone_number
+		FROM users
+		WHERE id = $1
+	`
+	recipient := &entity.NotificationRecipient{UserID: userID}
+	err := nr.QueryRowContext(ctx, query, userID).Scan(&recipient.Email, &recipient.PhoneNumber)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("user is not found")
+		nr.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		nr.appLogger.Error(err)
+		return nil, err
+	}
+
+	recipient.Preferences, err = nr.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return recipient, nil
+}
+
+// GetDeliveryNotice fetches participants, addresses, dropoff contact's phone and PIN of the delivery
+func (nr *NotificationRepo) GetDeliveryNotice(ctx context.Context, deliveryID int) (*entity.DeliveryNotice, error) {
+	query := `
+		SELECT deliveries.id, client_id, courier_id, geo.from_object, geo.to_object, price,
+			COALESCE(delivery_contacts.phone_number, ''), COALESCE(delivery_proofs.pin, '')
+		FROM deliveries
+		INNER JOIN geo ON deliveries.geo_id = geo.id
+		LEFT JOIN delivery_contacts ON delivery_contacts.delivery_id = deliveries.id AND stop_type = $2
+		LEFT JOIN delivery_proofs ON delivery_proofs.delivery_id = deliveries.id
+		WHERE deliveries.id = $1
+	`
+	notice := &entity.DeliveryNotice{}
+	var courierID sql.NullInt64
+	err := nr.QueryRowContext(ctx, query, deliveryID, entity.StopDropoff).Scan(&notice.DeliveryID, &notice.ClientID,
+		&courierID, &notice.FromObject, &notice.ToObject, &notice.Price, &notice.DropoffPhone, &notice.PIN)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("delivery is not found")
+		nr.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		nr.appLogger.Error(err)
+		return nil, err
+	}
+
+	notice.CourierID = int(courierID.Int64)
+	return notice, nil
+}
+
+// CreateNotification queues the notification for sending
+func (nr *NotificationRepo) CreateNotification(ctx context.Context, n *entity.Notification) error {
+	query := `
+		INSERT INTO notifications(user_id, event, channel, recipient, subject, body, ref_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0))
+		RETURNING id, status, next_attempt_at, created_at
+	`
+	err := nr.QueryRowContext(ctx, query, n.UserID, n.Event, n.Channel, n.Recipient, n.Subject,
+		n.Body, n.RefID).Scan(&n.ID, &n.Status, &n.NextAttemptAt, &n.CreatedAt)
+	if err != nil {
+		nr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// ClaimNotifications takes pending notifications due for sending and counts the attempt,
+// they are postponed by the lease so that other workers skip them while they are being sent
+func (nr *NotificationRepo) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]*entity.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET attempts = attempts + 1, next_attempt_at = now() + $2 * interval '1 second'
+		WHERE id IN (
+		    SELECT id
+		    FROM notifications
+		    WHERE status = 'pending' AND next_attempt_at <= now()
+		    ORDER BY next_attempt_at
+		    LIMIT $1
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, event, channel, recipient, subject, body, COALESCE(ref_id, 0), status,
+		          attempts, last_error, next_attempt_at, created_at
+	`
+	rows, err := nr.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		nr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.Notification, 0)
+	for rows.Next() {
+		result := &entity.Notification{}
+		err = rows.Scan(&result.ID, &result.UserID, &result.Event, &result.Channel, &result.Recipient,
+			&result.Subject, &result.Body, &result.RefID, &result.Status, &result.Attempts, &result.LastError,
+			&result.NextAttemptAt, &result.CreatedAt)
+		if err != nil {
+			nr.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		nr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// UpdateNotification saves result of the attempt to send the notification
+func (nr *NotificationRepo) UpdateNotification(ctx context.Context, n *entity.Notification) error {
+	query := `
+		UPDATE notifications
+		SET status = $1, last_error = $2, next_attempt_at = $3, sent_at = $4
+		WHERE id = $5
+	`
+	_, err := nr.ExecContext(ctx, query, n.Status, n.LastError, n.NextAttemptAt, n.SentAt, n.ID)
+	if err != nil {
+		nr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRepo_ClaimNotifications(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewNotificationRepo(db, logger.New(testLogger))
+
+	columns := []string{"id", "user_id", "event", "channel", "recipient", "subject", "body", "ref_id", "status",
+		"attempts", "last_error", "next_attempt_at", "created_at"}
+	createdAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	leasedUntil := createdAt.Add(time.Minute)
+
+	tests := []struct {
+		name string
+		rows *sqlmock.Rows
+		want []*entity.Notification
+	}{
+		{
+			name: "pending notifications are claimed",
+			rows: sqlmock.NewRows(columns).
+				AddRow(1, 2, entity.NotifyDeliveryAccepted, entity.ChannelEmail, "client@mail.com",
+					"Courier is on the way", "Delivery #5 is accepted", 0, entity.NotificationPending, 1, "",
+					leasedUntil, createdAt).
+				AddRow(2, 2, entity.NotifyDeliveryAccepted, entity.ChannelSMS, "+79990000000",
+					"Courier is on the way", "Delivery #5 is accepted", 0, entity.NotificationPending, 3,
+					"gateway is unavailable", leasedUntil, createdAt).
+				AddRow(3, 4, entity.NotifyPasswordReset, entity.ChannelEmail, "user@mail.com", "", "", 7,
+					entity.NotificationPending, 1, "", leasedUntil, createdAt),
+			want: []*entity.Notification{
+				{
+					ID: 1, UserID: 2, Event: entity.NotifyDeliveryAccepted, Channel: entity.ChannelEmail,
+					Recipient: "client@mail.com", Subject: "Courier is on the way", Body: "Delivery #5 is accepted",
+					Status: entity.NotificationPending, Attempts: 1, NextAttemptAt: leasedUntil, CreatedAt: createdAt,
+				},
+				{
+					ID: 2, UserID: 2, Event: entity.NotifyDeliveryAccepted, Channel: entity.ChannelSMS,
+					Recipient: "+79990000000", Subject: "Courier is on the way", Body: "Delivery #5 is accepted",
+					Status: entity.NotificationPending, Attempts: 3, LastError: "gateway is unavailable",
+					NextAttemptAt: leasedUntil, CreatedAt: createdAt,
+				},
+				{
+					ID: 3, UserID: 4, Event: entity.NotifyPasswordReset, Channel: entity.ChannelEmail,
+					Recipient: "user@mail.com", RefID: 7, Status: entity.NotificationPending, Attempts: 1,
+					NextAttemptAt: leasedUntil, CreatedAt: createdAt,
+				},
+			},
+		},
+		{
+			name: "nothing is due",
+			rows: sqlmock.NewRows(columns),
+			want: []*entity.Notification{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				UPDATE notifications
+				SET attempts = attempts + 1, next_attempt_at = now() + $2 * interval '1 second'
+				WHERE id IN (
+				    SELECT id
+				    FROM notifications
+				    WHERE status = 'pending' AND next_attempt_at <= now()
+				    ORDER BY next_attempt_at
+				    LIMIT $1
+				    FOR UPDATE SKIP LOCKED
+				)
+				RETURNING id, user_id, event, channel, recipient, subject, body, COALESCE(ref_id, 0), status,
+				          attempts, last_error, next_attempt_at, created_at
+			`)).
+				WithArgs(10, float64(60)).
+				WillReturnRows(tt.rows)
+
+			got, err := repo.ClaimNotifications(context.Background(), 10, time.Minute)
+			require.NoError(t, err)
+			require.Nil(t, deep.Equal(tt.want, got))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,542 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// OrganizationRepo is a struct that provides
+// all functions to execute SQL queries
+// related to organizations, their members,
+// invitations and shared address book
+type OrganizationRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewOrganizationRepo(db *sql.DB, l *logger.Logger) *OrganizationRepo {
+	return &OrganizationRepo{db, l}
+}
+
+// CreateOrganization creates a new organization with the user as its owner
+// and attaches id of the organization to it
+func (or *OrganizationRepo) CreateOrganization(ctx context.Context, org *entity.Organization, ownerID int) error {
+	tx, err := or.Begin()
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query1 := `
+		INSERT INTO organizations(name, legal_name, tax_id, billing_email, billing_address)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, query1, org.Name, org.LegalName, org.TaxID, org.BillingEmail,
+		org.BillingAddress).Scan(&org.ID, &org.CreatedAt)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+
+	query2 := `
+		INSERT INTO organization_members(organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`
+	_, err = tx.ExecContext(ctx, query2, org.ID, ownerID, entity.OrgRoleOwner)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	org.Role = entity.OrgRoleOwner
+
+	if err = tx.Commit(); err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateOrganization updates name and billing details of the organization
+func (or *OrganizationRepo) UpdateOrganization(ctx context.Context, org *entity.Organization) error {
+	query := `
+		UPDATE organizations
+		SET name = $1, legal_name = $2, tax_id = $3, billing_email = $4, billing_address = $5
+		WHERE id = $6
+	`
+	result, err := or.ExecContext(ctx, query, org.Name, org.LegalName, org.TaxID, org.BillingEmail,
+		org.BillingAddress, org.ID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return checkAffected(result, "organization is not found", or.appLogger)
+}
+
+// GetOrganizationByID fetches the organization by its id
+func (or *OrganizationRepo) GetOrganizationByID(ctx context.Context, orgID int) (*entity.Organization, error) {
+	query := `
+		SELECT id, name, legal_name, tax_id, billing_email, billing_address, created_at
+		FROM organizations
+		WHERE id = $1
+	`
+	org := &entity.Organization{}
+	err := or.QueryRowContext(ctx, query, orgID).Scan(&org.ID, &org.Name, &org.LegalName, &org.TaxID,
+		&org.BillingEmail, &org.BillingAddress, &org.CreatedAt)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("organization is not found")
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	return org, nil
+}
+
+// GetUserOrganizations fetches organizations the user is a member of with the user's roles
+func (or *OrganizationRepo) GetUserOrganizations(ctx context.Context, userID int) ([]*entity.Organization, error) {
+	query := `
+		SELECT o.id, o.name, o.legal_name, o.tax_id, o.billing_email, o.billing_address, o.created_at, m.role
+		FROM organizations o
+		INNER JOIN organization_members m ON o.id = m.organization_id
+		WHERE m.user_id = $1
+		ORDER BY o.name, o.id
+	`
+	rows, err := or.QueryContext(ctx, query, userID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.Organization, 0)
+	for rows.Next() {
+		org := &entity.Organization{}
+		err = rows.Scan(&org.ID, &org.Name, &org.LegalName, &org.TaxID, &org.BillingEmail,
+			&org.BillingAddress, &org.CreatedAt, &org.Role)
+		if err != nil {
+			or.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, org)
+	}
+
+	if err = rows.Err(); err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetMemberRole fetches role of the user in the organization,
+// empty role is returned if the user is not its member
+func (or *OrganizationRepo) GetMemberRole(ctx context.Context, orgID, userID int) (string, error) {
+	query := `
+		SELECT role
+		FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2
+	`
+	var role string
+	err := or.QueryRowContext(ctx, query, orgID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		or.appLogger.Error(err)
+		return "", err
+	}
+	return role, nil
+}
+
+// GetMembers fetches members of the organization, owners go first
+func (or *OrganizationRepo) GetMembers(ctx context.Context, orgID int) ([]*entity.OrganizationMember, error) {
+	query := `
+		SELECT u.id, u.surname, u.name, u.email, m.role, m.joined_at
+		FROM organization_members m
+		INNER JOIN users u ON m.user_id = u.id
+		WHERE m.organization_id = $1
+		ORDER BY array_position(ARRAY['owner', 'manager', 'requester'], m.role::text), u.surname, u.name
+	`
+	rows, err := or.QueryContext(ctx, query, orgID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.OrganizationMember, 0)
+	for rows.Next() {
+		member := &entity.OrganizationMember{}
+		err = rows.Scan(&member.UserID, &member.Surname, &member.Name, &member.Email, &member.Role, &member.JoinedAt)
+		if err != nil {
+			or.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// UpdateMemberRole changes role of the organization's member
+func (or *OrganizationRepo) UpdateMemberRole(ctx context.Context, orgID, userID int, role string) error {
+	query := `
+		UPDATE organization_members
+		SET role = $1
+		WHERE organization_id = $2 AND user_id = $3
+	`
+	result, err := or.ExecContext(ctx, query, role, orgID, userID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return checkAffected(result, "member is not found", or.appLogger)
+}
+
+// DeleteMember removes the member from the organization,
+// deliveries requested by the member stay with the organization
+func (or *OrganizationRepo) DeleteMember(ctx context.Context, orgID, userID int) error {
+	query := `
+		DELETE FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2
+	`
+	result, err := or.ExecContext(ctx, query, orgID, userID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return checkAffected(result, "member is not found", or.appLogger)
+}
+
+// CreateInvitation creates invitation to the organization replacing
+// pending invitations sent to the same email
+func (or *OrganizationRepo) CreateInvitation(ctx context.Context, inv *entity.OrganizationInvitation) error {
+	tx, err := or.Begin()
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query1 := `
+		DELETE FROM organization_invitations
+		WHERE organization_id = $1 AND lower(email) = lower($2) AND accepted_at IS NULL
+	`
+	_, err = tx.ExecContext(ctx, query1, inv.OrganizationID, inv.Email)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+
+	query2 := `
+		INSERT INTO organization_invitations(organization_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, query2, inv.OrganizationID, inv.Email, inv.Role, inv.TokenHash,
+		inv.InvitedBy, inv.ExpiresAt).Scan(&inv.ID, &inv.CreatedAt)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// RenewInvitation replaces hash of the invitation's token if it is not accepted or expired yet,
+// the invitation is returned without the hash. Nil is returned if it is no longer valid
+func (or *OrganizationRepo) RenewInvitation(ctx context.Context, id int, tokenHash string) (*entity.OrganizationInvitation, error) {
+	query := `
+		UPDATE organization_invitations
+		SET token_hash = $2
+		WHERE id = $1 AND accepted_at IS NULL AND expires_at > now()
+		RETURNING id, organization_id, email, role, COALESCE(invited_by, 0), expires_at, created_at
+	`
+	inv := &entity.OrganizationInvitation{}
+	err := or.QueryRowContext(ctx, query, id, tokenHash).Scan(&inv.ID, &inv.OrganizationID, &inv.Email, &inv.Role,
+		&inv.InvitedBy, &inv.ExpiresAt, &inv.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	return inv, nil
+}
+
+// AcceptInvitation marks the invitation sent to the user's email as accepted
+// and adds the user to the organization, id of the organization is returned.
+// Role of the user who is already a member is not changed
+func (or *OrganizationRepo) AcceptInvitation(ctx context.Context, tokenHash string, userID int, email string) (int, error) {
+	tx, err := or.Begin()
+	if err != nil {
+		or.appLogger.Error(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query1 := `
+		UPDATE organization_invitations
+		SET accepted_at = now()
+		WHERE token_hash = $1 AND lower(email) = lower($2) AND accepted_at IS NULL AND expires_at > now()
+		RETURNING organization_id, role
+	`
+	var orgID int
+	var role string
+	err = tx.QueryRowContext(ctx, query1, tokenHash, email).Scan(&orgID, &role)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("invitation is invalid or expired")
+		or.appLogger.Error(err)
+		return 0, err
+	}
+	if err != nil {
+		or.appLogger.Error(err)
+		return 0, err
+	}
+
+	query2 := `
+		INSERT INTO organization_members(organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, user_id) DO NOTHING
+	`
+	_, err = tx.ExecContext(ctx, query2, orgID, userID, role)
+	if err != nil {
+		or.appLogger.Error(err)
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		or.appLogger.Error(err)
+		return 0, err
+	}
+	return orgID, nil
+}
+
+// CreateOrganizationPlace creates a new place in the organization's address book
+// and attaches its id to the place
+func (or *OrganizationRepo) CreateOrganizationPlace(ctx context.Context, place *entity.SavedPlace) error {
+	query := `
+		INSERT INTO saved_places(organization_id, label, latitude, longitude, object, entrance, floor, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+	err := or.QueryRowContext(ctx, query, place.OrganizationID, place.Label, place.Latitude, place.Longitude,
+		place.Object, place.Entrance, place.Floor, place.Notes).Scan(&place.ID, &place.CreatedAt)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateOrganizationPlace updates the place of the organization's address book
+func (or *OrganizationRepo) UpdateOrganizationPlace(ctx context.Context, place *entity.SavedPlace) error {
+	query := `
+		UPDATE saved_places
+		SET label = $1, latitude = $2, longitude = $3, object = $4, entrance = $5, floor = $6, notes = $7
+		WHERE id = $8 AND organization_id = $9
+	`
+	result, err := or.ExecContext(ctx, query, place.Label, place.Latitude, place.Longitude,
+		place.Object, place.Entrance, place.Floor, place.Notes, place.ID, place.OrganizationID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return checkPlaceAffected(result, or.appLogger)
+}
+
+// DeleteOrganizationPlace deletes the place of the organization's address book
+func (or *OrganizationRepo) DeleteOrganizationPlace(ctx context.Context, orgID, placeID int) error {
+	query := `
+		DELETE FROM saved_places
+		WHERE id = $1 AND organization_id = $2
+	`
+	result, err := or.ExecContext(ctx, query, placeID, orgID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return checkPlaceAffected(result, or.appLogger)
+}
+
+// GetOrganizationPlaces fetches all places of the organization's address book ordered by label
+func (or *OrganizationRepo) GetOrganizationPlaces(ctx context.Context, orgID int) ([]*entity.SavedPlace, error) {
+	query := `
+		SELECT id, organization_id, label, latitude, longitude, object, entrance, floor, notes, created_at
+		FROM saved_places
+		WHERE organization_id = $1
+		ORDER BY label, id
+	`
+	rows, err := or.QueryContext(ctx, query, orgID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.SavedPlace, 0)
+	for rows.Next() {
+		result := &entity.SavedPlace{}
+		err = rows.Scan(&result.ID, &result.OrganizationID, &result.Label, &result.Latitude, &result.Longitude,
+			&result.Object, &result.Entrance, &result.Floor, &result.Notes, &result.CreatedAt)
+		if err != nil {
+			or.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetOrganizationPlaceByID fetches the place of the organization's address book,
+// places of other organizations are reported as not found
+func (or *OrganizationRepo) GetOrganizationPlaceByID(ctx context.Context, orgID, placeID int) (*entity.SavedPlace, error) {
+	query := `
+		SELECT id, organization_id, label, latitude, longitude, object, entrance, floor, notes, created_at
+		FROM saved_places
+		WHERE id = $1 AND organization_id = $2
+	`
+	place := &entity.SavedPlace{}
+	err := or.QueryRowContext(ctx, query, placeID, orgID).Scan(&place.ID, &place.OrganizationID, &place.Label,
+		&place.Latitude, &place.Longitude, &place.Object, &place.Entrance, &place.Floor, &place.Notes, &place.CreatedAt)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("saved place is not found")
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	return place, nil
+}
+
+// CountOrganizationPlaces counts places of the organization's address book
+func (or *OrganizationRepo) CountOrganizationPlaces(ctx context.Context, orgID int) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM saved_places
+		WHERE organization_id = $1
+	`
+	var cnt int
+	err := or.QueryRowContext(ctx, query, orgID).Scan(&cnt)
+	if err != nil {
+		or.appLogger.Error(err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// GetOrganizationDeliveries fetches page of the organization's deliveries,
+// client id 0 stands for deliveries requested by all members
+func (or *OrganizationRepo) GetOrganizationDeliveries(ctx context.Context, orgID, clientID, page int) ([]*dto.DeliveryBriefResponse, error) {
+	query := `
+	SELECT deliveries.id, type_id, has_loader, status_id, price, geo.from_object, geo.to_object, geo.distance, created_at
+	FROM deliveries INNER JOIN geo ON deliveries.geo_id = geo.id
+	WHERE organization_id = $1 AND ($2 = 0 OR client_id = $2)
+	ORDER BY deliveries.id DESC
+	LIMIT 10 OFFSET $3
+	`
+	rows, err := or.QueryContext(ctx, query, orgID, clientID, (page-1)*10)
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*dto.DeliveryBriefResponse, 0)
+	for rows.Next() {
+		result := &dto.DeliveryBriefResponse{}
+		err = rows.Scan(&result.ID, &result.TypeID, &result.HasLoader, &result.StatusID, &result.Price,
+			&result.FromObject, &result.ToObject, &result.Distance, &result.Time)
+		if err != nil {
+			or.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetOrganizationSpending fetches spending of the organization's members on deliveries
+// created within the period, cancelled deliveries are not counted
+func (or *OrganizationRepo) GetOrganizationSpending(ctx context.Context, orgID int, from, to time.Time) ([]*dto.MemberSpendingResponse, error) {
+	query := `
+		SELECT u.id, u.surname, u.name, COUNT(d.id), COALESCE(SUM(d.price), 0)
+		FROM deliveries d
+		INNER JOIN users u ON d.client_id = u.id
+		WHERE d.organization_id = $1 AND d.status_id <> 4 AND d.created_at >= $2 AND d.created_at < $3
+		GROUP BY u.id, u.surname, u.name
+		ORDER BY SUM(d.price) DESC, u.id
+	`
+	rows, err := or.QueryContext(ctx, query, orgID, from, to)
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*dto.MemberSpendingResponse, 0)
+	for rows.Next() {
+		result := &dto.MemberSpendingResponse{}
+		err = rows.Scan(&result.UserID, &result.Surname, &result.Name, &result.Deliveries, &result.Total)
+		if err != nil {
+			or.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// checkAffected checks that the query changed exactly one row,
+// otherwise the error with the message is returned
+func checkAffected(result sql.Result, msg string, l *logger.Logger) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		l.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = errors.New(msg)
+		l.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+func TestOrganizationRepo_CreateOrganization(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewOrganizationRepo(db, logger.New(testLogger))
+
+	org := &entity.Organization{Name: "Ромашка", TaxID: "7701234567"}
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		INSERT INTO organizations(name, legal_name, tax_id, billing_email, billing_address)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`)).
+		WithArgs(org.Name, "", org.TaxID, "", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO organization_members(organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`)).
+		WithArgs(3, 7, entity.OrgRoleOwner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.CreateOrganization(context.Background(), org, 7)
+	require.NoError(t, err)
+	require.Nil(t, deep.Equal(&entity.Organization{
+		ID:        3,
+		Name:      "Ромашка",
+		TaxID:     "7701234567",
+		Role:      entity.OrgRoleOwner,
+		CreatedAt: createdAt,
+	}, org))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrganizationRepo_GetMemberRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewOrganizationRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name string
+		rows *sqlmock.Rows
+		want string
+	}{
+		{
+			name: "user is a member",
+			rows: sqlmock.NewRows([]string{"role"}).AddRow(entity.OrgRoleManager),
+			want: entity.OrgRoleManager,
+		},
+		{
+			name: "user is not a member",
+			rows: sqlmock.NewRows([]string{"role"}),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				SELECT role
+				FROM organization_members
+				WHERE organization_id = $1 AND user_id = $2
+			`)).
+				WithArgs(3, 7).
+				WillReturnRows(tt.rows)
+
+			got, err := repo.GetMemberRole(context.Background(), 3, 7)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOrganizationRepo_AcceptInvitation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewOrganizationRepo(db, logger.New(testLogger))
+
+	acceptQuery := `
+		UPDATE organization_invitations
+		SET accepted_at = now()
+		WHERE token_hash = $1 AND lower(email) = lower($2) AND accepted_at IS NULL AND expires_at > now()
+		RETURNING organization_id, role
+	`
+
+	// Accepting the valid invitation adds the user to the organization
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(acceptQuery)).
+		WithArgs("hash", "ivanov@mail.ru").
+		WillReturnRows(sqlmock.NewRows([]string{"organization_id", "role"}).AddRow(3, entity.OrgRoleRequester))
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO organization_members(organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, user_id) DO NOTHING
+	`)).
+		WithArgs(3, 7, entity.OrgRoleRequester).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	orgID, err := repo.AcceptInvitation(context.Background(), "hash", 7, "ivanov@mail.ru")
+	require.NoError(t, err)
+	require.Equal(t, 3, orgID)
+
+	// Invitation sent to another email, expired or used one is rejected
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(acceptQuery)).
+		WithArgs("hash", "petrov@mail.ru").
+		WillReturnRows(sqlmock.NewRows([]string{"organization_id", "role"}))
+	mock.ExpectRollback()
+
+	_, err = repo.AcceptInvitation(context.Background(), "hash", 8, "petrov@mail.ru")
+	require.Nil(t, deep.Equal(fmt.Errorf("invitation is invalid or expired"), err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrganizationRepo_RenewInvitation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewOrganizationRepo(db, logger.New(testLogger))
+
+	renewQuery := `
+		UPDATE organization_invitations
+		SET token_hash = $2
+		WHERE id = $1 AND accepted_at IS NULL AND expires_at > now()
+		RETURNING id, organization_id, email, role, COALESCE(invited_by, 0), expires_at, created_at
+	`
+	columns := []string{"id", "organization_id", "email", "role", "invited_by", "expires_at", "created_at"}
+	createdAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(7 * 24 * time.Hour)
+
+	// Pending invitation gets the new hash
+	mock.ExpectQuery(regexp.QuoteMeta(renewQuery)).
+		WithArgs(5, "hash").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, 3, "ivanov@mail.ru", entity.OrgRoleRequester, 7, expiresAt, createdAt))
+
+	inv, err := repo.RenewInvitation(context.Background(), 5, "hash")
+	require.NoError(t, err)
+	require.Nil(t, deep.Equal(&entity.OrganizationInvitation{
+		ID: 5, OrganizationID: 3, Email: "ivanov@mail.ru", Role: entity.OrgRoleRequester,
+		InvitedBy: 7, ExpiresAt: expiresAt, CreatedAt: createdAt,
+	}, inv))
+
+	// Accepted, replaced or expired invitation is not renewed
+	mock.ExpectQuery(regexp.QuoteMeta(renewQuery)).
+		WithArgs(6, "hash").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	inv, err = repo.RenewInvitation(context.Background(), 6, "hash")
+	require.NoError(t, err)
+	require.Nil(t, inv)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
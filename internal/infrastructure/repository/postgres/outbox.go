@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// OutboxRepo is a struct that provides
+// all functions to execute SQL queries
+// related to outbox of domain events
+type OutboxRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewOutboxRepo(db *sql.DB, l *logger.Logger) *OutboxRepo {
+	return &OutboxRepo{db, l}
+}
+
+// addOutboxEvent writes domain event to the outbox in the transaction of the change it describes
+func addOutboxEvent(ctx context.Context, tx *sql.Tx, aggregateType string, aggregateID int, eventType string, payload interface{}) error {
+	raw := []byte("{}")
+	if payload != nil {
+		var err error
+		raw, err = json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO outbox_events(aggregate_type, aggregate_id, type, payload)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := tx.ExecContext(ctx, query, aggregateType, aggregateID, eventType, string(raw))
+	return err
+}
+
+// ClaimOutboxEvents takes events due for publishing in order of their ids and hides
+// them from other instances for the lease, attempts of the claimed events are counted.
+// Event is not claimed while an earlier event of its aggregate is unpublished,
+// so that events of the same aggregate are published in order
+func (or *OutboxRepo) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]*entity.OutboxEvent, error) {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = now() + $2 * interval '1 second'
+		WHERE id IN (
+		    SELECT e.id
+		    FROM outbox_events e
+		    WHERE e.published_at IS NULL AND e.dead_at IS NULL
+		      AND (e.next_attempt_at IS NULL OR e.next_attempt_at <= now())
+		      AND NOT EXISTS (
+		          SELECT 1
+		          FROM outbox_events p
+		          WHERE p.aggregate_type = e.aggregate_type AND p.aggregate_id = e.aggregate_id
+		            AND p.id < e.id AND p.published_at IS NULL AND p.dead_at IS NULL
+		      )
+		    ORDER BY e.id
+		    LIMIT $1
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, aggregate_type, aggregate_id, type, payload, created_at, attempts, handled_groups
+	`
+	rows, err := or.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*entity.OutboxEvent, 0)
+	for rows.Next() {
+		event := &entity.OutboxEvent{}
+		var payload string
+		err = rows.Scan(&event.ID, &event.AggregateType, &event.AggregateID, &event.Type, &payload,
+			&event.CreatedAt, &event.Attempts, pq.Array(&event.HandledGroups))
+		if err != nil {
+			or.appLogger.Error(err)
+			return nil, err
+		}
+		event.Payload = json.RawMessage(payload)
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		or.appLogger.Error(err)
+		return nil, err
+	}
+
+	// RETURNING doesn't keep order of the subquery
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// MarkOutboxEventPublished marks the event accepted by the bus as published
+func (or *OutboxRepo) MarkOutboxEventPublished(ctx context.Context, id int) error {
+	query := `UPDATE outbox_events SET published_at = now(), last_error = NULL WHERE id = $1`
+	_, err := or.ExecContext(ctx, query, id)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// FailOutboxEvent saves result of the failed attempt to publish the event, the event is
+// published again at nextAttemptAt or is set aside as dead and not published anymore
+func (or *OutboxRepo) FailOutboxEvent(ctx context.Context, event *entity.OutboxEvent, lastError string, nextAttemptAt time.Time, dead bool) error {
+	query := `
+		UPDATE outbox_events
+		SET handled_groups = $1, last_error = $2, next_attempt_at = $3, dead_at = CASE WHEN $4 THEN now() END
+		WHERE id = $5
+	`
+	// nil slice is written as NULL
+	groups := event.HandledGroups
+	if groups == nil {
+		groups = []string{}
+	}
+	_, err := or.ExecContext(ctx, query, pq.Array(groups), lastError, nextAttemptAt, dead, event.ID)
+	if err != nil {
+		or.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeletePublishedOutboxEvents deletes events published before the time
+func (or *OutboxRepo) DeletePublishedOutboxEvents(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM outbox_events WHERE published_at < $1`
+	result, err := or.ExecContext(ctx, query, before)
+	if err != nil {
+		or.appLogger.Error(err)
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		or.appLogger.Error(err)
+		return 0, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// expectOutboxEvent expects domain event of the aggregate written to the outbox
+func expectOutboxEvent(mock sqlmock.Sqlmock, aggregateType string, aggregateID int, eventType string) {
+	mock.ExpectExec(regexp.QuoteMeta(`
+		INSERT INTO outbox_events(aggregate_type, aggregate_id, type, payload)
+		VALUES ($1, $2, $3, $4)
+	`)).
+		WithArgs(aggregateType, aggregateID, eventType, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestOutboxRepo_ClaimOutboxEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewOutboxRepo(db, logger.New(testLogger))
+
+	createdAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = now() + $2 * interval '1 second'
+		WHERE id IN (
+		    SELECT e.id
+		    FROM outbox_events e
+		    WHERE e.published_at IS NULL AND e.dead_at IS NULL
+		      AND (e.next_attempt_at IS NULL OR e.next_attempt_at <= now())
+		      AND NOT EXISTS (
+		          SELECT 1
+		          FROM outbox_events p
+		          WHERE p.aggregate_type = e.aggregate_type AND p.aggregate_id = e.aggregate_id
+		            AND p.id < e.id AND p.published_at IS NULL AND p.dead_at IS NULL
+		      )
+		    ORDER BY e.id
+		    LIMIT $1
+		    FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, aggregate_type, aggregate_id, type, payload, created_at, attempts, handled_groups
+	`
+
+	// Rows are returned out of order to check that events are sorted by id
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(100, time.Minute.Seconds()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "aggregate_type", "aggregate_id", "type", "payload",
+			"created_at", "attempts", "handled_groups"}).
+			AddRow(3, entity.AggregateUser, 3, entity.UserBanned, `{}`, createdAt, 1, `{}`).
+			AddRow(1, entity.AggregateDelivery, 7, entity.DeliveryCreated, `{"price":500}`, createdAt, 3, `{webhooks}`))
+
+	events, err := repo.ClaimOutboxEvents(context.Background(), 100, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, deep.Equal([]*entity.OutboxEvent{
+		{
+			ID:            1,
+			AggregateType: entity.AggregateDelivery,
+			AggregateID:   7,
+			Type:          entity.DeliveryCreated,
+			Payload:       []byte(`{"price":500}`),
+			CreatedAt:     createdAt,
+			Attempts:      3,
+			HandledGroups: []string{"webhooks"},
+		},
+		{
+			ID:            3,
+			AggregateType: entity.AggregateUser,
+			AggregateID:   3,
+			Type:          entity.UserBanned,
+			Payload:       []byte(`{}`),
+			CreatedAt:     createdAt,
+			Attempts:      1,
+			HandledGroups: []string{},
+		},
+	}, events))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepo_FailOutboxEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewOutboxRepo(db, logger.New(testLogger))
+
+	nextAttemptAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		event      *entity.OutboxEvent
+		dead       bool
+		wantGroups []string
+	}{
+		{
+			name:       "event is retried for failed groups",
+			event:      &entity.OutboxEvent{ID: 1, Attempts: 2, HandledGroups: []string{"webhooks"}},
+			wantGroups: []string{"webhooks"},
+		},
+		{
+			name:       "event is dead without handled groups",
+			event:      &entity.OutboxEvent{ID: 2, Attempts: 10},
+			dead:       true,
+			wantGroups: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(regexp.QuoteMeta(`
+				UPDATE outbox_events
+				SET handled_groups = $1, last_error = $2, next_attempt_at = $3, dead_at = CASE WHEN $4 THEN now() END
+				WHERE id = $5
+			`)).
+				WithArgs(pq.Array(tt.wantGroups), "bus is unavailable", nextAttemptAt, tt.dead, tt.event.ID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.FailOutboxEvent(context.Background(), tt.event, "bus is unavailable", nextAttemptAt, tt.dead)
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// SavedPlaceRepo is a struct that provides
+// all functions to execute SQL queries
+// related to user's saved places
+type SavedPlaceRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewSavedPlaceRepo(db *sql.DB, l *logger.Logger) *SavedPlaceRepo {
+	return &SavedPlaceRepo{db, l}
+}
+
+// CreatePlace creates a new saved place record and attaches its id to the place
+func (pr *SavedPlaceRepo) CreatePlace(ctx context.Context, place *entity.SavedPlace) error {
+	query := `
+		INSERT INTO saved_places(user_id, label, latitude, longitude, object, entrance, floor, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+	err := pr.QueryRowContext(ctx, query, place.UserID, place.Label, place.Latitude, place.Longitude,
+		place.Object, place.Entrance, place.Floor, place.Notes).Scan(&place.ID, &place.CreatedAt)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdatePlace updates the place saved by the user
+func (pr *SavedPlaceRepo) UpdatePlace(ctx context.Context, place *entity.SavedPlace) error {
+	query := `
+		UPDATE saved_places
+		SET label = $1, latitude = $2, longitude = $3, object = $4, entrance = $5, floor = $6, notes = $7
+		WHERE id = $8 AND user_id = $9
+	`
+	result, err := pr.ExecContext(ctx, query, place.Label, place.Latitude, place.Longitude,
+		place.Object, place.Entrance, place.Floor, place.Notes, place.ID, place.UserID)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+	return checkPlaceAffected(result, pr.appLogger)
+}
+
+// DeletePlace deletes the place saved by the user
+func (pr *SavedPlaceRepo) DeletePlace(ctx context.Context, userID, placeID int) error {
+	query := `
+		DELETE FROM saved_places
+		WHERE id = $1 AND user_id = $2
+	`
+	result, err := pr.ExecContext(ctx, query, placeID, userID)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+	return checkPlaceAffected(result, pr.appLogger)
+}
+
+// GetPlaces fetches all places saved by the user ordered by label
+func (pr *SavedPlaceRepo) GetPlaces(ctx context.Context, userID int) ([]*entity.SavedPlace, error) {
+	query := `
+		SELECT id, user_id, label, latitude, longitude, object, entrance, floor, notes, created_at
+		FROM saved_places
+		WHERE user_id = $1
+		ORDER BY label, id
+	`
+	rows, err := pr.QueryContext(ctx, query, userID)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.SavedPlace, 0)
+	for rows.Next() {
+		result := &entity.SavedPlace{}
+		err = rows.Scan(&result.ID, &result.UserID, &result.Label, &result.Latitude, &result.Longitude,
+			&result.Object, &result.Entrance, &result.Floor, &result.Notes, &result.CreatedAt)
+		if err != nil {
+			pr.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetPlaceByID fetches the place saved by the user,
+// places of other users are reported as not found
+func (pr *SavedPlaceRepo) GetPlaceByID(ctx context.Context, userID, placeID int) (*entity.SavedPlace, error) {
+	query := `
+		SELECT id, user_id, label, latitude, longitude, object, entrance, floor, notes, created_at
+		FROM saved_places
+		WHERE id = $1 AND user_id = $2
+	`
+	place := &entity.SavedPlace{}
+	err := pr.QueryRowContext(ctx, query, placeID, userID).Scan(&place.ID, &place.UserID, &place.Label,
+		&place.Latitude, &place.Longitude, &place.Object, &place.Entrance, &place.Floor, &place.Notes, &place.CreatedAt)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("saved place is not found")
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	return place, nil
+}
+
+// CountPlaces counts places saved by the user
+func (pr *SavedPlaceRepo) CountPlaces(ctx context.Context, userID int) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM saved_places
+		WHERE user_id = $1
+	`
+	var cnt int
+	err := pr.QueryRowContext(ctx, query, userID).Scan(&cnt)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// checkPlaceAffected checks that the query changed exactly one user's place
+func checkPlaceAffected(result sql.Result, l *logger.Logger) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		l.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("saved place is not found")
+		l.Error(err)
+		return err
+	}
+	return nil
+}
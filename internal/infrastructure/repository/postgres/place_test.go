@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedPlaceRepo_GetPlaceByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewSavedPlaceRepo(db, logger.New(testLogger))
+
+	columns := []string{"id", "user_id", "label", "latitude", "longitude", "object", "entrance", "floor", "notes", "created_at"}
+	createdAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		userID  int
+		placeID int
+		rows    *sqlmock.Rows
+		want    *entity.SavedPlace
+		error   error
+	}{
+		{
+			name:    "place is found",
+			userID:  1,
+			placeID: 3,
+			rows: sqlmock.NewRows(columns).
+				AddRow(3, 1, "Warehouse", 55.75, 37.61, "Москва, Тверская улица, 1", "2", "1", "call on arrival", createdAt),
+			want: &entity.SavedPlace{
+				ID:        3,
+				UserID:    1,
+				Label:     "Warehouse",
+				Latitude:  55.75,
+				Longitude: 37.61,
+				Object:    "Москва, Тверская улица, 1",
+				Entrance:  "2",
+				Floor:     "1",
+				Notes:     "call on arrival",
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name:    "place of another user",
+			userID:  2,
+			placeID: 3,
+			rows:    sqlmock.NewRows(columns),
+			error:   fmt.Errorf("saved place is not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				SELECT id, user_id, label, latitude, longitude, object, entrance, floor, notes, created_at
+				FROM saved_places
+				WHERE id = $1 AND user_id = $2
+			`)).
+				WithArgs(tt.placeID, tt.userID).
+				WillReturnRows(tt.rows)
+
+			got, err := repo.GetPlaceByID(context.Background(), tt.userID, tt.placeID)
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.Nil(t, deep.Equal(tt.want, got))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// profileUpdates maps fields of the user's profile to queries setting them,
+// new value of the field is the first argument and id of the user is the second one
+var profileUpdates = map[string]string{
+	entity.FieldSurname:     `UPDATE users SET surname = $1 WHERE id = $2`,
+	entity.FieldName:        `UPDATE users SET name = $1 WHERE id = $2`,
+	entity.FieldEmail:       `UPDATE users SET email = $1 WHERE id = $2`,
+	entity.FieldPhoneNumber: `UPDATE users SET phone_number = $1 WHERE id = $2`,
+	entity.FieldCityID:      `UPDATE meta SET city_id = $1 WHERE user_id = $2`,
+	entity.FieldAvatar:      `UPDATE users SET avatar_key = $1 WHERE id = $2`,
+}
+
+// ProfileRepo is a struct that provides
+// all functions to execute SQL queries
+// related to changes of users' profiles and their history
+type ProfileRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewProfileRepo(db *sql.DB, l *logger.Logger) *ProfileRepo {
+	return &ProfileRepo{db, l}
+}
+
+// UpdateProfile sets new values of the user's profile fields and records their changes,
+// fields whose values are not changed are skipped. Recorded changes are returned
+func (pr *ProfileRepo) UpdateProfile(ctx context.Context, userID, changedBy int, values map[string]string) ([]*entity.UserChange, error) {
+	tx, err := pr.Begin()
+	if err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockProfile(ctx, tx, userID)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+
+	// Fields are changed in the same order every time
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	changes := make([]*entity.UserChange, 0, len(fields))
+	for _, field := range fields {
+		if values[field] == current[field] {
+			continue
+		}
+		change := &entity.UserChange{
+			UserID:    userID,
+			Field:     field,
+			OldValue:  current[field],
+			NewValue:  values[field],
+			ChangedBy: changedBy,
+		}
+		err = changeProfileField(ctx, tx, change)
+		if err != nil {
+			pr.appLogger.Error(err)
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	if err = tx.Commit(); err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetUserChanges fetches all changes of the user's profile, the latest go first
+func (pr *ProfileRepo) GetUserChanges(ctx context.Context, userID int) ([]*entity.UserChange, error) {
+	query := `
+		SELECT id, user_id, field, old_value, new_value, changed_by, changed_at
+		FROM user_changes
+		WHERE user_id = $1
+		ORDER BY changed_at DESC, id DESC
+	`
+	rows, err := pr.QueryContext(ctx, query, userID)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]*entity.UserChange, 0)
+	for rows.Next() {
+		change := &entity.UserChange{}
+		var changedBy sql.NullInt64
+		err = rows.Scan(&change.ID, &change.UserID, &change.Field, &change.OldValue, &change.NewValue,
+			&changedBy, &change.ChangedAt)
+		if err != nil {
+			pr.appLogger.Error(err)
+			return nil, err
+		}
+		change.ChangedBy = int(changedBy.Int64)
+		changes = append(changes, change)
+	}
+	if err = rows.Err(); err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	return changes, nil
+}
+
+// lockProfile locks the user's profile till the end of the transaction
+// and returns current values of its fields
+func lockProfile(ctx context.Context, tx *sql.Tx, userID int) (map[string]string, error) {
+	query := `
+		SELECT surname, name, email, phone_number, city_id, avatar_key
+		FROM users INNER JOIN meta ON users.id = meta.user_id
+		WHERE users.id = $1
+		FOR UPDATE
+	`
+	var surname, name, email, phone, avatar string
+	var cityID int
+	err := tx.QueryRowContext(ctx, query, userID).Scan(&surname, &name, &email, &phone, &cityID, &avatar)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user is not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		entity.FieldSurname:     surname,
+		entity.FieldName:        name,
+		entity.FieldEmail:       email,
+		entity.FieldPhoneNumber: phone,
+		entity.FieldCityID:      strconv.Itoa(cityID),
+		entity.FieldAvatar:      avatar,
+	}, nil
+}
+
+// changeProfileField sets new value of the profile field and records the change,
+// changed phone number has to be verified again. Id and time of the change are attached to it
+func changeProfileField(ctx context.Context, tx *sql.Tx, change *entity.UserChange) error {
+	query1, ok := profileUpdates[change.Field]
+	if !ok {
+		return fmt.Errorf("field %v can't be changed", change.Field)
+	}
+	_, err := tx.ExecContext(ctx, query1, change.NewValue, change.UserID)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("%v is already taken", change.Field)
+	}
+	if err != nil {
+		return err
+	}
+
+	if change.Field == entity.FieldPhoneNumber {
+		query2 := `UPDATE meta SET phone_verified = false WHERE user_id = $1`
+		_, err = tx.ExecContext(ctx, query2, change.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var changedBy sql.NullInt64
+	if change.ChangedBy != 0 {
+		changedBy = sql.NullInt64{Int64: int64(change.ChangedBy), Valid: true}
+	}
+	query3 := `
+		INSERT INTO user_changes(user_id, field, old_value, new_value, changed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, changed_at
+	`
+	return tx.QueryRowContext(ctx, query3, change.UserID, change.Field, change.OldValue, change.NewValue,
+		changedBy).Scan(&change.ID, &change.ChangedAt)
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+const (
+	lockProfileQuery = `
+		SELECT surname, name, email, phone_number, city_id, avatar_key
+		FROM users INNER JOIN meta ON users.id = meta.user_id
+		WHERE users.id = $1
+		FOR UPDATE
+	`
+	insertUserChangeQuery = `
+		INSERT INTO user_changes(user_id, field, old_value, new_value, changed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, changed_at
+	`
+)
+
+var profileColumns = []string{"surname", "name", "email", "phone_number", "city_id", "avatar_key"}
+
+func TestProfileRepo_UpdateProfile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewProfileRepo(db, logger.New(testLogger))
+
+	now := time.Now()
+	values := map[string]string{
+		entity.FieldSurname:     "Иванов",
+		entity.FieldName:        "Пётр",
+		entity.FieldPhoneNumber: "+79157650031",
+	}
+
+	t.Run("changed fields are recorded", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lockProfileQuery)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(profileColumns).
+				AddRow("Иванов", "Иван", "ivanov@yandex.ru", "+79157650030", 1, ""))
+		// Surname is not changed, so only name and phone number are updated
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET name = $1 WHERE id = $2`)).
+			WithArgs("Пётр", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(insertUserChangeQuery)).
+			WithArgs(1, entity.FieldName, "Иван", "Пётр", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "changed_at"}).AddRow(1, now))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET phone_number = $1 WHERE id = $2`)).
+			WithArgs("+79157650031", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE meta SET phone_verified = false WHERE user_id = $1`)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(insertUserChangeQuery)).
+			WithArgs(1, entity.FieldPhoneNumber, "+79157650030", "+79157650031", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "changed_at"}).AddRow(2, now))
+		mock.ExpectCommit()
+
+		changes, err := repo.UpdateProfile(context.Background(), 1, 1, values)
+		require.NoError(t, err)
+		require.Nil(t, deep.Equal([]*entity.UserChange{
+			{ID: 1, UserID: 1, Field: entity.FieldName, OldValue: "Иван", NewValue: "Пётр", ChangedBy: 1, ChangedAt: now},
+			{ID: 2, UserID: 1, Field: entity.FieldPhoneNumber, OldValue: "+79157650030", NewValue: "+79157650031",
+				ChangedBy: 1, ChangedAt: now},
+		}, changes))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("user is not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lockProfileQuery)).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(profileColumns))
+		mock.ExpectRollback()
+
+		changes, err := repo.UpdateProfile(context.Background(), 2, 2, values)
+		require.Nil(t, deep.Equal(fmt.Errorf("user is not found"), err))
+		require.Nil(t, changes)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProfileRepo_GetUserChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewProfileRepo(db, logger.New(testLogger))
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT id, user_id, field, old_value, new_value, changed_by, changed_at
+		FROM user_changes
+		WHERE user_id = $1
+		ORDER BY changed_at DESC, id DESC
+	`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "field", "old_value", "new_value", "changed_by", "changed_at"}).
+			AddRow(2, 1, entity.FieldCityID, "1", "2", 1, now).
+			AddRow(1, 1, entity.FieldName, "Иван", "Пётр", nil, now.Add(-time.Hour)))
+
+	changes, err := repo.GetUserChanges(context.Background(), 1)
+	require.NoError(t, err)
+	require.Nil(t, deep.Equal([]*entity.UserChange{
+		{ID: 2, UserID: 1, Field: entity.FieldCityID, OldValue: "1", NewValue: "2", ChangedBy: 1, ChangedAt: now},
+		{ID: 1, UserID: 1, Field: entity.FieldName, OldValue: "Иван", NewValue: "Пётр", ChangedAt: now.Add(-time.Hour)},
+	}, changes))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
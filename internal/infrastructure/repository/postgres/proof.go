@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ProofRepo is a struct that provides
+// all functions to execute SQL queries
+// related to proofs of delivery
+type ProofRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewProofRepo(db *sql.DB, l *logger.Logger) *ProofRepo {
+	return &ProofRepo{db, l}
+}
+
+// GetProof fetches proof of the delivery
+func (pr *ProofRepo) GetProof(ctx context.Context, deliveryID int) (*entity.Proof, error) {
+	query := `
+		SELECT delivery_id, pin, pin_attempts, photo_key, signature_key, latitude, longitude, distance, completed_at
+		FROM delivery_proofs
+		WHERE delivery_id = $1
+	`
+	proof := &entity.Proof{}
+	var photoKey, signatureKey sql.NullString
+	var lat, lon, distance sql.NullFloat64
+	var completedAt sql.NullTime
+	err := pr.QueryRowContext(ctx, query, deliveryID).Scan(&proof.DeliveryID, &proof.PIN, &proof.PINAttempts,
+		&photoKey, &signatureKey, &lat, &lon, &distance, &completedAt)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("proof of delivery is not found")
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		pr.appLogger.Error(err)
+		return nil, err
+	}
+
+	proof.PhotoKey = photoKey.String
+	proof.SignatureKey = signatureKey.String
+	proof.Latitude = lat.Float64
+	proof.Longitude = lon.Float64
+	proof.Distance = distance.Float64
+	if completedAt.Valid {
+		proof.CompletedAt = &completedAt.Time
+	}
+	return proof, nil
+}
+
+// SetProofFile attaches the key of stored photo or signature to the proof
+// while the delivery isn't completed
+func (pr *ProofRepo) SetProofFile(ctx context.Context, deliveryID int, kind, key string) error {
+	query := `
+		UPDATE delivery_proofs
+		SET photo_key = $1
+		WHERE delivery_id = $2 AND completed_at IS NULL
+	`
+	if kind == entity.ProofSignature {
+		query = `
+			UPDATE delivery_proofs
+			SET signature_key = $1
+			WHERE delivery_id = $2 AND completed_at IS NULL
+		`
+	}
+
+	result, err := pr.ExecContext(ctx, query, key, deliveryID)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("delivery is already completed")
+		pr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UsePINAttempt counts attempt to enter recipient's PIN and returns the PIN
+// if the limit of attempts isn't reached, so concurrent attempts can't exceed it
+func (pr *ProofRepo) UsePINAttempt(ctx context.Context, deliveryID, maxAttempts int) (string, error) {
+	query := `
+		UPDATE delivery_proofs
+		SET pin_attempts = pin_attempts + 1
+		WHERE delivery_id = $1 AND pin_attempts < $2 AND completed_at IS NULL
+		RETURNING pin
+	`
+	var pin string
+	err := pr.QueryRowContext(ctx, query, deliveryID, maxAttempts).Scan(&pin)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("too many pin attempts")
+		pr.appLogger.Error(err)
+		return "", err
+	}
+	if err != nil {
+		pr.appLogger.Error(err)
+		return "", err
+	}
+	return pin, nil
+}
+
+// CompleteDelivery saves position of the completion to the proof
+// and completes active delivery in one transaction
+func (pr *ProofRepo) CompleteDelivery(ctx context.Context, proof *entity.Proof) error {
+	tx, err := pr.Begin()
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	q1 := `
+		UPDATE delivery_proofs
+		SET latitude = $1, longitude = $2, distance = $3, completed_at = now()
+		WHERE delivery_id = $4 AND completed_at IS NULL
+		RETURNING completed_at
+	`
+	var completedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, q1, proof.Latitude, proof.Longitude, proof.Distance, proof.DeliveryID).Scan(&completedAt)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("delivery is already completed")
+		pr.appLogger.Error(err)
+		return err
+	}
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+
+	q2 := `UPDATE deliveries SET status_id = 3 WHERE id = $1 AND status_id = 2`
+	result, err := tx.ExecContext(ctx, q2, proof.DeliveryID)
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("delivery is not active")
+		pr.appLogger.Error(err)
+		return err
+	}
+
+	err = addOutboxEvent(ctx, tx, entity.AggregateDelivery, proof.DeliveryID, entity.DeliveryCompleted,
+		map[string]float64{"distance": proof.Distance})
+	if err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		pr.appLogger.Error(err)
+		return err
+	}
+	proof.CompletedAt = &completedAt.Time
+	return nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofRepo_CompleteDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewProofRepo(db, logger.New(testLogger))
+
+	completedAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		proof         *entity.Proof
+		proofRows     *sqlmock.Rows
+		deliveryRows  int64
+		wantCommitted bool
+		error         error
+	}{
+		{
+			name:          "delivery is completed",
+			proof:         &entity.Proof{DeliveryID: 1, Latitude: 55.75, Longitude: 37.61, Distance: 42},
+			proofRows:     sqlmock.NewRows([]string{"completed_at"}).AddRow(completedAt),
+			deliveryRows:  1,
+			wantCommitted: true,
+		},
+		{
+			name:      "proof is already completed",
+			proof:     &entity.Proof{DeliveryID: 2, Latitude: 55.75, Longitude: 37.61, Distance: 42},
+			proofRows: sqlmock.NewRows([]string{"completed_at"}),
+			error:     fmt.Errorf("delivery is already completed"),
+		},
+		{
+			name:         "delivery is not active",
+			proof:        &entity.Proof{DeliveryID: 3, Latitude: 55.75, Longitude: 37.61, Distance: 42},
+			proofRows:    sqlmock.NewRows([]string{"completed_at"}).AddRow(completedAt),
+			deliveryRows: 0,
+			error:        fmt.Errorf("delivery is not active"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				UPDATE delivery_proofs
+				SET latitude = $1, longitude = $2, distance = $3, completed_at = now()
+				WHERE delivery_id = $4 AND completed_at IS NULL
+				RETURNING completed_at
+			`)).
+				WithArgs(tt.proof.Latitude, tt.proof.Longitude, tt.proof.Distance, tt.proof.DeliveryID).
+				WillReturnRows(tt.proofRows)
+
+			if tt.error == nil || tt.error.Error() != "delivery is already completed" {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE deliveries SET status_id = 3 WHERE id = $1 AND status_id = 2`)).
+					WithArgs(tt.proof.DeliveryID).
+					WillReturnResult(sqlmock.NewResult(0, tt.deliveryRows))
+			}
+
+			if tt.wantCommitted {
+				expectOutboxEvent(mock, entity.AggregateDelivery, tt.proof.DeliveryID, entity.DeliveryCompleted)
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := repo.CompleteDelivery(context.Background(), tt.proof)
+			require.Nil(t, deep.Equal(tt.error, err))
+			if tt.wantCommitted {
+				require.Equal(t, completedAt, *tt.proof.CompletedAt)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestProofRepo_UsePINAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewProofRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name  string
+		rows  *sqlmock.Rows
+		want  string
+		error error
+	}{
+		{
+			name: "attempt is counted",
+			rows: sqlmock.NewRows([]string{"pin"}).AddRow("0427"),
+			want: "0427",
+		},
+		{
+			name:  "attempts are exhausted",
+			rows:  sqlmock.NewRows([]string{"pin"}),
+			error: fmt.Errorf("too many pin attempts"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(`
+				UPDATE delivery_proofs
+				SET pin_attempts = pin_attempts + 1
+				WHERE delivery_id = $1 AND pin_attempts < $2 AND completed_at IS NULL
+				RETURNING pin
+			`)).
+				WithArgs(10, 5).
+				WillReturnRows(tt.rows)
+
+			got, err := repo.UsePINAttempt(context.Background(), 10, 5)
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
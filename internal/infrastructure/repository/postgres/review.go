@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ReviewRepo is a struct that provides
+// all functions to execute SQL queries
+// related to reviews and ratings
+type ReviewRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewReviewRepo(db *sql.DB, l *logger.Logger) *ReviewRepo {
+	return &ReviewRepo{db, l}
+}
+
+// CreateReview creates a new review record and attaches its id to the review,
+// each participant can rate the delivery only once
+func (rr *ReviewRepo) CreateReview(ctx context.Context, review *entity.Review) error {
+	query := `
+		INSERT INTO reviews(delivery_id, author_id, target_id, author_role, score, comment, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err := rr.QueryRowContext(ctx, query, review.DeliveryID, review.AuthorID, review.TargetID, review.AuthorRole,
+		review.Score, review.Comment, pq.Array(review.Tags)).Scan(&review.ID, &review.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		err = fmt.Errorf("delivery is already rated")
+		rr.appLogger.Error(err)
+		return err
+	}
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetRecentScores fetches latest scores given to the user, the newest go first
+func (rr *ReviewRepo) GetRecentScores(ctx context.Context, targetID, limit int) ([]int, error) {
+	query := `
+		SELECT score
+		FROM reviews
+		WHERE target_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	rows, err := rr.QueryContext(ctx, query, targetID, limit)
+	if err != nil {
+		rr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := make([]int, 0)
+	for rows.Next() {
+		var score int
+		if err = rows.Scan(&score); err != nil {
+			rr.appLogger.Error(err)
+			return nil, err
+		}
+		scores = append(scores, score)
+	}
+
+	if err = rows.Err(); err != nil {
+		rr.appLogger.Error(err)
+		return nil, err
+	}
+	return scores, nil
+}
+
+// SetRating updates aggregate rating of the user
+func (rr *ReviewRepo) SetRating(ctx context.Context, userID int, rating float64) error {
+	query := `UPDATE meta SET rating = $1 WHERE user_id = $2`
+	_, err := rr.ExecContext(ctx, query, rating, userID)
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetReviewsByTargetID fetches page of reviews given to the user, the newest go first
+func (rr *ReviewRepo) GetReviewsByTargetID(ctx context.Context, targetID, page int) ([]*entity.Review, error) {
+	query := `
+		SELECT id, delivery_id, author_id, target_id, author_role, score, comment, tags, is_comment_hidden, created_at
+		FROM reviews
+		WHERE target_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 10 OFFSET $2
+	`
+	return rr.queryReviews(ctx, query, targetID, (page-1)*10)
+}
+
+// GetReviews fetches page of all reviews for moderation, the newest go first
+func (rr *ReviewRepo) GetReviews(ctx context.Context, page int) ([]*entity.Review, error) {
+	query := `
+		SELECT id, delivery_id, author_id, target_id, author_role, score, comment, tags, is_comment_hidden, created_at
+		FROM reviews
+		ORDER BY created_at DESC, id DESC
+		LIMIT 10 OFFSET $1
+	`
+	return rr.queryReviews(ctx, query, (page-1)*10)
+}
+
+// SetCommentHidden hides or shows text of the review
+func (rr *ReviewRepo) SetCommentHidden(ctx context.Context, reviewID int, hidden bool) error {
+	query := `UPDATE reviews SET is_comment_hidden = $1 WHERE id = $2`
+	result, err := rr.ExecContext(ctx, query, hidden, reviewID)
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("review is not found")
+		rr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// queryReviews executes query selecting reviews and scans them
+func (rr *ReviewRepo) queryReviews(ctx context.Context, query string, args ...interface{}) ([]*entity.Review, error) {
+	rows, err := rr.QueryContext(ctx, query, args...)
+	if err != nil {
+		rr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.Review, 0)
+	for rows.Next() {
+		result := &entity.Review{}
+		err = rows.Scan(&result.ID, &result.DeliveryID, &result.AuthorID, &result.TargetID, &result.AuthorRole,
+			&result.Score, &result.Comment, pq.Array(&result.Tags), &result.IsCommentHidden, &result.CreatedAt)
+		if err != nil {
+			rr.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		rr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
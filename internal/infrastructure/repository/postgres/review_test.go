@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewRepo_CreateReview(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewReviewRepo(db, logger.New(testLogger))
+
+	createdAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		review  *entity.Review
+		rows    *sqlmock.Rows
+		dbError error
+		wantID  int
+		error   error
+	}{
+		{
+			name: "review is created",
+			review: &entity.Review{
+				DeliveryID: 1, AuthorID: 2, TargetID: 3, AuthorRole: entity.ReviewByClient,
+				Score: 5, Comment: "fast", Tags: []string{"polite"},
+			},
+			rows:   sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, createdAt),
+			wantID: 7,
+		},
+		{
+			name: "delivery is already rated by the author",
+			review: &entity.Review{
+				DeliveryID: 1, AuthorID: 2, TargetID: 3, AuthorRole: entity.ReviewByClient,
+				Score: 1, Tags: []string{},
+			},
+			dbError: &pq.Error{Code: "23505"},
+			error:   fmt.Errorf("delivery is already rated"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect := mock.ExpectQuery(regexp.QuoteMeta(`
+				INSERT INTO reviews(delivery_id, author_id, target_id, author_role, score, comment, tags)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id, created_at
+			`)).
+				WithArgs(tt.review.DeliveryID, tt.review.AuthorID, tt.review.TargetID, tt.review.AuthorRole,
+					tt.review.Score, tt.review.Comment, pq.Array(tt.review.Tags))
+			if tt.dbError != nil {
+				expect.WillReturnError(tt.dbError)
+			} else {
+				expect.WillReturnRows(tt.rows)
+			}
+
+			err := repo.CreateReview(context.Background(), tt.review)
+			require.Nil(t, deep.Equal(tt.error, err))
+			require.Equal(t, tt.wantID, tt.review.ID)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
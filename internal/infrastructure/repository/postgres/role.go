@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// RoleRepo is a struct that provides
+// all functions to execute SQL queries
+// related to users' roles
+type RoleRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewRoleRepo(db *sql.DB, l *logger.Logger) *RoleRepo {
+	return &RoleRepo{db, l}
+}
+
+// GetUserRoles fetches roles of the user
+func (rr *RoleRepo) GetUserRoles(ctx context.Context, userID int) ([]string, error) {
+	query := `
+		SELECT role
+		FROM user_roles
+		WHERE user_id = $1
+		ORDER BY role
+	`
+	rows, err := rr.QueryContext(ctx, query, userID)
+	if err != nil {
+		rr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]string, 0)
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			rr.appLogger.Error(err)
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		rr.appLogger.Error(err)
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GrantRole grants the role to the user, granting the role
+// the user already has is not an error
+func (rr *RoleRepo) GrantRole(ctx context.Context, userID int, role string, grantedBy int) error {
+	tx, err := rr.Begin()
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO user_roles(user_id, role, granted_by)
+		VALUES($1, $2, $3)
+		ON CONFLICT (user_id, role) DO NOTHING
+	`
+	_, err = tx.ExecContext(ctx, query, userID, role, grantedBy)
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+
+	if err = syncRoleFlags(ctx, tx, userID); err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// RevokeRole revokes the role from the user
+func (rr *RoleRepo) RevokeRole(ctx context.Context, userID int, role string) error {
+	tx, err := rr.Begin()
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`
+	result, err := tx.ExecContext(ctx, query, userID, role)
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	if rows != 1 {
+		err = fmt.Errorf("role is not granted")
+		rr.appLogger.Error(err)
+		return err
+	}
+
+	if err = syncRoleFlags(ctx, tx, userID); err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		rr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// syncRoleFlags keeps is_courier and is_admin flags of the user's metadata
+// in sync with the roles, queries relying on the flags still work
+func syncRoleFlags(ctx context.Context, tx *sql.Tx, userID int) error {
+	query := `
+		UPDATE meta
+		SET is_courier = EXISTS(SELECT 1 FROM user_roles WHERE user_id = $1 AND role = $2),
+			is_admin = EXISTS(SELECT 1 FROM user_roles WHERE user_id = $1 AND role = ANY($3))
+		WHERE user_id = $1
+	`
+	result, err := tx.ExecContext(ctx, query, userID, entity.RoleCourier, pq.Array(entity.StaffRoles))
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return fmt.Errorf("user is not found")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+const syncRoleFlagsQuery = `
+		UPDATE meta
+		SET is_courier = EXISTS(SELECT 1 FROM user_roles WHERE user_id = $1 AND role = $2),
+			is_admin = EXISTS(SELECT 1 FROM user_roles WHERE user_id = $1 AND role = ANY($3))
+		WHERE user_id = $1
+	`
+
+func TestRoleRepo_GrantRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewRoleRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name     string
+		metaRows int64
+		wantErr  error
+	}{
+		{
+			name:     "role is granted",
+			metaRows: 1,
+		},
+		{
+			name:     "user is not found",
+			metaRows: 0,
+			wantErr:  fmt.Errorf("user is not found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`
+				INSERT INTO user_roles(user_id, role, granted_by)
+				VALUES($1, $2, $3)
+				ON CONFLICT (user_id, role) DO NOTHING
+			`)).
+				WithArgs(2, entity.RoleSupport, 1).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta(syncRoleFlagsQuery)).
+				WithArgs(2, entity.RoleCourier, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, tt.metaRows))
+			if tt.wantErr == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := repo.GrantRole(context.Background(), 2, entity.RoleSupport, 1)
+			require.Nil(t, deep.Equal(tt.wantErr, err))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRoleRepo_RevokeRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewRoleRepo(db, logger.New(testLogger))
+
+	// Revoking the role which is granted syncs the flags
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_roles WHERE user_id = $1 AND role = $2`)).
+		WithArgs(2, entity.RoleCourier).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(syncRoleFlagsQuery)).
+		WithArgs(2, entity.RoleCourier, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.RevokeRole(context.Background(), 2, entity.RoleCourier)
+	require.NoError(t, err)
+
+	// Revoking the role which is not granted fails
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM user_roles WHERE user_id = $1 AND role = $2`)).
+		WithArgs(2, entity.RoleFinance).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.RevokeRole(context.Background(), 2, entity.RoleFinance)
+	require.Nil(t, deep.Equal(fmt.Errorf("role is not granted"), err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
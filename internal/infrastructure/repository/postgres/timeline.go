@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// TimelineRepo is a struct that provides
+// all functions to execute SQL queries
+// related to events of deliveries' timelines
+type TimelineRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewTimelineRepo(db *sql.DB, l *logger.Logger) *TimelineRepo {
+	return &TimelineRepo{db, l}
+}
+
+// AddEvent appends the event to the delivery's timeline
+func (tr *TimelineRepo) AddEvent(ctx context.Context, event *entity.DeliveryEvent) error {
+	query := `
+		INSERT INTO delivery_events(delivery_id, type, actor_id, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	payload := event.Payload
+	if payload == nil {
+		payload = []byte("{}")
+	}
+	err := tr.QueryRowContext(ctx, query, event.DeliveryID, event.Type, event.ActorID, string(payload)).
+		Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetEvents fetches the delivery's timeline in chronological order
+func (tr *TimelineRepo) GetEvents(ctx context.Context, deliveryID int) ([]*entity.DeliveryEvent, error) {
+	query := `
+		SELECT id, delivery_id, type, actor_id, payload, created_at
+		FROM delivery_events
+		WHERE delivery_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := tr.QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		tr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.DeliveryEvent, 0)
+	for rows.Next() {
+		result := &entity.DeliveryEvent{}
+		var actorID sql.NullInt64
+		var payload []byte
+		err = rows.Scan(&result.ID, &result.DeliveryID, &result.Type, &actorID, &payload, &result.CreatedAt)
+		if err != nil {
+			tr.appLogger.Error(err)
+			return nil, err
+		}
+		if actorID.Valid {
+			id := int(actorID.Int64)
+			result.ActorID = &id
+		}
+		result.Payload = payload
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		tr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
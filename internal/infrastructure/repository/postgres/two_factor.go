@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// TwoFactorRepo is a struct that provides
+// all functions to execute SQL queries
+// related to users' second factor
+type TwoFactorRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewTwoFactorRepo(db *sql.DB, l *logger.Logger) *TwoFactorRepo {
+	return &TwoFactorRepo{db, l}
+}
+
+// GetTwoFactor fetches second factor of the user, nil is returned if the user has not enrolled
+func (tr *TwoFactorRepo) GetTwoFactor(ctx context.Context, userID int) (*entity.TwoFactor, error) {
+	query := `
+		SELECT user_id, secret, enabled_at, created_at
+		FROM two_factor
+		WHERE user_id = $1
+	`
+	tf := &entity.TwoFactor{}
+	var enabledAt sql.NullTime
+	err := tr.QueryRowContext(ctx, query, userID).Scan(&tf.UserID, &tf.Secret, &enabledAt, &tf.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		tr.appLogger.Error(err)
+		return nil, err
+	}
+
+	if enabledAt.Valid {
+		tf.EnabledAt = &enabledAt.Time
+	}
+	return tf, nil
+}
+
+// SaveTwoFactorSecret saves secret of the enrollment replacing the one
+// which is not confirmed yet, enabled second factor is not replaced
+func (tr *TwoFactorRepo) SaveTwoFactorSecret(ctx context.Context, userID int, secret string) error {
+	query := `
+		INSERT INTO two_factor(user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_step = NULL, created_at = now()
+		WHERE two_factor.enabled_at IS NULL
+	`
+	result, err := tr.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("two-factor authentication is already enabled")
+		tr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// EnableTwoFactor enables second factor of the user with new recovery codes
+func (tr *TwoFactorRepo) EnableTwoFactor(ctx context.Context, userID int, codeHashes []string) error {
+	tx, err := tr.Begin()
+	if err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE two_factor SET enabled_at = now() WHERE user_id = $1 AND enabled_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	if rows != 1 {
+		err = fmt.Errorf("two-factor authentication is already enabled")
+		tr.appLogger.Error(err)
+		return err
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces all recovery codes of the user with new ones
+func (tr *TwoFactorRepo) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	tx, err := tr.Begin()
+	if err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UseTOTPStep remembers time step of the accepted code, false is returned
+// if a code of the same or later step has already been accepted
+func (tr *TwoFactorRepo) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	query := `
+		UPDATE two_factor
+		SET last_step = $1
+		WHERE user_id = $2 AND (last_step IS NULL OR last_step < $1)
+	`
+	result, err := tr.ExecContext(ctx, query, step, userID)
+	if err != nil {
+		tr.appLogger.Error(err)
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tr.appLogger.Error(err)
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// UseRecoveryCode marks the user's recovery code as used, false is returned
+// if there is no such unused code
+func (tr *TwoFactorRepo) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	query := `
+		UPDATE recovery_codes
+		SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	result, err := tr.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		tr.appLogger.Error(err)
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		tr.appLogger.Error(err)
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// DeleteTwoFactor deletes second factor of the user with its recovery codes
+func (tr *TwoFactorRepo) DeleteTwoFactor(ctx context.Context, userID int) error {
+	query := `DELETE FROM two_factor WHERE user_id = $1`
+	_, err := tr.ExecContext(ctx, query, userID)
+	if err != nil {
+		tr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// replaceRecoveryCodes deletes recovery codes of the user and inserts new ones
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int, codeHashes []string) error {
+	query1 := `DELETE FROM recovery_codes WHERE user_id = $1`
+	_, err := tx.ExecContext(ctx, query1, userID)
+	if err != nil {
+		return err
+	}
+
+	query2 := `
+		INSERT INTO recovery_codes(user_id, code_hash)
+		SELECT $1, unnest($2::varchar[])
+	`
+	_, err = tx.ExecContext(ctx, query2, userID, pq.Array(codeHashes))
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorRepo_UseTOTPStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewTwoFactorRepo(db, logger.New(testLogger))
+
+	tests := []struct {
+		name string
+		rows int64
+		want bool
+	}{
+		{
+			name: "code of the new step",
+			rows: 1,
+			want: true,
+		},
+		{
+			name: "code is replayed",
+			rows: 0,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(regexp.QuoteMeta(`
+				UPDATE two_factor
+				SET last_step = $1
+				WHERE user_id = $2 AND (last_step IS NULL OR last_step < $1)
+			`)).
+				WithArgs(int64(56666666), 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.rows))
+
+			got, err := repo.UseTOTPStep(context.Background(), 1, 56666666)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTwoFactorRepo_GetTwoFactor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewTwoFactorRepo(db, logger.New(testLogger))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT user_id, secret, enabled_at, created_at
+		FROM two_factor
+		WHERE user_id = $1
+	`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "created_at"}))
+
+	got, err := repo.GetTwoFactor(context.Background(), 2)
+	require.NoError(t, err)
+	require.Nil(t, got)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
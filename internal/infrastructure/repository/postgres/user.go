@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"github.com/dacore-x/truckly/pkg/logger"
 
 	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
 )
 
 // UserRepo is a struct that provides
@@ -46,10 +49,10 @@ func (ur *UserRepo) CreateUser(ctx context.Context, req *dto.UserSignUpRequestBo
 	}
 
 	query2 := `
-		INSERT INTO meta(user_id, is_courier)
-		VALUES($1, $2)
+		INSERT INTO meta(user_id, is_courier, city_id)
+		VALUES($1, $2, $3)
 	`
-	result, err := tx.ExecContext(ctx, query2, lastInsertID, req.IsCourier)
+	result, err := tx.ExecContext(ctx, query2, lastInsertID, req.IsCourier, req.CityID)
 	if err != nil {
 		ur.appLogger.Error(err)
 		return err
@@ -65,6 +68,28 @@ func (ur *UserRepo) CreateUser(ctx context.Context, req *dto.UserSignUpRequestBo
 		return err
 	}
 
+	// Every user is a client, couriers also get the courier role
+	roles := []string{entity.RoleClient}
+	if req.IsCourier {
+		roles = append(roles, entity.RoleCourier)
+	}
+	query3 := `
+		INSERT INTO user_roles(user_id, role)
+		SELECT $1, unnest($2::varchar[])
+	`
+	_, err = tx.ExecContext(ctx, query3, lastInsertID, pq.Array(roles))
+	if err != nil {
+		ur.appLogger.Error(err)
+		return err
+	}
+
+	err = addOutboxEvent(ctx, tx, entity.AggregateUser, lastInsertID, entity.UserCreated,
+		map[string]bool{"is_courier": req.IsCourier})
+	if err != nil {
+		ur.appLogger.Error(err)
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		ur.appLogger.Error(err)
 		return err
@@ -72,60 +97,70 @@ func (ur *UserRepo) CreateUser(ctx context.Context, req *dto.UserSignUpRequestBo
 	return nil
 }
 
+const banUserQuery = `
+	UPDATE meta
+	SET is_banned=true
+	WHERE user_id=$1
+`
+
 // BanUser updates user's is_banned field and sets its value to true
 func (ur *UserRepo) BanUser(ctx context.Context, id int) error {
+	return ur.setBan(ctx, banUserQuery, id, entity.UserBanned)
+}
+
+// UnbanUser updates user's is_banned field and sets its value to false
+func (ur *UserRepo) UnbanUser(ctx context.Context, id int) error {
 	query := `
 		UPDATE meta
-		SET is_banned=true
+		SET is_banned=false
 		WHERE user_id=$1
 	`
-	result, err := ur.ExecContext(ctx, query, id)
+	return ur.setBan(ctx, query, id, entity.UserUnbanned)
+}
+
+// setBan executes query changing user's ban status and writes the event of the change to the outbox
+func (ur *UserRepo) setBan(ctx context.Context, query string, id int, event string) error {
+	tx, err := ur.Begin()
 	if err != nil {
 		ur.appLogger.Error(err)
 		return err
 	}
-	rows, err := result.RowsAffected()
-	if err != nil {
+	defer tx.Rollback()
+
+	if err = setBanTx(ctx, tx, query, id, event); err != nil {
 		ur.appLogger.Error(err)
 		return err
 	}
-	if rows != 1 {
-		err := fmt.Errorf("expected to affect 1 row, affected %d", rows)
+
+	if err = tx.Commit(); err != nil {
 		ur.appLogger.Error(err)
 		return err
 	}
 	return nil
 }
 
-// UnbanUser updates user's is_banned field and sets its value to false
-func (ur *UserRepo) UnbanUser(ctx context.Context, id int) error {
-	query := `
-		UPDATE meta
-		SET is_banned=false
-		WHERE user_id=$1
-	`
-	result, err := ur.ExecContext(ctx, query, id)
+// setBanTx changes user's ban status within the given transaction
+// so the change can be committed together with the caller's updates
+func setBanTx(ctx context.Context, tx *sql.Tx, query string, id int, event string) error {
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
-		ur.appLogger.Error(err)
 		return err
 	}
 	rows, err := result.RowsAffected()
 	if err != nil {
-		ur.appLogger.Error(err)
 		return err
 	}
 	if rows != 1 {
-		err := fmt.Errorf("expected to affect 1 row, affected %d", rows)
-		ur.appLogger.Error(err)
-		return err
+		return fmt.Errorf("expected to affect 1 row, affected %d", rows)
 	}
-	return nil
+	return addOutboxEvent(ctx, tx, entity.AggregateUser, id, event, nil)
 }
 
 // GetUserByID fetches user's account data from the database and returns it
 func (ur *UserRepo) GetUserByID(ctx context.Context, id int) (*dto.UserMeResponse, error) {
 	query := `
-		SELECT users.id, surname, name, email, phone_number, created_at, is_admin, is_courier, is_banned
+		SELECT users.id, surname, name, email, phone_number, avatar_key, created_at, is_admin, is_courier, is_banned,
+			city_id, email_verified, phone_verified
 		FROM users INNER JOIN meta ON users.id = meta.user_id
 		WHERE users.id=$1
 	`
@@ -141,15 +176,20 @@ func (ur *UserRepo) GetUserByID(ctx context.Context, id int) (*dto.UserMeRespons
 		&resp.Name,
 		&resp.Email,
 		&resp.PhoneNumber,
+		&resp.AvatarKey,
 		&resp.CreatedAt,
 		&resp.Meta.IsAdmin,
 		&resp.Meta.IsCourier,
 		&resp.Meta.IsBanned,
+		&resp.Meta.CityID,
+		&resp.Meta.IsEmailVerified,
+		&resp.Meta.IsPhoneVerified,
 	)
 	if err != nil {
 		ur.appLogger.Error(err)
 		return nil, err
 	}
+	resp.HasAvatar = resp.AvatarKey != ""
 	return resp, nil
 }
 
@@ -192,14 +232,15 @@ func (ur *UserRepo) GetUserPrivateByEmail(ctx context.Context, email string) (*d
 // GetUserMeta fetches user's metadata by id from the database and returns it
 func (ur *UserRepo) GetUserMeta(ctx context.Context, id int) (*dto.UserMetaResponse, error) {
 	query := `
-		SELECT user_id, is_admin, is_courier, is_banned, rating
+		SELECT user_id, is_admin, is_courier, is_banned, rating, city_id, email_verified, phone_verified
 		FROM meta
 		WHERE user_id=$1
 	`
 	row := ur.QueryRowContext(ctx, query, id)
 
 	resp := &dto.UserMetaResponse{}
-	err := row.Scan(&resp.UserID, &resp.IsAdmin, &resp.IsCourier, &resp.IsBanned, &resp.Rating)
+	err := row.Scan(&resp.UserID, &resp.IsAdmin, &resp.IsCourier, &resp.IsBanned, &resp.Rating, &resp.CityID,
+		&resp.IsEmailVerified, &resp.IsPhoneVerified)
 	if err != nil {
 		ur.appLogger.Error(err)
 		return nil, err
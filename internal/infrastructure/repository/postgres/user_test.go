@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
 )
 
 func TestPostgres_CreateUserTxNoRollback(t *testing.T) {
@@ -134,12 +135,23 @@ func TestPostgres_CreateUserTxNoRollback(t *testing.T) {
 			// Expect query to create a new user metadata record
 			// and either return error or not, match it with regexp
 			mock.ExpectExec(regexp.QuoteMeta(`
-					INSERT INTO meta(user_id, is_courier)
-					VALUES($1, $2) 
+					INSERT INTO meta(user_id, is_courier, city_id)
+					VALUES($1, $2, $3) 
 				`)).
-				WithArgs(tc.args.id, tc.args.body.IsCourier).
+				WithArgs(tc.args.id, tc.args.body.IsCourier, tc.args.body.CityID).
 				WillReturnResult(tc.args.result)
 
+			// Expect query to grant the signup roles
+			mock.ExpectExec(regexp.QuoteMeta(`
+					INSERT INTO user_roles(user_id, role)
+					SELECT $1, unnest($2::varchar[])
+				`)).
+				WithArgs(tc.args.id, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			// Expect event of the new user written to the outbox
+			expectOutboxEvent(mock, entity.AggregateUser, tc.args.id, entity.UserCreated)
+
 			// Expect transaction commit
 			mock.ExpectCommit()
 
@@ -267,6 +279,7 @@ func TestPostgres_BanUser(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Expect query to update users's ban status and
 			// either return error or not, match it with regexp
+			mock.ExpectBegin()
 			mock.ExpectExec(regexp.QuoteMeta(`
 				UPDATE meta
 				SET is_banned=true
@@ -276,9 +289,18 @@ func TestPostgres_BanUser(t *testing.T) {
 				WillReturnResult(tc.args.result).
 				WillReturnError(tc.wantErr)
 
+			// Expect event of the change written to the outbox
+			if tc.wantErr == nil {
+				expectOutboxEvent(mock, entity.AggregateUser, tc.args.id, entity.UserBanned)
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
 			// Run the ban function
 			err := repo.BanUser(context.Background(), tc.args.id)
 			require.Nil(t, deep.Equal(tc.wantErr, err))
+			require.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -327,6 +349,7 @@ func TestPostgres_UnbanUser(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Expect query to update users's ban status and
 			// either return error or not, match it with regexp
+			mock.ExpectBegin()
 			mock.ExpectExec(regexp.QuoteMeta(`
 				UPDATE meta
 				SET is_banned=false
@@ -336,9 +359,18 @@ func TestPostgres_UnbanUser(t *testing.T) {
 				WillReturnResult(tc.args.result).
 				WillReturnError(tc.wantErr)
 
+			// Expect event of the change written to the outbox
+			if tc.wantErr == nil {
+				expectOutboxEvent(mock, entity.AggregateUser, tc.args.id, entity.UserUnbanned)
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
 			// Run the ban function
 			err := repo.UnbanUser(context.Background(), tc.args.id)
 			require.Nil(t, deep.Equal(tc.wantErr, err))
+			require.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -373,8 +405,8 @@ func TestPostgres_GetUserByID(t *testing.T) {
 			name: "user is found",
 			args: args{
 				id: 1,
-				rows: sqlmock.NewRows([]string{"id", "surname", "name", "email", "phone_number", "created_at", "is_admin", "is_courier", "is_banned"}).
-					AddRow(1, "Иванов", "Иван", "ivanov@yandex.ru", "89157650030", now, false, false, false),
+				rows: sqlmock.NewRows([]string{"id", "surname", "name", "email", "phone_number", "avatar_key", "created_at", "is_admin", "is_courier", "is_banned", "city_id", "email_verified", "phone_verified"}).
+					AddRow(1, "Иванов", "Иван", "ivanov@yandex.ru", "89157650030", "avatars/1/a1b2.jpg", now, false, false, false, 1, false, false),
 			},
 			want: &dto.UserMeResponse{
 				ID:          1,
@@ -382,11 +414,14 @@ func TestPostgres_GetUserByID(t *testing.T) {
 				Name:        "Иван",
 				Email:       "ivanov@yandex.ru",
 				PhoneNumber: "89157650030",
+				AvatarKey:   "avatars/1/a1b2.jpg",
+				HasAvatar:   true,
 				CreatedAt:   now,
 				Meta: &dto.RoleMeta{
 					IsAdmin:   false,
 					IsCourier: false,
 					IsBanned:  false,
+					CityID:    1,
 				},
 			},
 		},
@@ -394,8 +429,8 @@ func TestPostgres_GetUserByID(t *testing.T) {
 			name: "user is not found",
 			args: args{
 				id: 2,
-				rows: sqlmock.NewRows([]string{"id", "surname", "name", "email", "phone_number", "created_at", "is_admin", "is_courier", "is_banned"}).
-					AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil),
+				rows: sqlmock.NewRows([]string{"id", "surname", "name", "email", "phone_number", "avatar_key", "created_at", "is_admin", "is_courier", "is_banned", "city_id", "email_verified", "phone_verified"}).
+					AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
 			},
 			wantErr: sql.ErrNoRows,
 		},
@@ -407,7 +442,8 @@ func TestPostgres_GetUserByID(t *testing.T) {
 			// Expect query to fetch user's account data and
 			// either return error or not, match it with regexp
 			mock.ExpectQuery(regexp.QuoteMeta(`
-				SELECT users.id, surname, name, email, phone_number, created_at, is_admin, is_courier, is_banned
+				SELECT users.id, surname, name, email, phone_number, avatar_key, created_at, is_admin, is_courier, is_banned,
+					city_id, email_verified, phone_verified
 				FROM users INNER JOIN meta ON users.id = meta.user_id
 				WHERE users.id=$1
 			`)).
@@ -588,68 +624,76 @@ func TestUser_GetUserMeta(t *testing.T) {
 			name: "default user",
 			args: args{
 				id: 1,
-				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating"}).
-					AddRow(1, false, false, false, 4.00),
+				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating", "city_id", "email_verified", "phone_verified"}).
+					AddRow(1, false, false, false, 4.00, 1, true, false),
 			},
 			want: &dto.UserMetaResponse{
-				UserID:    1,
-				IsAdmin:   false,
-				IsCourier: false,
-				IsBanned:  false,
-				Rating:    4.00,
+				UserID:          1,
+				IsAdmin:         false,
+				IsCourier:       false,
+				IsBanned:        false,
+				Rating:          4.00,
+				CityID:          1,
+				IsEmailVerified: true,
 			},
 		},
 		{
 			name: "banned user",
 			args: args{
 				id: 2,
-				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating"}).
-					AddRow(2, false, false, true, 3.00),
+				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating", "city_id", "email_verified", "phone_verified"}).
+					AddRow(2, false, false, true, 3.00, 1, true, false),
 			},
 			want: &dto.UserMetaResponse{
-				UserID:    2,
-				IsAdmin:   false,
-				IsCourier: false,
-				IsBanned:  true,
-				Rating:    3.00,
+				UserID:          2,
+				IsAdmin:         false,
+				IsCourier:       false,
+				IsBanned:        true,
+				Rating:          3.00,
+				CityID:          1,
+				IsEmailVerified: true,
 			},
 		},
 		{
 			name: "admin user",
 			args: args{
 				id: 3,
-				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating"}).
-					AddRow(3, true, false, false, 2.00),
+				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating", "city_id", "email_verified", "phone_verified"}).
+					AddRow(3, true, false, false, 2.00, 1, true, false),
 			},
 			want: &dto.UserMetaResponse{
-				UserID:    3,
-				IsAdmin:   true,
-				IsCourier: false,
-				IsBanned:  false,
-				Rating:    2.00,
+				UserID:          3,
+				IsAdmin:         true,
+				IsCourier:       false,
+				IsBanned:        false,
+				Rating:          2.00,
+				CityID:          1,
+				IsEmailVerified: true,
 			},
 		},
 		{
 			name: "courier user",
 			args: args{
 				id: 4,
-				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating"}).
-					AddRow(4, false, true, false, 5.00),
+				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating", "city_id", "email_verified", "phone_verified"}).
+					AddRow(4, false, true, false, 5.00, 2, true, false),
 			},
 			want: &dto.UserMetaResponse{
-				UserID:    4,
-				IsAdmin:   false,
-				IsCourier: true,
-				IsBanned:  false,
-				Rating:    5.00,
+				UserID:          4,
+				IsAdmin:         false,
+				IsCourier:       true,
+				IsBanned:        false,
+				Rating:          5.00,
+				CityID:          2,
+				IsEmailVerified: true,
 			},
 		},
 		{
 			name: "user is not found",
 			args: args{
 				id: 5,
-				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating"}).
-					AddRow(nil, nil, nil, nil, nil),
+				rows: sqlmock.NewRows([]string{"user_id", "is_admin", "is_courier", "is_banned", "rating", "city_id", "email_verified", "phone_verified"}).
+					AddRow(nil, nil, nil, nil, nil, nil, nil, nil),
 			},
 			wantErr: sql.ErrNoRows,
 		},
@@ -661,7 +705,7 @@ func TestUser_GetUserMeta(t *testing.T) {
 			// Expect query to fetch private user's data by email and
 			// either return error or not, match it with regexp
 			mock.ExpectQuery(regexp.QuoteMeta(`
-				SELECT user_id, is_admin, is_courier, is_banned, rating
+				SELECT user_id, is_admin, is_courier, is_banned, rating, city_id, email_verified, phone_verified
 				FROM meta
 				WHERE user_id=$1
 			`)).
@@ -0,0 +1,380 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// WebhookRepo is a struct that provides
+// all functions to execute SQL queries
+// related to webhooks and their deliveries
+type WebhookRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewWebhookRepo(db *sql.DB, l *logger.Logger) *WebhookRepo {
+	return &WebhookRepo{db, l}
+}
+
+// CreateWebhook creates a new webhook record and attaches its id to the webhook
+func (wr *WebhookRepo) CreateWebhook(ctx context.Context, webhook *entity.Webhook) error {
+	query := `
+		INSERT INTO webhooks(user_id, url, secret, events)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, is_active, created_at
+	`
+	err := wr.QueryRowContext(ctx, query, webhook.UserID, webhook.URL, webhook.Secret,
+		pq.Array(webhook.Events)).Scan(&webhook.ID, &webhook.IsActive, &webhook.CreatedAt)
+	if err != nil {
+		wr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateWebhook updates endpoint, events and state of the user's webhook
+func (wr *WebhookRepo) UpdateWebhook(ctx context.Context, webhook *entity.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $1, events = $2, is_active = $3, failure_count = $4, disabled_at = $5
+		WHERE id = $6 AND user_id = $7
+	`
+	result, err := wr.ExecContext(ctx, query, webhook.URL, pq.Array(webhook.Events), webhook.IsActive,
+		webhook.FailureCount, webhook.DisabledAt, webhook.ID, webhook.UserID)
+	if err != nil {
+		wr.appLogger.Error(err)
+		return err
+	}
+	return wr.checkWebhookAffected(result)
+}
+
+// DeleteWebhook deletes the user's webhook with its delivery log
+func (wr *WebhookRepo) DeleteWebhook(ctx context.Context, userID, webhookID int) error {
+	query := `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+	result, err := wr.ExecContext(ctx, query, webhookID, userID)
+	if err != nil {
+		wr.appLogger.Error(err)
+		return err
+	}
+	return wr.checkWebhookAffected(result)
+}
+
+// GetWebhooks fetches all webhooks of the user
+func (wr *WebhookRepo) GetWebhooks(ctx context.Context, userID int) ([]*entity.Webhook, error) {
+	query := `
+		SELECT id, user_id, url, secret, events, is_active, failure_count, disabled_at, created_at
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY id
+	`
+	return wr.queryWebhooks(ctx, query, userID)
+}
+
+// GetWebhookByID fetches the user's webhook by id
+func (wr *WebhookRepo) GetWebhookByID(ctx context.Context, userID, webhookID int) (*entity.Webhook, error) {
+	query := `
+		SELECT id, user_id, url, secret, events, is_active, failure_count, disabled_at, created_at
+		FROM webhooks
+		WHERE id = $1 AND user_id = $2
+	`
+	webhooks, err := wr.queryWebhooks(ctx, query, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(webhooks) == 0 {
+		err = fmt.Errorf("webhook is not found")
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	return webhooks[0], nil
+}
+
+// GetSubscribedWebhooks fetches active webhooks of the user subscribed to the event
+func (wr *WebhookRepo) GetSubscribedWebhooks(ctx context.Context, userID int, event string) ([]*entity.Webhook, error) {
+	query := `
+		SELECT id, user_id, url, secret, events, is_active, failure_count, disabled_at, created_at
+		FROM webhooks
+		WHERE user_id = $1 AND is_active AND $2 = ANY(events)
+	`
+	return wr.queryWebhooks(ctx, query, userID, event)
+}
+
+// GetWebhookEventData fetches state of the delivery sent to webhooks
+func (wr *WebhookRepo) GetWebhookEventData(ctx context.Context, deliveryID int) (*entity.WebhookEventData, error) {
+	query := `
+		SELECT deliveries.id, client_id, courier_id, status_id, price, geo.from_object, geo.to_object, deliveries.created_at
+		FROM deliveries
+		INNER JOIN geo ON deliveries.geo_id = geo.id
+		WHERE deliveries.id = $1
+	`
+	data := &entity.WebhookEventData{}
+	var courierID sql.NullInt64
+	err := wr.QueryRowContext(ctx, query, deliveryID).Scan(&data.DeliveryID, &data.ClientID, &courierID,
+		&data.StatusID, &data.Price, &data.FromObject, &data.ToObject, &data.CreatedAt)
+	if err == sql.ErrNoRows {
+		err = fmt.Errorf("delivery is not found")
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	if err != nil {
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+
+	if courierID.Valid {
+		id := int(courierID.Int64)
+		data.CourierID = &id
+	}
+	return data, nil
+}
+
+// CreateWebhookDelivery queues the event for sending to the webhook
+func (wr *WebhookRepo) CreateWebhookDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries(webhook_id, event, payload)
+		VALUES ($1, $2, $3)
+		RETURNING id, status, next_attempt_at, created_at
+	`
+	err := wr.QueryRowContext(ctx, query, delivery.WebhookID, delivery.Event, []byte(delivery.Payload)).Scan(
+		&delivery.ID, &delivery.Status, &delivery.NextAttemptAt, &delivery.CreatedAt)
+	if err != nil {
+		wr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetWebhookDeliveries fetches page of the webhook's delivery log, the newest go first
+func (wr *WebhookRepo) GetWebhookDeliveries(ctx context.Context, webhookID, page int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, event, payload, status, attempts, response_status, last_error,
+		       next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT 10 OFFSET $2
+	`
+	return wr.queryWebhookDeliveries(ctx, query, webhookID, (page-1)*10)
+}
+
+// GetWebhookDeliveryByID fetches the webhook's delivery by id
+func (wr *WebhookRepo) GetWebhookDeliveryByID(ctx context.Context, webhookID, deliveryID int) (*entity.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, event, payload, status, attempts, response_status, last_error,
+		       next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE id = $1 AND webhook_id = $2
+	`
+	deliveries, err := wr.queryWebhookDeliveries(ctx, query, deliveryID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		err = fmt.Errorf("webhook delivery is not found")
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	return deliveries[0], nil
+}
+
+// ClaimWebhookDeliveries takes pending deliveries of active webhooks due for sending and counts
+// the attempt, they are postponed by the lease so that other workers skip them while being sent
+func (wr *WebhookRepo) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, next_attempt_at = now() + $2 * interval '1 second'
+		FROM webhooks
+		WHERE webhooks.id = webhook_deliveries.webhook_id AND webhook_deliveries.id IN (
+		    SELECT webhook_deliveries.id
+		    FROM webhook_deliveries
+		    INNER JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+		    WHERE status = 'pending' AND next_attempt_at <= now() AND webhooks.is_active
+		    ORDER BY next_attempt_at
+		    LIMIT $1
+		    FOR UPDATE OF webhook_deliveries SKIP LOCKED
+		)
+		RETURNING webhook_deliveries.id, webhook_id, event, payload, status, attempts, response_status,
+		          last_error, next_attempt_at, webhook_deliveries.created_at, delivered_at, webhooks.url, webhooks.secret
+	`
+	rows, err := wr.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.WebhookDelivery, 0)
+	for rows.Next() {
+		result := &entity.WebhookDelivery{}
+		var responseStatus sql.NullInt64
+		var deliveredAt sql.NullTime
+		err = rows.Scan(&result.ID, &result.WebhookID, &result.Event, &result.Payload, &result.Status,
+			&result.Attempts, &responseStatus, &result.LastError, &result.NextAttemptAt, &result.CreatedAt,
+			&deliveredAt, &result.URL, &result.Secret)
+		if err != nil {
+			wr.appLogger.Error(err)
+			return nil, err
+		}
+		setWebhookDeliveryNulls(result, responseStatus, deliveredAt)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// RecordWebhookAttempt saves result of the attempt to send the delivery and counts
+// consecutive failures of its webhook in one transaction, the webhook is disabled
+// once failures reach the limit, it reports whether the webhook has been disabled now
+// (now() is the same within transaction so only just set disabled_at equals it)
+func (wr *WebhookRepo) RecordWebhookAttempt(ctx context.Context, delivery *entity.WebhookDelivery, disableAfter int) (bool, error) {
+	tx, err := wr.Begin()
+	if err != nil {
+		wr.appLogger.Error(err)
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q1 := `
+		UPDATE webhook_deliveries
+		SET status = $1, response_status = $2, last_error = $3, next_attempt_at = $4, delivered_at = $5
+		WHERE id = $6
+	`
+	_, err = tx.ExecContext(ctx, q1, delivery.Status, delivery.ResponseStatus, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt, delivery.ID)
+	if err != nil {
+		wr.appLogger.Error(err)
+		return false, err
+	}
+
+	disabled := false
+	if delivery.Status == entity.WebhookSucceeded {
+		q2 := `UPDATE webhooks SET failure_count = 0 WHERE id = $1`
+		_, err = tx.ExecContext(ctx, q2, delivery.WebhookID)
+	} else {
+		q2 := `
+			UPDATE webhooks
+			SET failure_count = failure_count + 1,
+			    is_active = is_active AND failure_count + 1 < $2,
+			    disabled_at = CASE WHEN is_active AND failure_count + 1 >= $2 THEN now() ELSE disabled_at END
+			WHERE id = $1
+			RETURNING COALESCE(disabled_at = now(), FALSE)
+		`
+		err = tx.QueryRowContext(ctx, q2, delivery.WebhookID, disableAfter).Scan(&disabled)
+	}
+	if err != nil {
+		wr.appLogger.Error(err)
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		wr.appLogger.Error(err)
+		return false, err
+	}
+	return disabled, nil
+}
+
+// checkWebhookAffected checks that the user's webhook has been found
+func (wr *WebhookRepo) checkWebhookAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		wr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("webhook is not found")
+		wr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// queryWebhooks executes query selecting webhooks and scans them
+func (wr *WebhookRepo) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]*entity.Webhook, error) {
+	rows, err := wr.QueryContext(ctx, query, args...)
+	if err != nil {
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.Webhook, 0)
+	for rows.Next() {
+		result := &entity.Webhook{}
+		var disabledAt sql.NullTime
+		err = rows.Scan(&result.ID, &result.UserID, &result.URL, &result.Secret, pq.Array(&result.Events),
+			&result.IsActive, &result.FailureCount, &disabledAt, &result.CreatedAt)
+		if err != nil {
+			wr.appLogger.Error(err)
+			return nil, err
+		}
+		if disabledAt.Valid {
+			result.DisabledAt = &disabledAt.Time
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// queryWebhookDeliveries executes query selecting webhook deliveries and scans them
+func (wr *WebhookRepo) queryWebhookDeliveries(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookDelivery, error) {
+	rows, err := wr.QueryContext(ctx, query, args...)
+	if err != nil {
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.WebhookDelivery, 0)
+	for rows.Next() {
+		result := &entity.WebhookDelivery{}
+		var responseStatus sql.NullInt64
+		var deliveredAt sql.NullTime
+		err = rows.Scan(&result.ID, &result.WebhookID, &result.Event, &result.Payload, &result.Status,
+			&result.Attempts, &responseStatus, &result.LastError, &result.NextAttemptAt, &result.CreatedAt,
+			&deliveredAt)
+		if err != nil {
+			wr.appLogger.Error(err)
+			return nil, err
+		}
+		setWebhookDeliveryNulls(result, responseStatus, deliveredAt)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		wr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// setWebhookDeliveryNulls sets nullable fields of the webhook delivery
+func setWebhookDeliveryNulls(delivery *entity.WebhookDelivery, responseStatus sql.NullInt64, deliveredAt sql.NullTime) {
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		delivery.ResponseStatus = &status
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+}
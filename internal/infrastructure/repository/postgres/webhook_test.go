@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepo_RecordWebhookAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewWebhookRepo(db, logger.New(testLogger))
+
+	deliveredAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	okStatus := 200
+	failStatus := 500
+
+	tests := []struct {
+		name         string
+		delivery     *entity.WebhookDelivery
+		disabledRows *sqlmock.Rows
+		wantDisabled bool
+	}{
+		{
+			name: "delivery succeeded",
+			delivery: &entity.WebhookDelivery{
+				ID: 1, WebhookID: 3, Status: entity.WebhookSucceeded, ResponseStatus: &okStatus,
+				NextAttemptAt: deliveredAt, DeliveredAt: &deliveredAt,
+			},
+		},
+		{
+			name: "delivery failed",
+			delivery: &entity.WebhookDelivery{
+				ID: 2, WebhookID: 3, Status: entity.WebhookPending, ResponseStatus: &failStatus,
+				LastError: "endpoint responded with status 500", NextAttemptAt: deliveredAt.Add(time.Minute),
+			},
+			disabledRows: sqlmock.NewRows([]string{"disabled"}).AddRow(false),
+		},
+		{
+			name: "webhook is disabled after failure",
+			delivery: &entity.WebhookDelivery{
+				ID: 4, WebhookID: 3, Status: entity.WebhookFailed, LastError: "connection refused",
+				NextAttemptAt: deliveredAt,
+			},
+			disabledRows: sqlmock.NewRows([]string{"disabled"}).AddRow(true),
+			wantDisabled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.delivery
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`
+				UPDATE webhook_deliveries
+				SET status = $1, response_status = $2, last_error = $3, next_attempt_at = $4, delivered_at = $5
+				WHERE id = $6
+			`)).
+				WithArgs(d.Status, d.ResponseStatus, d.LastError, d.NextAttemptAt, d.DeliveredAt, d.ID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			if tt.disabledRows == nil {
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE webhooks SET failure_count = 0 WHERE id = $1`)).
+					WithArgs(d.WebhookID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			} else {
+				mock.ExpectQuery(regexp.QuoteMeta(`
+					UPDATE webhooks
+					SET failure_count = failure_count + 1,
+					    is_active = is_active AND failure_count + 1 < $2,
+					    disabled_at = CASE WHEN is_active AND failure_count + 1 >= $2 THEN now() ELSE disabled_at END
+					WHERE id = $1
+					RETURNING COALESCE(disabled_at = now(), FALSE)
+				`)).
+					WithArgs(d.WebhookID, 50).
+					WillReturnRows(tt.disabledRows)
+			}
+			mock.ExpectCommit()
+
+			disabled, err := repo.RecordWebhookAttempt(context.Background(), d, 50)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDisabled, disabled)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
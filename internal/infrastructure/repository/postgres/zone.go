@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ZoneRepo is a struct that provides
+// all functions to execute SQL queries
+// related to service zones
+type ZoneRepo struct {
+	*sql.DB
+	appLogger *logger.Logger
+}
+
+func NewZoneRepo(db *sql.DB, l *logger.Logger) *ZoneRepo {
+	return &ZoneRepo{db, l}
+}
+
+// CreateZone creates a new service zone record and attaches its id to the zone
+func (zr *ZoneRepo) CreateZone(ctx context.Context, zone *entity.ServiceZone) error {
+	query := `
+		INSERT INTO service_zones(name, geometry, allowed_types, tariff_multiplier, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err := zr.QueryRowContext(ctx, query, zone.Name, string(zone.Geometry), pq.Array(zone.AllowedTypes),
+		zone.TariffMultiplier, zone.IsActive).Scan(&zone.ID, &zone.CreatedAt)
+	if err != nil {
+		zr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// ImportZones creates all service zones in one transaction
+// so that an invalid zone doesn't leave the import half-done
+func (zr *ZoneRepo) ImportZones(ctx context.Context, zones []*entity.ServiceZone) error {
+	tx, err := zr.Begin()
+	if err != nil {
+		zr.appLogger.Error(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO service_zones(name, geometry, allowed_types, tariff_multiplier, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	for _, zone := range zones {
+		err = tx.QueryRowContext(ctx, query, zone.Name, string(zone.Geometry), pq.Array(zone.AllowedTypes),
+			zone.TariffMultiplier, zone.IsActive).Scan(&zone.ID, &zone.CreatedAt)
+		if err != nil {
+			zr.appLogger.Error(err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		zr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// UpdateZone updates geometry and settings of the service zone
+func (zr *ZoneRepo) UpdateZone(ctx context.Context, zone *entity.ServiceZone) error {
+	query := `
+		UPDATE service_zones
+		SET name = $1, geometry = $2, allowed_types = $3, tariff_multiplier = $4, is_active = $5
+		WHERE id = $6
+	`
+	result, err := zr.ExecContext(ctx, query, zone.Name, string(zone.Geometry), pq.Array(zone.AllowedTypes),
+		zone.TariffMultiplier, zone.IsActive, zone.ID)
+	if err != nil {
+		zr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		zr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("zone is not found")
+		zr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteZone deletes the service zone, deliveries tagged with it keep no zone
+func (zr *ZoneRepo) DeleteZone(ctx context.Context, zoneID int) error {
+	query := `DELETE FROM service_zones WHERE id = $1`
+	result, err := zr.ExecContext(ctx, query, zoneID)
+	if err != nil {
+		zr.appLogger.Error(err)
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		zr.appLogger.Error(err)
+		return err
+	}
+
+	if rows != 1 {
+		err = fmt.Errorf("zone is not found")
+		zr.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetZones fetches all service zones ordered by id
+func (zr *ZoneRepo) GetZones(ctx context.Context) ([]*entity.ServiceZone, error) {
+	query := `
+		SELECT id, name, geometry, allowed_types, tariff_multiplier, is_active, created_at
+		FROM service_zones
+		ORDER BY id
+	`
+	rows, err := zr.QueryContext(ctx, query)
+	if err != nil {
+		zr.appLogger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.ServiceZone, 0)
+	for rows.Next() {
+		result := &entity.ServiceZone{}
+		err = rows.Scan(&result.ID, &result.Name, &result.Geometry, pq.Array(&result.AllowedTypes),
+			&result.TariffMultiplier, &result.IsActive, &result.CreatedAt)
+		if err != nil {
+			zr.appLogger.Error(err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		zr.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
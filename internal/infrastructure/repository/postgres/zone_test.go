@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneRepo_GetZones(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	testLogger := logrus.New()
+	repo := NewZoneRepo(db, logger.New(testLogger))
+
+	geometry := `{"type":"Polygon","coordinates":[[[37.5,55.7],[37.7,55.7],[37.7,55.8],[37.5,55.7]]]}`
+	createdAt := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`
+		SELECT id, name, geometry, allowed_types, tariff_multiplier, is_active, created_at
+		FROM service_zones
+		ORDER BY id
+	`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "geometry", "allowed_types", "tariff_multiplier", "is_active", "created_at"}).
+			AddRow(1, "Center", []byte(geometry), []byte("{1,2}"), 1.5, true, createdAt).
+			AddRow(2, "Suburbs", []byte(geometry), []byte("{2,3,4,5}"), 1., false, createdAt))
+
+	want := []*entity.ServiceZone{
+		{
+			ID:               1,
+			Name:             "Center",
+			Geometry:         json.RawMessage(geometry),
+			AllowedTypes:     []int64{1, 2},
+			TariffMultiplier: 1.5,
+			IsActive:         true,
+			CreatedAt:        createdAt,
+		},
+		{
+			ID:               2,
+			Name:             "Suburbs",
+			Geometry:         json.RawMessage(geometry),
+			AllowedTypes:     []int64{2, 3, 4, 5},
+			TariffMultiplier: 1,
+			IsActive:         false,
+			CreatedAt:        createdAt,
+		},
+	}
+
+	got, err := repo.GetZones(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, deep.Equal(want, got))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
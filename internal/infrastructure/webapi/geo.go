@@ -15,12 +15,16 @@ import (
 	"github.com/dacore-x/truckly/internal/dto"
 )
 
+// Default maximum number of sources and targets in one distance matrix request
+const defaultMatrixChunkSize = 25
+
 // Geo is a struct for communicating with 2GIS API
 type Geo struct {
-	BaseURLCatalog string
-	BaseURLRouting string
-	APIKeys        map[string]string
-	appLogger      *logger.Logger
+	BaseURLCatalog  string
+	BaseURLRouting  string
+	APIKeys         map[string]string
+	MatrixChunkSize int
+	appLogger       *logger.Logger
 }
 
 // URLQuery is a struct for building request URL
@@ -31,6 +35,10 @@ type URLQuery struct {
 }
 
 func New(cfg *config.GEO, l *logger.Logger) *Geo {
+	chunkSize := cfg.MatrixChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultMatrixChunkSize
+	}
 	return &Geo{
 		BaseURLCatalog: cfg.BaseURLCatalog,
 		BaseURLRouting: cfg.BaseURLRouting,
@@ -38,7 +46,8 @@ func New(cfg *config.GEO, l *logger.Logger) *Geo {
 			"catalog":    cfg.APIKeyCatalog,
 			"navigation": cfg.APIKeyRouting,
 		},
-		appLogger: l,
+		MatrixChunkSize: chunkSize,
+		appLogger:       l,
 	}
 }
 
@@ -47,7 +56,6 @@ func doRequest(method, URL string, body io.Reader) (*http.Response, error) {
 	switch method {
 	case http.MethodGet:
 		r, err := http.Get(URL)
-		r.Header.Set("Content-Type", "application/json")
 		if err != nil {
 			return nil, err
 		}
@@ -75,8 +83,9 @@ func buildQuery(u *URLQuery) string {
 	return URL.String()
 }
 
-// GetCoordsByObject converts query to object dto.PointResponse
-func (g *Geo) GetCoordsByObject(q string) (*dto.PointResponse, error) {
+// GetCoordsByObject converts query to object dto.PointResponse,
+// search is limited to the city if its 2GIS id is set
+func (g *Geo) GetCoordsByObject(q, cityGeocoderID string) (*dto.PointResponse, error) {
 	if q == "" {
 		err := errors.New("query is empty")
 		g.appLogger.Error(err)
@@ -87,12 +96,14 @@ func (g *Geo) GetCoordsByObject(q string) (*dto.PointResponse, error) {
 		base:     g.BaseURLCatalog,
 		endpoint: "/3.0/items/geocode",
 		params: map[string]string{
-			"q":       q,
-			"key":     g.APIKeys["catalog"],
-			"fields":  "items.point",
-			"city_id": "4504222397630173",
+			"q":      q,
+			"key":    g.APIKeys["catalog"],
+			"fields": "items.point",
 		},
 	}
+	if cityGeocoderID != "" {
+		u.params["city_id"] = cityGeocoderID
+	}
 
 	URL := buildQuery(u)
 	result, err := doRequest(http.MethodGet, URL, nil)
@@ -184,24 +195,137 @@ func (g *Geo) GetObjectByCoords(lat, lon float64) (string, error) {
 	return response.Result.Items[0].Address, nil
 }
 
-// GetDistanceBetweenPoints calculating distance between 2 points (from and to) with input latitude and longitude
-func (g *Geo) GetDistanceBetweenPoints(latFrom, lonFrom, latTo, lonTo float64) (float64, error) {
-	if latFrom == 0 || lonFrom == 0 || latTo == 0 || lonTo == 0 {
-		err := errors.New("coordinate couldn't be zero")
+// GetSuggestions returns geo objects matching the user's input ranked by 2GIS,
+// search is limited to the city if its 2GIS id is set and biased to the location if it is set
+func (g *Geo) GetSuggestions(q, cityGeocoderID string, location *dto.PointRequest, limit int) ([]*dto.SuggestionResponse, error) {
+	if q == "" {
+		err := errors.New("query is empty")
 		g.appLogger.Error(err)
-		return 0, err
+		return nil, err
 	}
 
 	u := &URLQuery{
-		base:     g.BaseURLRouting,
-		endpoint: "/get_dist_matrix",
+		base:     g.BaseURLCatalog,
+		endpoint: "/3.0/suggests",
 		params: map[string]string{
-			"key":     g.APIKeys["navigation"],
-			"version": "2.0",
+			"q":         q,
+			"key":       g.APIKeys["catalog"],
+			"fields":    "items.point,items.full_name,items.address_name",
+			"page_size": fmt.Sprint(limit),
+		},
+	}
+	if cityGeocoderID != "" {
+		u.params["city_id"] = cityGeocoderID
+	}
+	if location != nil {
+		u.params["location"] = fmt.Sprintf("%v,%v", location.Lon, location.Lat)
+	}
+
+	response, err := g.doCatalogRequest(u)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]*dto.SuggestionResponse, 0, len(response.Result.Items))
+	for _, item := range response.Result.Items {
+		// Objects without point such as rubrics can't be used as an address
+		if item.Point.Lat == 0 && item.Point.Lon == 0 {
+			continue
+		}
+		suggestions = append(suggestions, itemToSuggestion(item))
+	}
+	return suggestions, nil
+}
+
+// GetObjectByID returns geo object previously suggested to the user by its 2GIS id
+func (g *Geo) GetObjectByID(id string) (*dto.SuggestionResponse, error) {
+	if id == "" {
+		err := errors.New("id is empty")
+		g.appLogger.Error(err)
+		return nil, err
+	}
+
+	u := &URLQuery{
+		base:     g.BaseURLCatalog,
+		endpoint: "/3.0/items/byid",
+		params: map[string]string{
+			"id":     id,
+			"key":    g.APIKeys["catalog"],
+			"fields": "items.point,items.full_name,items.address_name",
 		},
 	}
 
+	response, err := g.doCatalogRequest(u)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.Result.Items) == 0 {
+		err := errors.New("results not found by id")
+		g.appLogger.Error(err)
+		return nil, err
+	}
+	return itemToSuggestion(response.Result.Items[0]), nil
+}
+
+// doCatalogRequest makes request to 2GIS catalog API and decodes its response
+func (g *Geo) doCatalogRequest(u *URLQuery) (*dto.GeoCoderResponse, error) {
 	URL := buildQuery(u)
+	result, err := doRequest(http.MethodGet, URL, nil)
+	if err != nil {
+		g.appLogger.Error(err)
+		return nil, err
+	}
+
+	response := &dto.GeoCoderResponse{}
+	decoder := json.NewDecoder(result.Body)
+	err = decoder.Decode(response)
+	result.Body.Close()
+
+	if err != nil {
+		err := errors.New("error unmarshalling meta")
+		g.appLogger.Error(err)
+		return nil, err
+	}
+
+	// 2GIS responds with 404 code when nothing is found
+	if response.Meta.StatusCode == http.StatusNotFound {
+		return response, nil
+	}
+	if response.Meta.StatusCode >= 400 {
+		err := errors.New("bad status code from geo")
+		g.appLogger.Error(err)
+		return nil, err
+	}
+	return response, nil
+}
+
+// itemToSuggestion converts 2GIS catalog item to the suggestion
+func itemToSuggestion(item dto.Item) *dto.SuggestionResponse {
+	suggestion := &dto.SuggestionResponse{
+		ID:          item.ID,
+		Name:        item.Name,
+		FullAddress: item.FullName,
+		Type:        item.Type,
+		Point:       item.Point,
+	}
+	if suggestion.Name == "" {
+		suggestion.Name = item.Address
+	}
+	if suggestion.FullAddress == "" {
+		suggestion.FullAddress = item.Address
+	}
+	return suggestion
+}
+
+// GetDistanceBetweenPoints calculating distance between 2 points (from and to) with input latitude and longitude
+func (g *Geo) GetDistanceBetweenPoints(latFrom, lonFrom, latTo, lonTo float64) (float64, error) {
+	if latFrom == 0 || lonFrom == 0 || latTo == 0 || lonTo == 0 {
+		err := errors.New("coordinate couldn't be zero")
+		g.appLogger.Error(err)
+		return 0, err
+	}
+
 	body := dto.DistanceRequest{
 		Points: []dto.PointRequest{
 			{Lat: latFrom, Lon: lonFrom},
@@ -211,23 +335,154 @@ func (g *Geo) GetDistanceBetweenPoints(latFrom, lonFrom, latTo, lonTo float64) (
 		Targets: []int{1},
 		Type:    "jam",
 	}
+	response, err := g.doDistMatrixRequest(&body)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(response.Routes) == 0 {
+		err := errors.New("routes not found")
+		g.appLogger.Error(err)
+		return 0, err
+	}
+	return response.Routes[0].Distance, nil
+}
+
+// GetDistanceMatrix calculating distances and durations from every source to every target point.
+// Points are split into chunks according to provider limits so that
+// N×M matrix is calculated with as few requests as possible
+func (g *Geo) GetDistanceMatrix(sources, targets []dto.PointRequest) (*dto.RouteMatrix, error) {
+	if len(sources) == 0 || len(targets) == 0 {
+		err := errors.New("sources and targets are required")
+		g.appLogger.Error(err)
+		return nil, err
+	}
+
+	for _, points := range [][]dto.PointRequest{sources, targets} {
+		for _, p := range points {
+			if p.Lat == 0 || p.Lon == 0 {
+				err := errors.New("coordinate couldn't be zero")
+				g.appLogger.Error(err)
+				return nil, err
+			}
+		}
+	}
+
+	matrix := &dto.RouteMatrix{
+		Distances: make([][]float64, len(sources)),
+		Durations: make([][]float64, len(sources)),
+	}
+	for i := range sources {
+		matrix.Distances[i] = make([]float64, len(targets))
+		matrix.Durations[i] = make([]float64, len(targets))
+	}
+
+	for sFrom := 0; sFrom < len(sources); sFrom += g.MatrixChunkSize {
+		sTo := sFrom + g.MatrixChunkSize
+		if sTo > len(sources) {
+			sTo = len(sources)
+		}
+		for tFrom := 0; tFrom < len(targets); tFrom += g.MatrixChunkSize {
+			tTo := tFrom + g.MatrixChunkSize
+			if tTo > len(targets) {
+				tTo = len(targets)
+			}
+			err := g.fillMatrixChunk(matrix, sources[sFrom:sTo], targets[tFrom:tTo], sFrom, tFrom)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// fillMatrixChunk requests distances and durations between chunks of sources and targets
+// and puts them into the matrix starting from the row and the column of the chunk
+func (g *Geo) fillMatrixChunk(matrix *dto.RouteMatrix, sources, targets []dto.PointRequest, row, col int) error {
+	// Sources go first in the list of points, targets go after them
+	body := dto.DistanceRequest{
+		Points:  make([]dto.PointRequest, 0, len(sources)+len(targets)),
+		Sources: make([]int, 0, len(sources)),
+		Targets: make([]int, 0, len(targets)),
+		Type:    "jam",
+	}
+	for i, p := range sources {
+		body.Points = append(body.Points, p)
+		body.Sources = append(body.Sources, i)
+	}
+	for i, p := range targets {
+		body.Points = append(body.Points, p)
+		body.Targets = append(body.Targets, len(sources)+i)
+	}
+
+	response, err := g.doDistMatrixRequest(&body)
+	if err != nil {
+		return err
+	}
+
+	// Routes between the same points are not required
+	required := 0
+	for _, s := range sources {
+		for _, t := range targets {
+			if s != t {
+				required++
+			}
+		}
+	}
+
+	found := 0
+	for _, route := range response.Routes {
+		i := route.SourceID
+		j := route.TargetID - len(sources)
+		if i < 0 || i >= len(sources) || j < 0 || j >= len(targets) {
+			continue
+		}
+		if route.Status != "OK" || sources[i] == targets[j] {
+			continue
+		}
+		matrix.Distances[row+i][col+j] = route.Distance
+		matrix.Durations[row+i][col+j] = route.Duration
+		found++
+	}
+
+	if found < required {
+		err := errors.New("routes not found")
+		g.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// doDistMatrixRequest making request to 2GIS distance matrix API and decodes its response
+func (g *Geo) doDistMatrixRequest(body *dto.DistanceRequest) (*dto.DistanceResponse, error) {
+	u := &URLQuery{
+		base:     g.BaseURLRouting,
+		endpoint: "/get_dist_matrix",
+		params: map[string]string{
+			"key":     g.APIKeys["navigation"],
+			"version": "2.0",
+		},
+	}
+
+	URL := buildQuery(u)
 	var buf bytes.Buffer
 	err := json.NewEncoder(&buf).Encode(body)
 	if err != nil {
 		err := errors.New("error encoding body")
 		g.appLogger.Error(err)
-		return 0, err
+		return nil, err
 	}
 	result, err := doRequest(http.MethodPost, URL, &buf)
 	if err != nil {
 		g.appLogger.Errorf("webapi.doRequest: %v", err)
-		return 0, err
+		return nil, err
 	}
 
 	if result.StatusCode != 200 {
+		result.Body.Close()
 		err := errors.New("error response 2gis")
 		g.appLogger.Error(err)
-		return 0, err
+		return nil, err
 	}
 
 	response := &dto.DistanceResponse{}
@@ -238,13 +493,7 @@ func (g *Geo) GetDistanceBetweenPoints(latFrom, lonFrom, latTo, lonTo float64) (
 	if err != nil {
 		err := errors.New("error unmarshalling body")
 		g.appLogger.Error(err)
-		return 0, err
-	}
-
-	if len(response.Routes) == 0 {
-		err := errors.New("routes not found")
-		g.appLogger.Error(err)
-		return 0, err
+		return nil, err
 	}
-	return response.Routes[0].Distance, nil
+	return response, nil
 }
@@ -1,9 +1,13 @@
 package webapi
 
 import (
+	"encoding/json"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
+	"sync/atomic"
 	"testing"
 
 	"github.com/dacore-x/truckly/config"
@@ -78,7 +82,7 @@ func TestGeo_GetCoordsByObject(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := g.GetCoordsByObject(tt.args.q)
+			got, err := g.GetCoordsByObject(tt.args.q, "4504222397630173")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetCoordsByObject() error = %v, wantErr %v", err != nil, tt.wantErr)
 				return
@@ -243,3 +247,156 @@ func TestGeo_GetDistanceBetweenPoints(t *testing.T) {
 		})
 	}
 }
+
+func TestGeo_GetDistanceMatrix(t *testing.T) {
+	// Stub of 2GIS distance matrix API, distance between points
+	// is the difference of their latitudes multiplied by 1000
+	// Chunks are requested concurrently, so the counter is atomic
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var body dto.DistanceRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if len(body.Sources) > 2 || len(body.Targets) > 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		type route struct {
+			SourceID int     `json:"source_id"`
+			TargetID int     `json:"target_id"`
+			Distance float64 `json:"distance"`
+			Duration float64 `json:"duration"`
+			Status   string  `json:"status"`
+		}
+		routes := make([]route, 0)
+		for _, s := range body.Sources {
+			for _, t := range body.Targets {
+				d := math.Abs(body.Points[s].Lat-body.Points[t].Lat) * 1000
+				routes = append(routes, route{SourceID: s, TargetID: t, Distance: d, Duration: d / 10, Status: "OK"})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"routes": routes})
+	}))
+	defer server.Close()
+
+	// Create instance of GeoWebAPI with small chunks
+	testLogger := logrus.New()
+	g := New(
+		&config.GEO{
+			BaseURLRouting:  server.URL,
+			MatrixChunkSize: 2,
+		},
+		logger.New(testLogger),
+	)
+
+	type args struct {
+		sources []dto.PointRequest
+		targets []dto.PointRequest
+	}
+	tests := []struct {
+		name         string
+		args         args
+		want         [][]float64
+		wantRequests int32
+		wantErr      bool
+	}{
+		{
+			name: "empty targets",
+			args: args{
+				sources: []dto.PointRequest{{Lat: 55, Lon: 37}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "one request",
+			args: args{
+				sources: []dto.PointRequest{{Lat: 55, Lon: 37}, {Lat: 56, Lon: 37}},
+				targets: []dto.PointRequest{{Lat: 57, Lon: 37}},
+			},
+			want:         [][]float64{{2000}, {1000}},
+			wantRequests: 1,
+		},
+		{
+			name: "split into chunks",
+			args: args{
+				sources: []dto.PointRequest{{Lat: 55, Lon: 37}, {Lat: 56, Lon: 37}, {Lat: 57, Lon: 37}},
+				targets: []dto.PointRequest{{Lat: 55, Lon: 37}, {Lat: 58, Lon: 37}, {Lat: 59, Lon: 37}},
+			},
+			want: [][]float64{
+				{0, 3000, 4000},
+				{1000, 2000, 3000},
+				{2000, 1000, 2000},
+			},
+			wantRequests: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			got, err := g.GetDistanceMatrix(tt.args.sources, tt.args.targets)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDistanceMatrix() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.Distances, tt.want) {
+				t.Errorf("GetDistanceMatrix() got = %v, want %v", got.Distances, tt.want)
+			}
+			if n := requests.Load(); n != tt.wantRequests {
+				t.Errorf("GetDistanceMatrix() requests = %v, want %v", n, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestGeo_GetSuggestions(t *testing.T) {
+	// Stub of 2GIS suggest API, rubric without point must be skipped
+	var params map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params = map[string]string{}
+		for k := range r.URL.Query() {
+			params[k] = r.URL.Query().Get(k)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"meta": map[string]int{"code": 200},
+			"result": map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": "1", "name": "Кремль", "type": "building", "full_name": "Москва, Кремль", "point": map[string]float64{"lat": 55.75, "lon": 37.61}},
+					{"id": "2", "name": "Музеи", "type": "rubric"},
+					{"id": "3", "type": "street", "address_name": "Тверская улица", "point": map[string]float64{"lat": 55.76, "lon": 37.6}},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	testLogger := logrus.New()
+	g := New(&config.GEO{BaseURLCatalog: server.URL}, logger.New(testLogger))
+
+	want := []*dto.SuggestionResponse{
+		{ID: "1", Name: "Кремль", FullAddress: "Москва, Кремль", Type: "building", Point: dto.PointResponse{Lat: 55.75, Lon: 37.61}},
+		{ID: "3", Name: "Тверская улица", FullAddress: "Тверская улица", Type: "street", Point: dto.PointResponse{Lat: 55.76, Lon: 37.6}},
+	}
+
+	got, err := g.GetSuggestions("крем", "4504222397630173", &dto.PointRequest{Lat: 55.7, Lon: 37.5}, 5)
+	if err != nil {
+		t.Fatalf("GetSuggestions() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSuggestions() got = %v, want %v", got, want)
+	}
+	if params["city_id"] != "4504222397630173" || params["location"] != "37.5,55.7" || params["page_size"] != "5" {
+		t.Errorf("GetSuggestions() sent params = %v", params)
+	}
+
+	_, err = g.GetSuggestions("", "", nil, 5)
+	if err == nil {
+		t.Errorf("GetSuggestions() expected error on empty query")
+	}
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/dacore-x/truckly/pkg/nethelper"
+)
+
+// Client is a struct that sends events to clients' webhook endpoints
+type Client struct {
+	client    *http.Client
+	appLogger *logger.Logger
+}
+
+// NewClient creates webhook client that connects only to public addresses.
+// Addresses are checked when connecting, not only on registration,
+// because the endpoint's host can be re-pointed to an internal address later
+func NewClient(timeout time.Duration, l *logger.Logger) *Client {
+	return newClient(timeout, nethelper.PublicOnlyControl, l)
+}
+
+func newClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error, l *logger.Logger) *Client {
+	// Proxy is disabled so that connections go directly to the checked address
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout: timeout,
+		Control: control,
+	}).DialContext
+
+	return &Client{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// Redirects are not followed so that payload goes only to the registered URL
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		appLogger: l,
+	}
+}
+
+// Send posts JSON payload to the endpoint and returns status of the response,
+// status is 0 if the endpoint is unreachable and non-2xx status is reported as error
+func (c *Client) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		c.appLogger.Error(err)
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Truckly-Webhooks/1.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.appLogger.Error(err)
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Response body is not used, it is drained so that the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode/100 != 2 {
+		err = fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+		c.appLogger.Error(err)
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, nil
+}
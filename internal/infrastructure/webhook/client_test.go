@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Send(t *testing.T) {
+	testLogger := logrus.New()
+	// Test servers listen on loopback, so addresses aren't checked
+	client := newClient(time.Second, nil, logger.New(testLogger))
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantErr    string
+	}{
+		{
+			name: "event is accepted",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				require.Equal(t, "t=1,v1=abc", r.Header.Get("X-Truckly-Signature"))
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, `{"event":"delivery.created"}`, string(body))
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "endpoint fails",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    "endpoint responded with status 500",
+		},
+		{
+			name: "redirect is not followed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "http://example.com", http.StatusFound)
+			},
+			wantStatus: http.StatusFound,
+			wantErr:    "endpoint responded with status 302",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			status, err := client.Send(context.Background(), server.URL,
+				map[string]string{"X-Truckly-Signature": "t=1,v1=abc"}, []byte(`{"event":"delivery.created"}`))
+			require.Equal(t, tt.wantStatus, status)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Send_InternalAddress(t *testing.T) {
+	testLogger := logrus.New()
+	client := NewClient(time.Second, logger.New(testLogger))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached internal address")
+	}))
+	defer server.Close()
+
+	status, err := client.Send(context.Background(), server.URL, nil, []byte(`{}`))
+	require.Equal(t, 0, status)
+	require.ErrorContains(t, err, "connection to non-public address 127.0.0.1 is not allowed")
+}
@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// accountHandlers is a non-exportable struct
+// that provides handlers of users' email verification and passwords
+type accountHandlers struct {
+	usecase.Account
+}
+
+// newAccountHandlers initializes a group of account's routes
+func newAccountHandlers(superGroup *gin.RouterGroup, u usecase.Account, m *middleware.Middlewares) {
+	handler := &accountHandlers{u}
+
+	userGroup := superGroup.Group("/user")
+	{
+		userGroup.POST("/verify-email", handler.verifyEmail)
+		userGroup.POST("/verify-email/resend", m.RequireAuth, handler.resendVerificationEmail)
+		userGroup.POST("/password/forgot", handler.forgotPassword)
+		userGroup.POST("/password/reset", handler.resetPassword)
+		userGroup.PUT("/password", m.RequireAuth, handler.changePassword)
+	}
+}
+
+// verifyEmail handler verifies user's email by the token from the link
+func (h *accountHandlers) verifyEmail(c *gin.Context) {
+	var body dto.EmailVerifyRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.VerifyEmail(context.Background(), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "email is verified",
+	})
+}
+
+// resendVerificationEmail handler sends new email verification link to the user
+func (h *accountHandlers) resendVerificationEmail(c *gin.Context) {
+	err := h.SendVerificationEmail(context.Background(), c.GetInt("user"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "verification email is sent",
+	})
+}
+
+// forgotPassword handler sends password reset link to the email,
+// the response is the same whether the account exists or not
+func (h *accountHandlers) forgotPassword(c *gin.Context) {
+	var body dto.PasswordForgotRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.RequestPasswordReset(context.Background(), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "if the account exists, password reset email is sent",
+	})
+}
+
+// resetPassword handler sets new password by the token from the link
+// and logs the user out on all devices
+func (h *accountHandlers) resetPassword(c *gin.Context) {
+	var body dto.PasswordResetRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.ResetPassword(context.Background(), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	clearTokenCookies(c)
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "password is reset",
+	})
+}
+
+// changePassword handler changes password of the logged in user
+// and logs the user out on all other devices
+func (h *accountHandlers) changePassword(c *gin.Context) {
+	var body dto.PasswordChangeRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.ChangePassword(context.Background(), c.GetInt("user"), c.GetString("session"), &body)
+	if errors.Is(err, usecase.ErrWrongPassword) {
+		c.Error(err)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "password is changed",
+	})
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// apiKeyHandlers is a non-exportable struct
+// that provides clients' api keys handlers
+type apiKeyHandlers struct {
+	usecase.APIKey
+}
+
+// newAPIKeyHandlers initializes a group of api keys' routes,
+// keys are managed only within user's session
+func newAPIKeyHandlers(superGroup *gin.RouterGroup, u usecase.APIKey, m *middleware.Middlewares) {
+	handler := &apiKeyHandlers{u}
+
+	apiKeyGroup := superGroup.Group("/api-keys")
+	apiKeyGroup.Use(m.RequireAuth)
+	apiKeyGroup.Use(m.RequireNoBan)
+	{
+		apiKeyGroup.GET("/", handler.getAPIKeys)
+		apiKeyGroup.POST("/", handler.createAPIKey)
+		apiKeyGroup.POST("/:id/rotate", handler.rotateAPIKey)
+		apiKeyGroup.DELETE("/:id", handler.revokeAPIKey)
+	}
+}
+
+// getAPIKeys handler gets active api keys of the client
+func (h *apiKeyHandlers) getAPIKeys(c *gin.Context) {
+	keys, err := h.GetAPIKeys(context.Background(), c.GetInt("user"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": keys,
+	})
+}
+
+// createAPIKey handler issues new api key of the client,
+// the key is shown only in this response
+func (h *apiKeyHandlers) createAPIKey(c *gin.Context) {
+	var body dto.APIKeyRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	key, err := h.CreateAPIKey(context.Background(), c.GetInt("user"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"api_key": key,
+		"key":     key.Key,
+	})
+}
+
+// rotateAPIKey handler gets api key's id from URI and replaces
+// the key with the new one shown only in this response
+func (h *apiKeyHandlers) rotateAPIKey(c *gin.Context) {
+	var req dto.APIKeyIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	key, err := h.RotateAPIKey(context.Background(), c.GetInt("user"), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_key": key,
+		"key":     key.Key,
+	})
+}
+
+// revokeAPIKey handler gets api key's id from URI and revokes it
+func (h *apiKeyHandlers) revokeAPIKey(c *gin.Context) {
+	var req dto.APIKeyIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.RevokeAPIKey(context.Background(), c.GetInt("user"), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "api key is revoked",
+	})
+}
@@ -0,0 +1,198 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// Interval of pings keeping chat stream alive behind proxies
+const chatKeepAlive = 30 * time.Second
+
+// chatHandlers is a non-exportable struct
+// that provides handlers of deliveries' chats
+type chatHandlers struct {
+	usecase.Chat
+}
+
+// newChatHandlers initializes a group of deliveries' chats routes
+func newChatHandlers(superGroup *gin.RouterGroup, u usecase.Chat, m *middleware.Middlewares) {
+	handler := &chatHandlers{u}
+
+	chatGroup := superGroup.Group("/delivery")
+	chatGroup.Use(m.RequireAuth)
+	chatGroup.Use(m.RequireNoBan)
+	{
+		chatGroup.GET("/:id/chat/messages", m.AllowPermission(entity.PermDeliveriesReadAny), handler.getMessages)
+		chatGroup.POST("/:id/chat/messages", handler.sendMessage)
+		chatGroup.POST("/:id/chat/read", handler.markRead)
+		chatGroup.GET("/:id/chat/stream", m.AllowPermission(entity.PermDeliveriesReadAny), handler.streamEvents)
+	}
+}
+
+// sendMessage handler sends participant's message to the delivery's chat
+func (h *chatHandlers) sendMessage(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.ChatMessageRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	userID := c.GetInt("user")
+	msg, err := h.SendMessage(context.Background(), userID, req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, msg)
+}
+
+// getMessages handler gets page of the delivery's chat history
+func (h *chatHandlers) getMessages(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var query dto.ChatHistoryQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	userID := c.GetInt("user")
+	messages, err := h.GetMessages(context.Background(), userID, req.ID, c.GetBool(entity.PermDeliveriesReadAny), &query)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages": messages,
+	})
+}
+
+// markRead handler marks messages of the other participant as read
+func (h *chatHandlers) markRead(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.ChatReadRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	userID := c.GetInt("user")
+	err := h.MarkRead(context.Background(), userID, req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "messages are read",
+	})
+}
+
+// streamEvents handler delivers new messages and read receipts
+// of the delivery's chat as server-sent events
+func (h *chatHandlers) streamEvents(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Subscription lives as long as the client is connected
+	ctx := c.Request.Context()
+	userID := c.GetInt("user")
+	events, unsubscribe, err := h.Subscribe(ctx, userID, req.ID, c.GetBool(entity.PermDeliveriesReadAny))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(chatKeepAlive)
+	defer ticker.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-ticker.C:
+			c.SSEvent("ping", "")
+			return true
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// cityHandlers is a non-exportable struct
+// that provides cities' handlers
+type cityHandlers struct {
+	usecase.City
+}
+
+// newCityHandlers initializes a group of cities' routes
+func newCityHandlers(superGroup *gin.RouterGroup, u usecase.City, m *middleware.Middlewares) {
+	handler := &cityHandlers{u}
+
+	cityGroup := superGroup.Group("/cities")
+	{
+		cityGroup.GET("/", handler.getCities)
+		cityGroup.POST("/", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermCitiesManage), handler.createCity)
+		cityGroup.PUT("/:id", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermCitiesManage), handler.updateCity)
+	}
+}
+
+// getCities handler gets all cities, it is public to let users choose city on sign up
+func (h *cityHandlers) getCities(c *gin.Context) {
+	cities, err := h.GetCities(context.Background())
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, cities)
+}
+
+// createCity handler creates new city
+func (h *cityHandlers) createCity(c *gin.Context) {
+	var body dto.CityRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	city, err := h.CreateCity(context.Background(), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, city)
+}
+
+// updateCity handler gets city's id from URI and updates its settings
+func (h *cityHandlers) updateCity(c *gin.Context) {
+	var req dto.CityIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.CityRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.UpdateCity(context.Background(), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "city is updated",
+	})
+}
@@ -23,14 +23,21 @@ func newDeliveryHandlers(superGroup *gin.RouterGroup, u usecase.Delivery, m *mid
 
 	deliveryGroup := superGroup.Group("/delivery")
 	{
-		deliveryGroup.GET("/search", m.RequireAuth, m.RequireNoBan, m.RequireCourier, handler.getDeliveriesByGeolocation)
-		deliveryGroup.GET("/", m.RequireAuth, m.RequireNoBan, m.RequireCourier, handler.getDeliveriesByCourierID)
-		deliveryGroup.GET("/:id", m.RequireAuth, m.RequireNoBan, handler.getDeliveryByID)
-		deliveryGroup.GET("/my", m.RequireAuth, m.RequireNoBan, handler.getDeliveriesByClientID)
-		deliveryGroup.POST("/", m.RequireAuth, m.RequireNoBan, m.RateLimit, handler.createDelivery)
-		deliveryGroup.POST("/:id/accept", m.RequireAuth, m.RequireNoBan, m.RequireCourier, handler.acceptDelivery)
+		deliveryGroup.GET("/search", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermDeliveriesPerform), handler.getDeliveriesByGeolocation)
+		deliveryGroup.GET("/", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermDeliveriesPerform), handler.getDeliveriesByCourierID)
+		deliveryGroup.GET("/:id", m.AllowAPIKey(entity.ScopeDeliveriesRead), m.RequireAuth, m.RequireNoBan,
+			m.AllowPermission(entity.PermDeliveriesReadAny), handler.getDeliveryByID)
+		deliveryGroup.GET("/my", m.AllowAPIKey(entity.ScopeDeliveriesRead), m.RequireAuth, m.RequireNoBan, handler.getDeliveriesByClientID)
+		deliveryGroup.GET("/route", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermDeliveriesPerform), handler.getActiveRoute)
+		deliveryGroup.POST("/", m.AllowAPIKey(entity.ScopeDeliveriesCreate), m.RequireAuth, m.RequireNoBan,
+			m.RequirePermission(entity.PermDeliveriesCreate), m.RequireVerifiedEmail, m.RequireVerifiedPhone, m.RateLimit, handler.createDelivery)
+		deliveryGroup.POST("/:id/accept", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermDeliveriesPerform), m.RequireVerifiedPhone, handler.acceptDelivery)
+		deliveryGroup.POST("/:id/pickup", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermDeliveriesPerform), handler.pickUpDelivery)
 		deliveryGroup.POST("/:id/cancel", m.RequireAuth, m.RequireNoBan, handler.cancelDelivery)
-		deliveryGroup.POST("/:id/status", m.RequireAuth, m.RequireNoBan, m.RequireCourier, handler.changeDeliveryStatus)
+		deliveryGroup.POST("/:id/force-cancel", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermDeliveriesCancelAny), handler.cancelAnyDelivery)
+		deliveryGroup.POST("/:id/status", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermDeliveriesPerform), handler.changeDeliveryStatus)
+		deliveryGroup.GET("/:id/timeline", m.AllowAPIKey(entity.ScopeDeliveriesRead), m.RequireAuth, m.RequireNoBan,
+			m.AllowPermission(entity.PermDeliveriesReadAny), handler.getTimeline)
 	}
 }
 
@@ -47,17 +54,54 @@ func (h *deliveryHandlers) createDelivery(c *gin.Context) {
 
 	clientID := c.GetInt("user")
 	geo := &entity.Geo{
-		FromLongitude: body.FromPoint.Lon,
-		FromLatitude:  body.FromPoint.Lat,
-		ToLongitude:   body.ToPoint.Lon,
-		ToLatitude:    body.ToPoint.Lat,
+		FromPlaceID:      body.FromPlaceID,
+		ToPlaceID:        body.ToPlaceID,
+		FromSavedPlaceID: body.FromSavedPlaceID,
+		ToSavedPlaceID:   body.ToSavedPlaceID,
+	}
+	if body.FromPoint != nil {
+		geo.FromLatitude, geo.FromLongitude = body.FromPoint.Lat, body.FromPoint.Lon
+	}
+	if body.ToPoint != nil {
+		geo.ToLatitude, geo.ToLongitude = body.ToPoint.Lat, body.ToPoint.Lon
 	}
 
 	delivery := &entity.Delivery{
-		ClientID:  clientID,
-		TypeID:    body.TypeID,
-		Geo:       geo,
-		HasLoader: body.HasLoader,
+		ClientID:    clientID,
+		CityID:      c.GetInt("city"),
+		TypeID:      body.TypeID,
+		Geo:         geo,
+		HasLoader:   body.HasLoader,
+		CargoWeight: body.CargoWeight,
+		CargoVolume: body.CargoVolume,
+	}
+	if body.PickupWindow != nil {
+		delivery.PickupWindow = &entity.TimeWindow{From: body.PickupWindow.From, To: body.PickupWindow.To}
+	}
+	if body.DropoffWindow != nil {
+		delivery.DropoffWindow = &entity.TimeWindow{From: body.DropoffWindow.From, To: body.DropoffWindow.To}
+	}
+	if body.PickupContact != nil {
+		delivery.PickupContact = (*entity.StopContact)(body.PickupContact)
+	}
+	if body.DropoffContact != nil {
+		delivery.DropoffContact = (*entity.StopContact)(body.DropoffContact)
+	}
+	if body.OrganizationID != 0 {
+		delivery.OrgID = &body.OrganizationID
+	}
+
+	// Key of the organization creates deliveries only on its behalf
+	if orgID := c.GetInt("api_key_org"); orgID != 0 {
+		if body.OrganizationID != 0 && body.OrganizationID != orgID {
+			err := fmt.Errorf("api key can't create deliveries of another organization")
+			c.Error(err)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		delivery.OrgID = &orgID
 	}
 
 	err := h.CreateDelivery(context.Background(), delivery)
@@ -87,7 +131,7 @@ func (h *deliveryHandlers) getDeliveryByID(c *gin.Context) {
 	}
 
 	clientID := c.GetInt("user")
-	delivery, err := h.GetDeliveryByID(context.Background(), clientID, req.ID)
+	delivery, err := h.GetDeliveryByID(context.Background(), clientID, req.ID, c.GetBool(entity.PermDeliveriesReadAny))
 	if err != nil {
 		c.Error(err)
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -127,6 +171,47 @@ func (h *deliveryHandlers) acceptDelivery(c *gin.Context) {
 	})
 }
 
+func (h *deliveryHandlers) pickUpDelivery(c *gin.Context) {
+	// Get id of delivery from request
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	courierID := c.GetInt("user")
+	err := h.PickUpDelivery(context.Background(), courierID, req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "delivery is picked up",
+	})
+}
+
+func (h *deliveryHandlers) getActiveRoute(c *gin.Context) {
+	courierID := c.GetInt("user")
+	route, err := h.GetActiveRoute(context.Background(), courierID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, route)
+}
+
 func (h *deliveryHandlers) changeDeliveryStatus(c *gin.Context) {
 	// Get id of delivery from request
 	var req dto.DeliveryIdURI
@@ -191,6 +276,33 @@ func (h *deliveryHandlers) cancelDelivery(c *gin.Context) {
 	})
 }
 
+func (h *deliveryHandlers) cancelAnyDelivery(c *gin.Context) {
+	// Get id of delivery from request
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	staffID := c.GetInt("user")
+	err := h.CancelAnyDelivery(context.Background(), staffID, req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "delivery status is changed",
+	})
+}
+
 func (h *deliveryHandlers) getDeliveriesByGeolocation(c *gin.Context) {
 	var q dto.DeliveryListGeolocationQuery
 	if c.ShouldBindQuery(&q) != nil {
@@ -201,6 +313,8 @@ func (h *deliveryHandlers) getDeliveriesByGeolocation(c *gin.Context) {
 		})
 		return
 	}
+	q.CityID = c.GetInt("city")
+
 	results, err := h.GetDeliveriesByGeolocation(context.Background(), &q)
 	if err != nil {
 		c.Error(err)
@@ -280,3 +394,27 @@ func (h *deliveryHandlers) getDeliveriesByCourierID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, results)
 }
+
+// getTimeline handler gets events of the delivery in chronological order
+func (h *deliveryHandlers) getTimeline(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	events, err := h.GetTimeline(context.Background(), c.GetInt("user"), req.ID, c.GetBool(entity.PermDeliveriesReadAny))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
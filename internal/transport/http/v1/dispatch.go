@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// dispatchHandlers is a non-exportable struct
+// that provides automatic dispatch handlers
+type dispatchHandlers struct {
+	usecase.Dispatch
+}
+
+// newDispatchHandlers initializes a group of dispatch routes
+func newDispatchHandlers(superGroup *gin.RouterGroup, u usecase.Dispatch, m *middleware.Middlewares) {
+	handler := &dispatchHandlers{u}
+
+	dispatchGroup := superGroup.Group("/dispatch")
+	dispatchGroup.Use(m.RequireAuth)
+	dispatchGroup.Use(m.RequireNoBan)
+	dispatchGroup.Use(m.RequirePermission(entity.PermDeliveriesPerform))
+	{
+		dispatchGroup.POST("/presence", handler.setPresence)
+		dispatchGroup.GET("/offers", handler.pendingOffers)
+		dispatchGroup.POST("/offers/:id/accept", m.RequireVerifiedPhone, handler.acceptOffer)
+		dispatchGroup.POST("/offers/:id/decline", handler.declineOffer)
+	}
+}
+
+// setPresence handler updates courier's online status and location
+func (h *dispatchHandlers) setPresence(c *gin.Context) {
+	var body dto.CourierPresenceRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	courierID := c.GetInt("user")
+	err := h.SetCourierPresence(context.Background(), courierID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "presence is updated",
+	})
+}
+
+// pendingOffers handler gets courier's offers waiting for response
+func (h *dispatchHandlers) pendingOffers(c *gin.Context) {
+	courierID := c.GetInt("user")
+	offers, err := h.GetPendingOffers(context.Background(), courierID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// acceptOffer handler gets offer's id from URI and accepts it
+func (h *dispatchHandlers) acceptOffer(c *gin.Context) {
+	var req dto.OfferIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	courierID := c.GetInt("user")
+	err := h.AcceptOffer(context.Background(), courierID, req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "offer is accepted",
+	})
+}
+
+// declineOffer handler gets offer's id from URI and declines it
+func (h *dispatchHandlers) declineOffer(c *gin.Context) {
+	var req dto.OfferIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	courierID := c.GetInt("user")
+	err := h.DeclineOffer(context.Background(), courierID, req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "offer is declined",
+	})
+}
@@ -0,0 +1,346 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// disputeHandlers is a non-exportable struct
+// that provides disputes' handlers
+type disputeHandlers struct {
+	usecase.Dispute
+}
+
+// newDisputeHandlers initializes a group of disputes' routes
+func newDisputeHandlers(superGroup *gin.RouterGroup, u usecase.Dispute, m *middleware.Middlewares) {
+	handler := &disputeHandlers{u}
+
+	superGroup.POST("/delivery/:id/disputes", m.RequireAuth, m.RequireNoBan, handler.openDispute)
+	superGroup.GET("/delivery/:id/disputes", m.RequireAuth, m.RequireNoBan, m.AllowPermission(entity.PermDeliveriesReadAny), handler.getDeliveryDisputes)
+
+	disputeGroup := superGroup.Group("/disputes")
+	disputeGroup.Use(m.RequireAuth)
+	disputeGroup.Use(m.RequireNoBan)
+	{
+		disputeGroup.GET("/", m.RequirePermission(entity.PermDisputesManage), handler.getDisputes)
+		disputeGroup.GET("/:id", m.AllowPermission(entity.PermDeliveriesReadAny), handler.getDispute)
+		disputeGroup.POST("/:id/attachments", handler.uploadAttachment)
+		disputeGroup.GET("/:id/attachments/:attachment_id", m.AllowPermission(entity.PermDeliveriesReadAny), handler.getAttachment)
+		disputeGroup.PUT("/:id/assignee", m.RequirePermission(entity.PermDisputesManage), handler.assignDispute)
+		disputeGroup.PUT("/:id/status", m.RequirePermission(entity.PermDisputesManage), handler.changeDisputeStatus)
+		disputeGroup.POST("/:id/resolution", m.RequirePermission(entity.PermDisputesResolve), handler.resolveDispute)
+	}
+}
+
+// openDispute handler opens dispute on the delivery by its participant
+func (h *disputeHandlers) openDispute(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.DisputeRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.OpenDispute(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dispute)
+}
+
+// getDeliveryDisputes handler gets all disputes of the delivery
+func (h *disputeHandlers) getDeliveryDisputes(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	disputes, err := h.GetDeliveryDisputes(context.Background(), c.GetInt("user"), req.ID, c.GetBool(entity.PermDeliveriesReadAny))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"disputes": disputes,
+	})
+}
+
+// getDisputes handler gets page of the admins' queue of disputes
+func (h *disputeHandlers) getDisputes(c *gin.Context) {
+	var query dto.DisputeListQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	disputes, err := h.GetDisputes(context.Background(), &query)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"disputes": disputes,
+	})
+}
+
+// getDispute handler gets the dispute with its attachments
+func (h *disputeHandlers) getDispute(c *gin.Context) {
+	var req dto.DisputeIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.GetDispute(context.Background(), c.GetInt("user"), req.ID, c.GetBool(entity.PermDeliveriesReadAny))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// uploadAttachment handler attaches image sent by the opener as multipart file to the dispute
+func (h *disputeHandlers) uploadAttachment(c *gin.Context) {
+	var req dto.DisputeIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.UploadAttachment(context.Background(), c.GetInt("user"), req.ID, file)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// getAttachment handler returns file attached to the dispute
+func (h *disputeHandlers) getAttachment(c *gin.Context) {
+	var req dto.DisputeAttachmentURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	file, err := h.GetAttachment(context.Background(), c.GetInt("user"), req.ID, req.AttachmentID, c.GetBool(entity.PermDeliveriesReadAny))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, http.DetectContentType(data), data)
+}
+
+// assignDispute handler assigns the dispute to the admin
+func (h *disputeHandlers) assignDispute(c *gin.Context) {
+	var req dto.DisputeIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.DisputeAssignBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.AssignDispute(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "dispute is assigned",
+	})
+}
+
+// changeDisputeStatus handler changes status of unresolved dispute
+func (h *disputeHandlers) changeDisputeStatus(c *gin.Context) {
+	var req dto.DisputeIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.DisputeStatusBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.ChangeDisputeStatus(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "dispute status is changed",
+	})
+}
+
+// resolveDispute handler closes the dispute with admin's decision
+func (h *disputeHandlers) resolveDispute(c *gin.Context) {
+	var req dto.DisputeIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.DisputeResolveBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resolution, err := h.ResolveDispute(context.Background(), c.GetInt("user"), req.ID, &body)
+	if errors.Is(err, usecase.ErrBanForbidden) {
+		c.Error(err)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resolution)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/dacore-x/truckly/internal/dto"
 	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
 	"github.com/dacore-x/truckly/internal/usecase"
 )
@@ -23,12 +24,43 @@ func newGeoHandlers(superGroup *gin.RouterGroup, u usecase.Geo, m *middleware.Mi
 	{
 		geoGroup.GET("/coords", m.RequireAuth, m.RequireNoBan, handler.getCoordsByObject)
 		geoGroup.GET("/object", m.RequireAuth, m.RequireNoBan, handler.getObjectByCoords)
+		geoGroup.GET("/suggest", m.RequireAuth, m.RequireNoBan, handler.getSuggestions)
 	}
 }
 
+// getSuggestions handler returns ranked candidates of the address typed by the user,
+// id of the chosen candidate can be passed on delivery creation
+func (h *geoHandlers) getSuggestions(c *gin.Context) {
+	var query dto.SuggestQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	suggestions, err := h.GetSuggestions(context.Background(), &query, c.GetInt("city"))
+	if err != nil {
+		err := fmt.Errorf("error finding geo objects")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Let the client reuse suggestions while the user is typing
+	c.Header("Cache-Control", "private, max-age=60")
+	c.JSON(http.StatusOK, gin.H{
+		"suggestions": suggestions,
+	})
+}
+
 func (h *geoHandlers) getCoordsByObject(c *gin.Context) {
 	q := c.Query("q")
-	coords, err := h.GetCoordsByObject(context.Background(), q)
+	coords, err := h.GetCoordsByObject(context.Background(), q, c.GetInt("city"))
 	if err != nil {
 		err := fmt.Errorf("error finding geo object")
 		c.Error(err)
@@ -2,10 +2,13 @@ package v1
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
 	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
 	"github.com/dacore-x/truckly/internal/usecase"
 )
@@ -23,7 +26,7 @@ func newMetricsHandlers(superGroup *gin.RouterGroup, u usecase.Metrics, m *middl
 	metricsGroup := superGroup.Group("/metrics")
 	metricsGroup.Use(m.RequireAuth)
 	metricsGroup.Use(m.RequireNoBan)
-	metricsGroup.Use(m.RequireAdmin)
+	metricsGroup.Use(m.RequirePermission(entity.PermMetricsRead))
 	{
 		metricsGroup.GET("/", handler.metricsPerDay)
 		metricsGroup.GET("/map", handler.currentDeliveries)
@@ -31,8 +34,19 @@ func newMetricsHandlers(superGroup *gin.RouterGroup, u usecase.Metrics, m *middl
 }
 
 // metricsPerDay handler gets all metrics per last 24 hours
+// optionally filtered by city
 func (h *metricsHandlers) metricsPerDay(c *gin.Context) {
-	metrics, err := h.GetMetrics(context.Background())
+	var q dto.MetricsQuery
+	if c.ShouldBindQuery(&q) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	metrics, err := h.GetMetrics(context.Background(), q.CityID)
 	if err != nil {
 		c.Error(err)
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -47,8 +61,19 @@ func (h *metricsHandlers) metricsPerDay(c *gin.Context) {
 }
 
 // currentDeliveries handler gets all current deliveries
+// optionally filtered by city
 func (h *metricsHandlers) currentDeliveries(c *gin.Context) {
-	list, err := h.GetCurrentDeliveries(context.Background())
+	var q dto.MetricsQuery
+	if c.ShouldBindQuery(&q) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	list, err := h.GetCurrentDeliveries(context.Background(), q.CityID)
 	if err != nil {
 		c.Error(err)
 		c.JSON(http.StatusBadRequest, gin.H{
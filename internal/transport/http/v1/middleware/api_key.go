@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// apiKeyMiddlewares is a non-exportable struct
+// that provides middlewares of clients' api keys
+type apiKeyMiddlewares struct {
+	usecase.APIKey
+	redisClient *redis.Client
+}
+
+// AllowAPIKey middleware authenticates request by the api key given as bearer token
+// if it is present, checks that the key is granted the scope and its rate limit
+// is not exceeded and attaches the key's owner and the key to the request.
+// Requests without api key are passed to the next middleware as is
+func (m *apiKeyMiddlewares) AllowAPIKey(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := bearerToken(c)
+		if !strings.HasPrefix(rawKey, entity.APIKeyPrefix) {
+			c.Next()
+			return
+		}
+
+		key, err := m.Authenticate(context.Background(), rawKey)
+		if err != nil {
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		// Check the scope
+		if !key.Allows(scope) {
+			err := fmt.Errorf("api key is not granted scope %v", scope)
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		// Check requests per minute of the key, it is limited
+		// separately from the owner's requests
+		limitKey := fmt.Sprintf("api_key_rate:%v:%v", key.ID, time.Now().Unix()/60)
+		counter, err := m.redisClient.Incr(context.Background(), limitKey).Result()
+		if err != nil {
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "rate limit is not available",
+			})
+			return
+		}
+		if counter == 1 {
+			m.redisClient.Expire(context.Background(), limitKey, time.Minute)
+		}
+		if counter > int64(key.RateLimit) {
+			err := fmt.Errorf("requests limit reached")
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		// Attach to request
+		c.Set("user", key.UserID)
+		c.Set("api_key", key.ID)
+		if key.OrgID != nil {
+			c.Set("api_key_org", *key.OrgID)
+		}
+
+		// Continue
+		c.Next()
+	}
+}
+
+// bearerToken gets token from the Authorization header of the request
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
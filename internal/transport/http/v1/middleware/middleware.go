@@ -13,12 +13,16 @@ type Middlewares struct {
 	userMiddlewares
 	loggerMiddlewares
 	redisMiddlewares
+	apiKeyMiddlewares
+	roleMiddlewares
 }
 
-func New(u usecase.User, l *logger.Logger, rdb *redis.Client) *Middlewares {
+func New(u usecase.User, s usecase.Session, k usecase.APIKey, r usecase.Role, l *logger.Logger, rdb *redis.Client) *Middlewares {
 	return &Middlewares{
-		userMiddlewares{u},
+		userMiddlewares{u, s},
 		loggerMiddlewares{l},
 		redisMiddlewares{rdb},
+		apiKeyMiddlewares{k, rdb},
+		roleMiddlewares{r},
 	}
 }
@@ -17,7 +17,8 @@ type redisMiddlewares struct {
 }
 
 // RateLimit middleware checks if user's request rate
-// is exceeded the number of requests limit
+// is exceeded the number of requests limit, requests
+// made with api keys are counted for each key separately
 func (m *redisMiddlewares) RateLimit(c *gin.Context) {
 	// Limit the number of requests to 5 requests per 10 minutes
 	var (
@@ -28,6 +29,9 @@ func (m *redisMiddlewares) RateLimit(c *gin.Context) {
 	// Get user id
 	// Convert it to string since it is used as redis key
 	userKey := fmt.Sprintf("%v", c.GetInt("user"))
+	if keyID := c.GetInt("api_key"); keyID != 0 {
+		userKey = fmt.Sprintf("api_key:%v", keyID)
+	}
 
 	// Set key if not exists
 	m.redisClient.SetNX(context.Background(), userKey, 1, limitTime).Val()
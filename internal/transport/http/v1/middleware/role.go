@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// roleMiddlewares is a non-exportable struct
+// that provides middlewares of users' roles
+type roleMiddlewares struct {
+	usecase.Role
+}
+
+// RequirePermission middleware checks if user's roles grant the permission,
+// permissions are cached so the check doesn't hit the database on every request.
+// Staff permissions also require the session to have passed the second factor
+func (m *roleMiddlewares) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user from keys
+		userKey := c.GetInt("user")
+
+		ok, err := m.HasPermission(context.Background(), userKey, permission)
+		if err != nil {
+			err := fmt.Errorf("user is not found")
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		// Check for the permission
+		if !ok {
+			err := fmt.Errorf("permission %v is required", permission)
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		// Check that the session has passed the second factor, it is mandatory for staff
+		if entity.IsStaffPermission(permission) && !c.GetBool("two_factor") {
+			err := fmt.Errorf("two-factor authentication is required for staff")
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		// continue
+		c.Next()
+	}
+}
+
+// AllowPermission middleware sets key of the permission to whether user's roles grant it
+// without aborting the request, so handlers can widen access for users having it.
+// Staff permissions are set only if the session has passed the second factor
+func (m *roleMiddlewares) AllowPermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user from keys
+		userKey := c.GetInt("user")
+
+		// Failed check falls back to the access of regular users
+		ok, err := m.HasPermission(context.Background(), userKey, permission)
+		if err != nil {
+			ok = false
+		}
+		if entity.IsStaffPermission(permission) && !c.GetBool("two_factor") {
+			ok = false
+		}
+		c.Set(permission, ok)
+
+		// continue
+		c.Next()
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/net/context"
 
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
 	"github.com/dacore-x/truckly/internal/usecase"
 )
 
@@ -18,15 +21,36 @@ import (
 // that provides user-related middlewares
 type userMiddlewares struct {
 	usecase.User
+	sessions usecase.Session
 }
 
 // RequireAuth middleware checks if user is authenticated
-// by decoding and validating user's jwt token and attaches
-// private user's data to the request
+// by decoding and validating user's jwt token given as bearer token
+// or cookie, checks that its session is not revoked and attaches
+// private user's data and the session to the request.
+// Requests already authenticated by api key are passed as is
 func (m *userMiddlewares) RequireAuth(c *gin.Context) {
-	// Get cookie from request
-	tokenString, err := c.Cookie("Authorization")
-	if err != nil {
+	if c.GetInt("api_key") != 0 {
+		c.Next()
+		return
+	}
+
+	// Get token from header or cookie
+	tokenString := bearerToken(c)
+	if strings.HasPrefix(tokenString, entity.APIKeyPrefix) {
+		err := fmt.Errorf("api key is not allowed for this route")
+		c.Error(err)
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var err error
+	if tokenString == "" {
+		tokenString, err = c.Cookie("Authorization")
+	}
+	if err != nil || tokenString == "" {
 		err := fmt.Errorf("user is not authorized")
 		c.Error(err)
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
@@ -67,9 +91,23 @@ func (m *userMiddlewares) RequireAuth(c *gin.Context) {
 				return
 			}
 
+			// Check that the session is not revoked
+			sub, _ := claims["sub"].(float64)
+			sid, _ := claims["sid"].(string)
+			session, err := m.sessions.CheckSession(context.Background(), int(sub), sid)
+			if err != nil {
+				err := fmt.Errorf("user is not authorized")
+				c.Error(err)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
 			// Attach to request
-			sub := claims["sub"].(float64)
 			c.Set("user", int(sub))
+			c.Set("session", sid)
+			c.Set("two_factor", session.TwoFactor)
 		}
 	}
 
@@ -77,7 +115,8 @@ func (m *userMiddlewares) RequireAuth(c *gin.Context) {
 	c.Next()
 }
 
-// RequireNoBan middleware checks if user is not banned
+// RequireNoBan middleware checks if user is not banned and attaches user's city
+// and metadata to the request, so that the next checks don't load it again
 func (m *userMiddlewares) RequireNoBan(c *gin.Context) {
 	// Check user authorization
 	userKey := c.GetInt("user")
@@ -103,17 +142,17 @@ func (m *userMiddlewares) RequireNoBan(c *gin.Context) {
 		return
 	}
 
+	c.Set("city", resp.CityID)
+	c.Set("meta", resp)
+
 	// continue
 	c.Next()
 }
 
-// RequireAdmin middleware checks if user has admin privileges
-func (m *userMiddlewares) RequireAdmin(c *gin.Context) {
-	// Get user from keys
-	userKey := c.GetInt("user")
-
-	// Check for existence
-	resp, err := m.GetUserMeta(context.Background(), userKey)
+// RequireVerifiedEmail middleware checks if user's email is verified
+func (m *userMiddlewares) RequireVerifiedEmail(c *gin.Context) {
+	// Get metadata attached by RequireNoBan
+	resp, err := m.userMeta(c)
 	if err != nil {
 		err := fmt.Errorf("user is not found")
 		c.Error(err)
@@ -123,9 +162,9 @@ func (m *userMiddlewares) RequireAdmin(c *gin.Context) {
 		return
 	}
 
-	// Check for admin privileges
-	if !resp.IsAdmin {
-		err := fmt.Errorf("user is not admin")
+	// Check for verified email
+	if !resp.IsEmailVerified {
+		err := fmt.Errorf("email is not verified")
 		c.Error(err)
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 			"error": err.Error(),
@@ -137,13 +176,10 @@ func (m *userMiddlewares) RequireAdmin(c *gin.Context) {
 	c.Next()
 }
 
-// RequireCourier middleware checks if user's role is courier
-func (m *userMiddlewares) RequireCourier(c *gin.Context) {
-	// Get user from keys
-	userKey := c.GetInt("user")
-
-	// Check for existence
-	resp, err := m.GetUserMeta(context.Background(), userKey)
+// RequireVerifiedPhone middleware checks if user's phone number is verified
+func (m *userMiddlewares) RequireVerifiedPhone(c *gin.Context) {
+	// Get metadata attached by RequireNoBan
+	resp, err := m.userMeta(c)
 	if err != nil {
 		err := fmt.Errorf("user is not found")
 		c.Error(err)
@@ -153,9 +189,9 @@ func (m *userMiddlewares) RequireCourier(c *gin.Context) {
 		return
 	}
 
-	// Check for courier role
-	if !resp.IsCourier {
-		err := fmt.Errorf("user is not courier")
+	// Check for verified phone number
+	if !resp.IsPhoneVerified {
+		err := fmt.Errorf("phone number is not verified")
 		c.Error(err)
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 			"error": err.Error(),
@@ -166,3 +202,12 @@ func (m *userMiddlewares) RequireCourier(c *gin.Context) {
 	// continue
 	c.Next()
 }
+
+// userMeta returns user's metadata attached to the request,
+// it is loaded if the request has passed no ban check before
+func (m *userMiddlewares) userMeta(c *gin.Context) (*dto.UserMetaResponse, error) {
+	if resp, ok := c.Get("meta"); ok {
+		return resp.(*dto.UserMetaResponse), nil
+	}
+	return m.GetUserMeta(context.Background(), c.GetInt("user"))
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// notificationHandlers is a non-exportable struct
+// that provides notification settings' handlers
+type notificationHandlers struct {
+	usecase.Notification
+}
+
+// newNotificationHandlers initializes a group of notification settings' routes
+func newNotificationHandlers(superGroup *gin.RouterGroup, u usecase.Notification, m *middleware.Middlewares) {
+	handler := &notificationHandlers{u}
+
+	notificationGroup := superGroup.Group("/notifications")
+	notificationGroup.Use(m.RequireAuth)
+	notificationGroup.Use(m.RequireNoBan)
+	{
+		notificationGroup.GET("/preferences", handler.getPreferences)
+		notificationGroup.PUT("/preferences", handler.updatePreferences)
+	}
+}
+
+// getPreferences handler gets channels the user is notified via
+func (h *notificationHandlers) getPreferences(c *gin.Context) {
+	prefs, err := h.GetPreferences(context.Background(), c.GetInt("user"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// updatePreferences handler sets channels the user wants to be notified via
+func (h *notificationHandlers) updatePreferences(c *gin.Context) {
+	var body dto.NotificationPreferencesBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.UpdatePreferences(context.Background(), c.GetInt("user"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "notification preferences are updated",
+	})
+}
@@ -0,0 +1,493 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// organizationHandlers is a non-exportable struct
+// that provides handlers of organizations
+type organizationHandlers struct {
+	usecase.Organization
+}
+
+// newOrganizationHandlers initializes a group of organizations' routes
+func newOrganizationHandlers(superGroup *gin.RouterGroup, u usecase.Organization, m *middleware.Middlewares) {
+	handler := &organizationHandlers{u}
+
+	orgGroup := superGroup.Group("/organizations")
+	orgGroup.Use(m.RequireAuth)
+	orgGroup.Use(m.RequireNoBan)
+	{
+		orgGroup.GET("/", handler.getOrganizations)
+		orgGroup.POST("/", m.RequireVerifiedEmail, handler.createOrganization)
+		orgGroup.POST("/accept", handler.acceptInvitation)
+		orgGroup.GET("/:id", handler.getOrganization)
+		orgGroup.PUT("/:id", handler.updateOrganization)
+		orgGroup.GET("/:id/members", handler.getMembers)
+		orgGroup.POST("/:id/invitations", handler.inviteMember)
+		orgGroup.PUT("/:id/members/:user_id", handler.updateMemberRole)
+		orgGroup.DELETE("/:id/members/:user_id", handler.removeMember)
+		orgGroup.GET("/:id/places", handler.getPlaces)
+		orgGroup.POST("/:id/places", handler.createPlace)
+		orgGroup.PUT("/:id/places/:place_id", handler.updatePlace)
+		orgGroup.DELETE("/:id/places/:place_id", handler.deletePlace)
+		orgGroup.GET("/:id/deliveries", handler.getDeliveries)
+		orgGroup.GET("/:id/spending", handler.getSpending)
+	}
+}
+
+// getOrganizations handler gets organizations the user is a member of
+func (h *organizationHandlers) getOrganizations(c *gin.Context) {
+	orgs, err := h.GetOrganizations(context.Background(), c.GetInt("user"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// createOrganization handler creates a new organization owned by the user
+func (h *organizationHandlers) createOrganization(c *gin.Context) {
+	var body dto.OrganizationRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	org, err := h.CreateOrganization(context.Background(), c.GetInt("user"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// acceptInvitation handler adds the user to the organization by the token from the invitation link
+func (h *organizationHandlers) acceptInvitation(c *gin.Context) {
+	var body dto.OrganizationAcceptRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	org, err := h.AcceptInvitation(context.Background(), c.GetInt("user"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// getOrganization handler gets organization's id from URI and gets it
+func (h *organizationHandlers) getOrganization(c *gin.Context) {
+	var req dto.OrganizationIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	org, err := h.GetOrganization(context.Background(), c.GetInt("user"), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// updateOrganization handler gets organization's id from URI
+// and updates its name and billing details
+func (h *organizationHandlers) updateOrganization(c *gin.Context) {
+	var req dto.OrganizationIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.OrganizationRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.UpdateOrganization(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "organization is updated",
+	})
+}
+
+// getMembers handler gets members of the organization
+func (h *organizationHandlers) getMembers(c *gin.Context) {
+	var req dto.OrganizationIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	members, err := h.GetMembers(context.Background(), c.GetInt("user"), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// inviteMember handler sends invitation to join the organization by email
+func (h *organizationHandlers) inviteMember(c *gin.Context) {
+	var req dto.OrganizationIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.OrganizationInviteRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.InviteMember(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": fmt.Sprintf("invitation is sent to %v", body.Email),
+	})
+}
+
+// updateMemberRole handler gets organization's and member's ids from URI
+// and changes role of the member
+func (h *organizationHandlers) updateMemberRole(c *gin.Context) {
+	var req dto.OrganizationMemberURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.OrganizationMemberRoleRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.UpdateMemberRole(context.Background(), c.GetInt("user"), req.ID, req.UserID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "member's role is changed",
+	})
+}
+
+// removeMember handler gets organization's and member's ids from URI
+// and removes the member from the organization
+func (h *organizationHandlers) removeMember(c *gin.Context) {
+	var req dto.OrganizationMemberURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.RemoveMember(context.Background(), c.GetInt("user"), req.ID, req.UserID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "member is removed",
+	})
+}
+
+// getPlaces handler gets the organization's address book
+func (h *organizationHandlers) getPlaces(c *gin.Context) {
+	var req dto.OrganizationIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	places, err := h.GetPlaces(context.Background(), c.GetInt("user"), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, places)
+}
+
+// createPlace handler saves new address to the organization's address book
+func (h *organizationHandlers) createPlace(c *gin.Context) {
+	var req dto.OrganizationIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.SavedPlaceRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	place, err := h.CreatePlace(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, place)
+}
+
+// updatePlace handler gets organization's and place's ids from URI and updates the place
+func (h *organizationHandlers) updatePlace(c *gin.Context) {
+	var req dto.OrganizationPlaceURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.SavedPlaceRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.UpdatePlace(context.Background(), c.GetInt("user"), req.ID, req.PlaceID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "place is updated",
+	})
+}
+
+// deletePlace handler gets organization's and place's ids from URI and deletes the place
+func (h *organizationHandlers) deletePlace(c *gin.Context) {
+	var req dto.OrganizationPlaceURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.DeletePlace(context.Background(), c.GetInt("user"), req.ID, req.PlaceID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "place is deleted",
+	})
+}
+
+// getDeliveries handler gets page of the organization's deliveries
+func (h *organizationHandlers) getDeliveries(c *gin.Context) {
+	var req dto.OrganizationIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var query dto.OrganizationDeliveriesQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	results, err := h.GetDeliveries(context.Background(), c.GetInt("user"), req.ID, &query)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// getSpending handler gets spending report of the organization
+// for the period given as dates in query
+func (h *organizationHandlers) getSpending(c *gin.Context) {
+	var req dto.OrganizationIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var query dto.OrganizationSpendingQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.GetSpending(context.Background(), c.GetInt("user"), req.ID, &query)
+	if err != nil {
+		c.Error(err)
+		c.JSON(organizationErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// organizationErrorStatus maps errors of organizations' members to response statuses
+func organizationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrNotOrgMember), errors.Is(err, usecase.ErrOrgForbidden),
+		errors.Is(err, usecase.ErrOwnOrgOwnership):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// phoneHandlers is a non-exportable struct
+// that provides handlers of users' phone numbers verification
+type phoneHandlers struct {
+	usecase.Phone
+}
+
+// newPhoneHandlers initializes a group of phone number's routes
+func newPhoneHandlers(superGroup *gin.RouterGroup, u usecase.Phone, m *middleware.Middlewares) {
+	handler := &phoneHandlers{u}
+
+	phoneGroup := superGroup.Group("/user/phone")
+	phoneGroup.Use(m.RequireAuth)
+	phoneGroup.Use(m.RequireNoBan)
+	{
+		phoneGroup.POST("/code", handler.sendCode)
+		phoneGroup.POST("/verify", handler.verifyCode)
+	}
+}
+
+// sendCode handler sends one-time code to the user's phone number
+func (h *phoneHandlers) sendCode(c *gin.Context) {
+	err := h.SendCode(context.Background(), c.GetInt("user"))
+	if errors.Is(err, usecase.ErrCodeCooldown) || errors.Is(err, usecase.ErrCodeSendsExceeded) {
+		c.Error(err)
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "code is sent",
+	})
+}
+
+// verifyCode handler checks the code and marks user's phone number as verified
+func (h *phoneHandlers) verifyCode(c *gin.Context) {
+	var body dto.PhoneVerifyRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.VerifyCode(context.Background(), c.GetInt("user"), &body)
+	if errors.Is(err, usecase.ErrCodeAttemptsExceeded) {
+		c.Error(err)
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "phone number is verified",
+	})
+}
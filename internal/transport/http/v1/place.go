@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// savedPlaceHandlers is a non-exportable struct
+// that provides handlers of user's address book
+type savedPlaceHandlers struct {
+	usecase.SavedPlace
+}
+
+// newSavedPlaceHandlers initializes a group of saved places' routes
+func newSavedPlaceHandlers(superGroup *gin.RouterGroup, u usecase.SavedPlace, m *middleware.Middlewares) {
+	handler := &savedPlaceHandlers{u}
+
+	placeGroup := superGroup.Group("/places")
+	placeGroup.Use(m.RequireAuth)
+	placeGroup.Use(m.RequireNoBan)
+	{
+		placeGroup.GET("/", handler.getPlaces)
+		placeGroup.POST("/", handler.createPlace)
+		placeGroup.PUT("/:id", handler.updatePlace)
+		placeGroup.DELETE("/:id", handler.deletePlace)
+	}
+}
+
+// getPlaces handler gets the user's address book
+func (h *savedPlaceHandlers) getPlaces(c *gin.Context) {
+	places, err := h.GetPlaces(context.Background(), c.GetInt("user"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, places)
+}
+
+// createPlace handler saves new address to the user's address book
+func (h *savedPlaceHandlers) createPlace(c *gin.Context) {
+	var body dto.SavedPlaceRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	place, err := h.CreatePlace(context.Background(), c.GetInt("user"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, place)
+}
+
+// updatePlace handler gets place's id from URI and updates it
+func (h *savedPlaceHandlers) updatePlace(c *gin.Context) {
+	var req dto.SavedPlaceIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.SavedPlaceRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.UpdatePlace(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "place is updated",
+	})
+}
+
+// deletePlace handler gets place's id from URI and deletes it
+func (h *savedPlaceHandlers) deletePlace(c *gin.Context) {
+	var req dto.SavedPlaceIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.DeletePlace(context.Background(), c.GetInt("user"), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "place is deleted",
+	})
+}
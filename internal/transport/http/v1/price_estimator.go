@@ -2,12 +2,14 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
 	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
 	"github.com/dacore-x/truckly/internal/usecase"
 )
@@ -21,7 +23,7 @@ func newPriceEstimatorHandlers(superGroup *gin.RouterGroup, u usecase.PriceEstim
 
 	priceEstimatorGroup := superGroup.Group("/price")
 	{
-		priceEstimatorGroup.POST("/predict", m.RequireAuth, m.RequireNoBan, handler.estimatePrice)
+		priceEstimatorGroup.POST("/predict", m.AllowAPIKey(entity.ScopePriceEstimate), m.RequireAuth, m.RequireNoBan, handler.estimatePrice)
 	}
 }
 
@@ -35,7 +37,20 @@ func (h *priceEstimatorHandlers) estimatePrice(c *gin.Context) {
 		})
 		return
 	}
-	price, err := h.EstimateDeliveryPrice(context.Background(), &body)
+	if body.CityID == 0 {
+		body.CityID = c.GetInt("city")
+	}
+	body.UserID = c.GetInt("user")
+
+	resp, err := h.EstimateDeliveryPrice(context.Background(), &body)
+	if errors.Is(err, usecase.ErrOutsideServiceArea) || errors.Is(err, usecase.ErrTypeNotAvailable) ||
+		errors.Is(err, usecase.ErrCityNotServed) || errors.Is(err, usecase.ErrTypeNotEnabledInCity) {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		err := fmt.Errorf("failed to estimate price")
 		c.Error(err)
@@ -45,6 +60,5 @@ func (h *priceEstimatorHandlers) estimatePrice(c *gin.Context) {
 		return
 	}
 
-	resp := dto.EstimatePriceResponse{Price: price}
 	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,210 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// profileHandlers is a non-exportable struct
+// that provides handlers of users' profiles
+type profileHandlers struct {
+	usecase.Profile
+}
+
+// newProfileHandlers initializes a group of users' profiles routes
+func newProfileHandlers(superGroup *gin.RouterGroup, u usecase.Profile, m *middleware.Middlewares) {
+	handler := &profileHandlers{u}
+
+	profileGroup := superGroup.Group("/user")
+	{
+		profileGroup.PATCH("/me", m.RequireAuth, m.RequireNoBan, handler.updateProfile)
+		profileGroup.POST("/me/avatar", m.RequireAuth, m.RequireNoBan, handler.uploadAvatar)
+		profileGroup.POST("/confirm-email", handler.confirmEmailChange)
+		profileGroup.GET("/:id/avatar", m.RequireAuth, handler.getAvatar)
+		profileGroup.GET("/:id/history", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermUsersRead), handler.getHistory)
+	}
+}
+
+// updateProfile handler changes fields of the user's profile set in the request body
+func (h *profileHandlers) updateProfile(c *gin.Context) {
+	var body dto.UserUpdateRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.UpdateProfile(context.Background(), c.GetInt("user"), &body)
+	if errors.Is(err, usecase.ErrEmailTaken) {
+		c.Error(err)
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// confirmEmailChange handler sets the user's new email confirmed by the token from the link
+func (h *profileHandlers) confirmEmailChange(c *gin.Context) {
+	var body dto.EmailChangeRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.ConfirmEmailChange(context.Background(), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "email is changed",
+	})
+}
+
+// uploadAvatar handler stores the user's avatar sent as multipart file
+func (h *profileHandlers) uploadAvatar(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	err = h.UploadAvatar(context.Background(), c.GetInt("user"), file)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "avatar is uploaded",
+	})
+}
+
+// getAvatar handler returns current avatar of the user
+func (h *profileHandlers) getAvatar(c *gin.Context) {
+	var req dto.UserIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	file, err := h.GetAvatar(context.Background(), req.ID)
+	if errors.Is(err, usecase.ErrNoAvatar) {
+		c.Error(err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, http.DetectContentType(data), data)
+}
+
+// getHistory handler gets changes of the user's profile for the support,
+// the profile as it was at the time is returned if it is set in the query
+func (h *profileHandlers) getHistory(c *gin.Context) {
+	var req dto.UserIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var query dto.UserHistoryQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	changes, snapshot, err := h.GetHistory(context.Background(), req.ID, query.At)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes":  changes,
+		"snapshot": snapshot,
+	})
+}
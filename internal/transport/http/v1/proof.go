@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// proofHandlers is a non-exportable struct
+// that provides proof of delivery handlers
+type proofHandlers struct {
+	usecase.Proof
+}
+
+// newProofHandlers initializes a group of proof of delivery routes
+func newProofHandlers(superGroup *gin.RouterGroup, u usecase.Proof, m *middleware.Middlewares) {
+	handler := &proofHandlers{u}
+
+	proofGroup := superGroup.Group("/delivery")
+	proofGroup.Use(m.RequireAuth)
+	proofGroup.Use(m.RequireNoBan)
+	{
+		proofGroup.GET("/:id/proof/:kind", m.AllowPermission(entity.PermDeliveriesReadAny), handler.getProofFile)
+		proofGroup.POST("/:id/proof/:kind", m.RequirePermission(entity.PermDeliveriesPerform), handler.uploadProofFile)
+		proofGroup.POST("/:id/complete", m.RequirePermission(entity.PermDeliveriesPerform), handler.completeDelivery)
+	}
+}
+
+// uploadProofFile handler stores photo or signature sent by the courier as multipart file
+func (h *proofHandlers) uploadProofFile(c *gin.Context) {
+	var req dto.ProofFileURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	err = h.UploadProofFile(context.Background(), c.GetInt("user"), req.ID, req.Kind, file)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": fmt.Sprintf("%s is uploaded", req.Kind),
+	})
+}
+
+// getProofFile handler returns photo or signature of the delivery
+func (h *proofHandlers) getProofFile(c *gin.Context) {
+	var req dto.ProofFileURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	file, err := h.GetProofFile(context.Background(), c.GetInt("user"), req.ID, req.Kind, c.GetBool(entity.PermDeliveriesReadAny))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		err := fmt.Errorf("failed to read file")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, http.DetectContentType(data), data)
+}
+
+// completeDelivery handler completes the delivery with recipient's PIN
+// and courier's position at the dropoff point
+func (h *proofHandlers) completeDelivery(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.ProofCompleteBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.CompleteDelivery(context.Background(), c.GetInt("user"), req.ID, &body)
+	if errors.Is(err, usecase.ErrInvalidPIN) {
+		c.Error(err)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "delivery is completed",
+	})
+}
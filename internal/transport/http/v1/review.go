@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// reviewHandlers is a non-exportable struct
+// that provides ratings and reviews handlers
+type reviewHandlers struct {
+	usecase.Review
+}
+
+// newReviewHandlers initializes a group of reviews' routes
+func newReviewHandlers(superGroup *gin.RouterGroup, u usecase.Review, m *middleware.Middlewares) {
+	handler := &reviewHandlers{u}
+
+	superGroup.POST("/delivery/:id/review", m.RequireAuth, m.RequireNoBan, handler.createReview)
+
+	reviewGroup := superGroup.Group("/reviews")
+	reviewGroup.Use(m.RequireAuth)
+	reviewGroup.Use(m.RequireNoBan)
+	{
+		reviewGroup.GET("/users/:id", handler.getUserReviews)
+		reviewGroup.GET("/", m.RequirePermission(entity.PermReviewsModerate), handler.getReviews)
+		reviewGroup.PUT("/:id/moderation", m.RequirePermission(entity.PermReviewsModerate), handler.moderateReview)
+	}
+}
+
+// createReview handler rates the other participant of the completed delivery
+func (h *reviewHandlers) createReview(c *gin.Context) {
+	var req dto.DeliveryIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.ReviewRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	review, err := h.CreateReview(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// getUserReviews handler gets page of reviews given to the user
+func (h *reviewHandlers) getUserReviews(c *gin.Context) {
+	var req dto.UserIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var query dto.ReviewListQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("page is required")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	reviews, err := h.GetUserReviews(context.Background(), req.ID, query.Page)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// getReviews handler gets page of all reviews for moderation
+func (h *reviewHandlers) getReviews(c *gin.Context) {
+	var query dto.ReviewListQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("page is required")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	reviews, err := h.GetReviews(context.Background(), query.Page)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// moderateReview handler hides or shows text of the review
+func (h *reviewHandlers) moderateReview(c *gin.Context) {
+	var req dto.ReviewIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.ReviewModerationBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.ModerateReview(context.Background(), req.ID, *body.IsCommentHidden)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "review is moderated",
+	})
+}
@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// roleHandlers is a non-exportable struct
+// that provides handlers of users' roles
+type roleHandlers struct {
+	usecase.Role
+}
+
+// newRoleHandlers initializes a group of roles' routes
+func newRoleHandlers(superGroup *gin.RouterGroup, u usecase.Role, m *middleware.Middlewares) {
+	handler := &roleHandlers{u}
+
+	roleGroup := superGroup.Group("/user")
+	roleGroup.Use(m.RequireAuth)
+	roleGroup.Use(m.RequireNoBan)
+	{
+		roleGroup.GET("/permissions", handler.getMyPermissions)
+		roleGroup.GET("/:id/roles", m.RequirePermission(entity.PermRolesManage), handler.getRoles)
+		roleGroup.POST("/:id/roles", m.RequirePermission(entity.PermRolesManage), handler.grantRole)
+		roleGroup.DELETE("/:id/roles/:role", m.RequirePermission(entity.PermRolesManage), handler.revokeRole)
+	}
+}
+
+// getMyPermissions handler gets roles of the user and permissions granted by them
+func (h *roleHandlers) getMyPermissions(c *gin.Context) {
+	userID := c.GetInt("user")
+	roles, err := h.GetRoles(context.Background(), userID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"roles":       roles,
+		"permissions": entity.Permissions(roles),
+	})
+}
+
+// getRoles handler gets roles of the user by id from URI
+func (h *roleHandlers) getRoles(c *gin.Context) {
+	var req dto.UserIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	roles, err := h.GetRoles(context.Background(), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"roles": roles,
+	})
+}
+
+// grantRole handler grants the role from the body to the user by id from URI
+func (h *roleHandlers) grantRole(c *gin.Context) {
+	var req dto.UserIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.RoleRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.GrantRole(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "failed to grant role",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": fmt.Sprintf("role %v is granted to user %v", body.Role, req.ID),
+	})
+}
+
+// revokeRole handler revokes the role from the user, both are taken from URI
+func (h *roleHandlers) revokeRole(c *gin.Context) {
+	var req dto.UserRoleURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.RevokeRole(context.Background(), c.GetInt("user"), req.ID, req.Role)
+	if err != nil {
+		c.Error(err)
+		msg := "failed to revoke role"
+		if errors.Is(err, usecase.ErrOwnRoleRevoke) {
+			msg = err.Error()
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": msg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": fmt.Sprintf("role %v is revoked from user %v", req.Role, req.ID),
+	})
+}
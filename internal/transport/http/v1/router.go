@@ -17,6 +17,24 @@ type Handlers struct {
 	metricsHandlers
 	geoHandlers
 	priceEstimatorHandlers
+	dispatchHandlers
+	zoneHandlers
+	cityHandlers
+	savedPlaceHandlers
+	proofHandlers
+	reviewHandlers
+	chatHandlers
+	disputeHandlers
+	notificationHandlers
+	webhookHandlers
+	sessionHandlers
+	apiKeyHandlers
+	accountHandlers
+	phoneHandlers
+	twoFactorHandlers
+	roleHandlers
+	organizationHandlers
+	profileHandlers
 	*middleware.Middlewares
 }
 
@@ -26,6 +44,24 @@ func NewHandlers(
 	m usecase.Metrics,
 	g usecase.Geo,
 	p usecase.PriceEstimator,
+	dp usecase.Dispatch,
+	z usecase.Zone,
+	ct usecase.City,
+	sp usecase.SavedPlace,
+	pr usecase.Proof,
+	rv usecase.Review,
+	ch usecase.Chat,
+	ds usecase.Dispute,
+	nt usecase.Notification,
+	wh usecase.Webhook,
+	ss usecase.Session,
+	ak usecase.APIKey,
+	ac usecase.Account,
+	ph usecase.Phone,
+	tf usecase.TwoFactor,
+	rl usecase.Role,
+	og usecase.Organization,
+	pf usecase.Profile,
 	l *logger.Logger,
 	rdb *redis.Client,
 ) *Handlers {
@@ -35,7 +71,25 @@ func NewHandlers(
 		metricsHandlers{m},
 		geoHandlers{g},
 		priceEstimatorHandlers{p},
-		middleware.New(u, l, rdb),
+		dispatchHandlers{dp},
+		zoneHandlers{z},
+		cityHandlers{ct},
+		savedPlaceHandlers{sp},
+		proofHandlers{pr},
+		reviewHandlers{rv},
+		chatHandlers{ch},
+		disputeHandlers{ds},
+		notificationHandlers{nt},
+		webhookHandlers{wh},
+		sessionHandlers{ss},
+		apiKeyHandlers{ak},
+		accountHandlers{ac},
+		phoneHandlers{ph},
+		twoFactorHandlers{tf},
+		roleHandlers{rl},
+		organizationHandlers{og},
+		profileHandlers{pf},
+		middleware.New(u, ss, ak, rl, l, rdb),
 	}
 }
 
@@ -50,5 +104,23 @@ func (h *Handlers) NewRouter(r *gin.Engine) {
 		newMetricsHandlers(superGroup, h.metricsHandlers, h.Middlewares)
 		newGeoHandlers(superGroup, h.geoHandlers, h.Middlewares)
 		newPriceEstimatorHandlers(superGroup, h.priceEstimatorHandlers, h.Middlewares)
+		newDispatchHandlers(superGroup, h.dispatchHandlers, h.Middlewares)
+		newZoneHandlers(superGroup, h.zoneHandlers, h.Middlewares)
+		newCityHandlers(superGroup, h.cityHandlers, h.Middlewares)
+		newSavedPlaceHandlers(superGroup, h.savedPlaceHandlers, h.Middlewares)
+		newProofHandlers(superGroup, h.proofHandlers, h.Middlewares)
+		newReviewHandlers(superGroup, h.reviewHandlers, h.Middlewares)
+		newChatHandlers(superGroup, h.chatHandlers, h.Middlewares)
+		newDisputeHandlers(superGroup, h.disputeHandlers, h.Middlewares)
+		newNotificationHandlers(superGroup, h.notificationHandlers, h.Middlewares)
+		newWebhookHandlers(superGroup, h.webhookHandlers, h.Middlewares)
+		newSessionHandlers(superGroup, h.sessionHandlers, h.Middlewares)
+		newAPIKeyHandlers(superGroup, h.apiKeyHandlers, h.Middlewares)
+		newAccountHandlers(superGroup, h.accountHandlers, h.Middlewares)
+		newPhoneHandlers(superGroup, h.phoneHandlers, h.Middlewares)
+		newTwoFactorHandlers(superGroup, h.twoFactorHandlers, h.Middlewares)
+		newRoleHandlers(superGroup, h.roleHandlers, h.Middlewares)
+		newOrganizationHandlers(superGroup, h.organizationHandlers, h.Middlewares)
+		newProfileHandlers(superGroup, h.profileHandlers, h.Middlewares)
 	}
 }
@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// Refresh token is sent only to the routes of the user's group
+const refreshCookiePath = "/api/user"
+
+// sessionHandlers is a non-exportable struct
+// that provides handlers of users' sessions
+type sessionHandlers struct {
+	usecase.Session
+}
+
+// newSessionHandlers initializes a group of sessions' routes
+func newSessionHandlers(superGroup *gin.RouterGroup, u usecase.Session, m *middleware.Middlewares) {
+	handler := &sessionHandlers{u}
+
+	userGroup := superGroup.Group("/user")
+	{
+		userGroup.POST("/login", handler.login)
+		userGroup.POST("/refresh", handler.refresh)
+		userGroup.POST("/logout", m.RequireAuth, handler.logout)
+		userGroup.GET("/sessions", m.RequireAuth, handler.getSessions)
+		userGroup.DELETE("/sessions", m.RequireAuth, handler.revokeAllSessions)
+		userGroup.DELETE("/sessions/:id", m.RequireAuth, handler.revokeSession)
+	}
+}
+
+// login handler checks if user has an account based on
+// request body data and asks for the second factor code
+// if the user has enabled it, starts new session and stores its
+// access and refresh tokens in cookies, the tokens are
+// also returned for clients that send them as bearer token
+func (h *sessionHandlers) login(c *gin.Context) {
+	// Get params from req body
+	var body dto.UserLoginRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.Login(context.Background(), &body, sessionClient(c))
+	if errors.Is(err, usecase.ErrTwoFactorRequired) {
+		c.Error(err)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":               err.Error(),
+			"two_factor_required": true,
+		})
+		return
+	}
+	if errors.Is(err, usecase.ErrTwoFactorLocked) {
+		c.Error(err)
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, usecase.ErrUserBanned) {
+		c.Error(err)
+		clearTokenCookies(c)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	setTokenCookies(c, tokens)
+	c.JSON(http.StatusOK, gin.H{
+		"msg":    "authorization is complete",
+		"tokens": tokens,
+	})
+}
+
+// refresh handler exchanges refresh token from cookie or
+// request body for the new pair of tokens
+func (h *sessionHandlers) refresh(c *gin.Context) {
+	refreshToken, err := c.Cookie("Refresh")
+	if err != nil || refreshToken == "" {
+		var body dto.SessionRefreshBody
+		err = c.ShouldBindJSON(&body)
+		refreshToken = body.RefreshToken
+	}
+	if err != nil {
+		err := fmt.Errorf("refresh token is required")
+		c.Error(err)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.Refresh(context.Background(), refreshToken, sessionClient(c))
+	if errors.Is(err, usecase.ErrInvalidRefreshToken) || errors.Is(err, usecase.ErrRefreshTokenReused) {
+		c.Error(err)
+		clearTokenCookies(c)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	setTokenCookies(c, tokens)
+	c.JSON(http.StatusOK, gin.H{
+		"msg":    "tokens are refreshed",
+		"tokens": tokens,
+	})
+}
+
+// logout handler revokes the current session and clears its cookies
+func (h *sessionHandlers) logout(c *gin.Context) {
+	err := h.RevokeSession(context.Background(), c.GetInt("user"), c.GetString("session"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	clearTokenCookies(c)
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "logout is complete",
+	})
+}
+
+// getSessions handler gets active sessions of the user with their devices
+func (h *sessionHandlers) getSessions(c *gin.Context) {
+	sessions, err := h.GetSessions(context.Background(), c.GetInt("user"), c.GetString("session"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+	})
+}
+
+// revokeSession handler gets session's id from URI and revokes it
+func (h *sessionHandlers) revokeSession(c *gin.Context) {
+	var req dto.SessionIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.RevokeSession(context.Background(), c.GetInt("user"), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if req.ID == c.GetString("session") {
+		clearTokenCookies(c)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "session is revoked",
+	})
+}
+
+// revokeAllSessions handler revokes all sessions of the user including the current one
+func (h *sessionHandlers) revokeAllSessions(c *gin.Context) {
+	err := h.RevokeAllSessions(context.Background(), c.GetInt("user"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	clearTokenCookies(c)
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "all sessions are revoked",
+	})
+}
+
+// sessionClient gets device of the request
+func sessionClient(c *gin.Context) *entity.SessionClient {
+	return &entity.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
+
+// setTokenCookies stores tokens in cookies living as long as the tokens
+func setTokenCookies(c *gin.Context, tokens *entity.AuthTokens) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie("Authorization", tokens.AccessToken, int(time.Until(tokens.AccessExpiresAt).Seconds()), "", "", false, true)
+	c.SetCookie("Refresh", tokens.RefreshToken, int(time.Until(tokens.RefreshExpiresAt).Seconds()), refreshCookiePath, "", false, true)
+}
+
+// clearTokenCookies removes cookies with tokens
+func clearTokenCookies(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie("Authorization", "", -1, "", "", false, true)
+	c.SetCookie("Refresh", "", -1, refreshCookiePath, "", false, true)
+}
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// twoFactorHandlers is a non-exportable struct
+// that provides handlers of users' second factor
+type twoFactorHandlers struct {
+	usecase.TwoFactor
+}
+
+// newTwoFactorHandlers initializes a group of second factor's routes
+func newTwoFactorHandlers(superGroup *gin.RouterGroup, u usecase.TwoFactor, m *middleware.Middlewares) {
+	handler := &twoFactorHandlers{u}
+
+	twoFactorGroup := superGroup.Group("/user/2fa")
+	twoFactorGroup.Use(m.RequireAuth)
+	twoFactorGroup.Use(m.RequireNoBan)
+	{
+		twoFactorGroup.POST("/enroll", handler.enroll)
+		twoFactorGroup.POST("/confirm", handler.confirm)
+		twoFactorGroup.POST("/disable", handler.disable)
+		twoFactorGroup.POST("/recovery-codes", handler.regenerateRecoveryCodes)
+	}
+}
+
+// enroll handler generates secret of the user's authenticator app,
+// client renders QR code of its otpauth URL
+func (h *twoFactorHandlers) enroll(c *gin.Context) {
+	enrollment, err := h.Enroll(context.Background(), c.GetInt("user"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// confirm handler enables the second factor by the first code
+// from authenticator app and shows recovery codes once
+func (h *twoFactorHandlers) confirm(c *gin.Context) {
+	var body dto.TwoFactorCodeRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	codes, err := h.Confirm(context.Background(), c.GetInt("user"), c.GetString("session"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(twoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg":            "two-factor authentication is enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// disable handler disables the second factor by the current code
+func (h *twoFactorHandlers) disable(c *gin.Context) {
+	var body dto.TwoFactorCodeRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.Disable(context.Background(), c.GetInt("user"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(twoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "two-factor authentication is disabled",
+	})
+}
+
+// regenerateRecoveryCodes handler replaces recovery codes by
+// the current code and shows new ones once
+func (h *twoFactorHandlers) regenerateRecoveryCodes(c *gin.Context) {
+	var body dto.TwoFactorCodeRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	codes, err := h.RegenerateRecoveryCodes(context.Background(), c.GetInt("user"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(twoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// twoFactorErrorStatus returns status of the response with the second factor's error
+func twoFactorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrTwoFactorLocked):
+		return http.StatusTooManyRequests
+	case errors.Is(err, usecase.ErrInvalidTwoFactorCode), errors.Is(err, usecase.ErrTwoFactorMandatory):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -4,14 +4,12 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"os"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
 	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
 	"github.com/dacore-x/truckly/internal/usecase"
 )
@@ -30,10 +28,8 @@ func newUserHandlers(superGroup *gin.RouterGroup, u usecase.User, m *middleware.
 	{
 		userGroup.GET("/me", m.RequireAuth, handler.me)
 		userGroup.POST("/signup", handler.signUp)
-		userGroup.POST("/login", handler.login)
-		userGroup.POST("/logout", m.RequireAuth, handler.logout)
-		userGroup.POST("/:id/ban", m.RequireAuth, m.RequireNoBan, m.RequireAdmin, handler.ban)
-		userGroup.POST("/:id/unban", m.RequireAuth, m.RequireNoBan, m.RequireAdmin, handler.unban)
+		userGroup.POST("/:id/ban", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermUsersBan), handler.ban)
+		userGroup.POST("/:id/unban", m.RequireAuth, m.RequireNoBan, m.RequirePermission(entity.PermUsersBan), handler.unban)
 	}
 }
 
@@ -60,6 +56,7 @@ func (h *userHandlers) me(c *gin.Context) {
 		Name:        user.Name,
 		Email:       user.Email,
 		PhoneNumber: user.PhoneNumber,
+		HasAvatar:   user.HasAvatar,
 		CreatedAt:   user.CreatedAt,
 		Meta:        user.Meta,
 	}
@@ -118,68 +115,6 @@ func (h *userHandlers) signUp(c *gin.Context) {
 	})
 }
 
-// login handler checks if user has an account
-// based on request body data, creates new jwt token
-// and stores it in cookie
-func (h *userHandlers) login(c *gin.Context) {
-	// Get params from req body
-	var body dto.UserLoginRequestBody
-	if c.BindJSON(&body) != nil {
-		err := fmt.Errorf("failed to read body")
-		c.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return
-	}
-
-	// Look up requested user in DB
-	user, err := h.GetUserPrivateByEmail(context.Background(), body.Email)
-	if err != nil {
-		err := fmt.Errorf("invalid email or password")
-		c.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return
-	}
-
-	// Compare sent in password wwith saved user password hash
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(body.Password))
-	if err != nil {
-		err := fmt.Errorf("invalid email or password")
-		c.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return
-	}
-
-	// Generate a jwt token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": user.ID,
-		"exp": time.Now().Add(time.Hour).Unix(),
-	})
-
-	tokenString, err := token.SignedString([]byte(os.Getenv("SECRET")))
-	if err != nil {
-		err := fmt.Errorf("failed to create token")
-		c.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return
-	}
-
-	// Send it back
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("Authorization", tokenString, 60*60*24*7, "", "", false, true) // cookie expires in a week
-
-	c.JSON(http.StatusOK, gin.H{
-		"msg": "authorization is complete",
-	})
-}
-
 // ban handler gets user's id from URI and bans him
 func (h *userHandlers) ban(c *gin.Context) {
 	// Get params from request
@@ -237,13 +172,3 @@ func (h *userHandlers) unban(c *gin.Context) {
 		"msg": fmt.Sprintf("user %v has been successfully unbanned", req.ID),
 	})
 }
-
-func (h *userHandlers) logout(c *gin.Context) {
-	// Send it back
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("Authorization", "", -1, "", "", false, true)
-
-	c.JSON(http.StatusOK, gin.H{
-		"msg": "logout is complete",
-	})
-}
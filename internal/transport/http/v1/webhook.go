@@ -0,0 +1,202 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// webhookHandlers is a non-exportable struct
+// that provides clients' webhooks handlers
+type webhookHandlers struct {
+	usecase.Webhook
+}
+
+// newWebhookHandlers initializes a group of webhooks' routes
+func newWebhookHandlers(superGroup *gin.RouterGroup, u usecase.Webhook, m *middleware.Middlewares) {
+	handler := &webhookHandlers{u}
+
+	webhookGroup := superGroup.Group("/webhooks")
+	webhookGroup.Use(m.RequireAuth)
+	webhookGroup.Use(m.RequireNoBan)
+	{
+		webhookGroup.GET("/", handler.getWebhooks)
+		webhookGroup.POST("/", handler.createWebhook)
+		webhookGroup.PUT("/:id", handler.updateWebhook)
+		webhookGroup.DELETE("/:id", handler.deleteWebhook)
+		webhookGroup.GET("/:id/deliveries", handler.getWebhookDeliveries)
+		webhookGroup.POST("/:id/deliveries/:delivery_id/redelivery", handler.redeliver)
+	}
+}
+
+// getWebhooks handler gets all webhooks of the client
+func (h *webhookHandlers) getWebhooks(c *gin.Context) {
+	webhooks, err := h.GetWebhooks(context.Background(), c.GetInt("user"))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhooks,
+	})
+}
+
+// createWebhook handler registers endpoint of the client, secret
+// of the webhook is shown only in this response
+func (h *webhookHandlers) createWebhook(c *gin.Context) {
+	var body dto.WebhookRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	webhook, err := h.CreateWebhook(context.Background(), c.GetInt("user"), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	})
+}
+
+// updateWebhook handler gets webhook's id from URI and changes its endpoint and events
+func (h *webhookHandlers) updateWebhook(c *gin.Context) {
+	var req dto.WebhookIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.WebhookRequestBody
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.UpdateWebhook(context.Background(), c.GetInt("user"), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "webhook is updated",
+	})
+}
+
+// deleteWebhook handler gets webhook's id from URI and deletes it
+func (h *webhookHandlers) deleteWebhook(c *gin.Context) {
+	var req dto.WebhookIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.DeleteWebhook(context.Background(), c.GetInt("user"), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "webhook is deleted",
+	})
+}
+
+// getWebhookDeliveries handler gets page of the webhook's delivery log
+func (h *webhookHandlers) getWebhookDeliveries(c *gin.Context) {
+	var req dto.WebhookIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var query dto.WebhookDeliveryListQuery
+	if c.ShouldBindQuery(&query) != nil {
+		err := fmt.Errorf("failed to read query")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	deliveries, err := h.GetWebhookDeliveries(context.Background(), c.GetInt("user"), req.ID, query.Page)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+	})
+}
+
+// redeliver handler sends the event of the webhook's delivery once more
+func (h *webhookHandlers) redeliver(c *gin.Context) {
+	var req dto.WebhookDeliveryURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	delivery, err := h.Redeliver(context.Background(), c.GetInt("user"), req.ID, req.DeliveryID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/transport/http/v1/middleware"
+	"github.com/dacore-x/truckly/internal/usecase"
+)
+
+// zoneHandlers is a non-exportable struct
+// that provides service zones' handlers
+type zoneHandlers struct {
+	usecase.Zone
+}
+
+// newZoneHandlers initializes a group of service zones' routes
+func newZoneHandlers(superGroup *gin.RouterGroup, u usecase.Zone, m *middleware.Middlewares) {
+	handler := &zoneHandlers{u}
+
+	zoneGroup := superGroup.Group("/zones")
+	zoneGroup.Use(m.RequireAuth)
+	zoneGroup.Use(m.RequireNoBan)
+	zoneGroup.Use(m.RequirePermission(entity.PermZonesManage))
+	{
+		zoneGroup.GET("/", handler.getZones)
+		zoneGroup.POST("/", handler.createZone)
+		zoneGroup.POST("/import", handler.importZones)
+		zoneGroup.PUT("/:id", handler.updateZone)
+		zoneGroup.DELETE("/:id", handler.deleteZone)
+	}
+}
+
+// getZones handler exports all service zones as GeoJSON feature collection
+func (h *zoneHandlers) getZones(c *gin.Context) {
+	zones, err := h.GetZones(context.Background())
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, zones)
+}
+
+// createZone handler creates service zone from GeoJSON feature
+func (h *zoneHandlers) createZone(c *gin.Context) {
+	var body dto.ZoneFeature
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	zone, err := h.CreateZone(context.Background(), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, zone)
+}
+
+// importZones handler creates service zones from GeoJSON feature collection
+func (h *zoneHandlers) importZones(c *gin.Context) {
+	var body dto.ZoneFeatureCollection
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	cnt, err := h.ImportZones(context.Background(), &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"msg": fmt.Sprintf("%d zones are imported", cnt),
+	})
+}
+
+// updateZone handler gets zone's id from URI and replaces it with GeoJSON feature
+func (h *zoneHandlers) updateZone(c *gin.Context) {
+	var req dto.ZoneIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var body dto.ZoneFeature
+	if c.BindJSON(&body) != nil {
+		err := fmt.Errorf("failed to read body")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.UpdateZone(context.Background(), req.ID, &body)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "zone is updated",
+	})
+}
+
+// deleteZone handler gets zone's id from URI and deletes it
+func (h *zoneHandlers) deleteZone(c *gin.Context) {
+	var req dto.ZoneIdURI
+	if c.ShouldBindUri(&req) != nil {
+		err := fmt.Errorf("failed to read uri")
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := h.DeleteZone(context.Background(), req.ID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"msg": "zone is deleted",
+	})
+}
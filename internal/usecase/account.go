@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Errors of the account checks, they are shown to users as is
+var (
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrWrongPassword        = errors.New("current password is wrong")
+)
+
+// AccountUseCase is a struct that provides all use cases of users' email verification
+// and passwords, one-time tokens are sent by email and only their hashes are stored.
+// Emails are queued as notifications with reference to the token and their links
+// are issued by Compose when the worker sends them
+type AccountUseCase struct {
+	users     UserRepo
+	repo      AccountRepo
+	sessions  SessionStore
+	queue     NotificationRepo
+	cfg       *config.AUTH
+	appLogger *logger.Logger
+}
+
+func NewAccountUseCase(u UserRepo, r AccountRepo, s SessionStore, q NotificationRepo, cfg *config.AUTH, l *logger.Logger) *AccountUseCase {
+	return &AccountUseCase{
+		users:     u,
+		repo:      r,
+		sessions:  s,
+		queue:     q,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// SendVerificationEmail usecase sends new email verification link to the user,
+// links sent before stop working
+func (uc *AccountUseCase) SendVerificationEmail(ctx context.Context, userID int) error {
+	meta, err := uc.users.GetUserMeta(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if meta.IsEmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	user, err := uc.users.GetUserPrivateByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	tokenID, err := uc.createToken(ctx, userID, entity.TokenEmailVerification, uc.cfg.VerifyTTL)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = queueTokenMail(ctx, uc.queue, userID, user.Email, entity.NotifyEmailVerification, tokenID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// VerifyEmail usecase marks email of the token's user as verified
+func (uc *AccountUseCase) VerifyEmail(ctx context.Context, req *dto.EmailVerifyRequestBody) error {
+	_, err := uc.repo.VerifyEmail(ctx, hashSecret(req.Token))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// RequestPasswordReset usecase sends password reset link to the email if there is
+// an account with it. Unknown emails are not reported so accounts can't be enumerated
+func (uc *AccountUseCase) RequestPasswordReset(ctx context.Context, req *dto.PasswordForgotRequestBody) error {
+	user, err := uc.users.GetUserPrivateByEmail(ctx, req.Email)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil
+	}
+
+	tokenID, err := uc.createToken(ctx, user.ID, entity.TokenPasswordReset, uc.cfg.ResetTTL)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = queueTokenMail(ctx, uc.queue, user.ID, user.Email, entity.NotifyPasswordReset, tokenID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// ResetPassword usecase sets new password of the token's user and revokes
+// all the user's sessions since the old password may be known to someone else
+func (uc *AccountUseCase) ResetPassword(ctx context.Context, req *dto.PasswordResetRequestBody) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), 10)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return fmt.Errorf("failed to hash password")
+	}
+
+	userID, err := uc.repo.ResetPassword(ctx, hashSecret(req.Token), string(hash))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = uc.sessions.DeleteSessions(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// ChangePassword usecase checks current password of the user, sets the new one
+// and revokes all the user's sessions except the current one
+func (uc *AccountUseCase) ChangePassword(ctx context.Context, userID int, sessionID string, req *dto.PasswordChangeRequestBody) error {
+	user, err := uc.users.GetUserPrivateByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword))
+	if err != nil {
+		return ErrWrongPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), 10)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return fmt.Errorf("failed to hash password")
+	}
+
+	err = uc.repo.UpdatePassword(ctx, userID, string(hash))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	sessions, err := uc.sessions.GetSessions(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			continue
+		}
+		err = uc.sessions.DeleteSession(ctx, userID, session.ID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleEvent queues email verification link to the signed up user. If the event
+// is delivered twice, the second link replaces the first one. Failure to queue
+// the email is returned so that the event is retried
+func (uc *AccountUseCase) HandleEvent(ctx context.Context, e *entity.OutboxEvent) error {
+	if e.AggregateType != entity.AggregateUser || e.Type != entity.UserCreated {
+		return nil
+	}
+
+	err := uc.SendVerificationEmail(ctx, e.AggregateID)
+	if err != nil && !errors.Is(err, ErrEmailAlreadyVerified) {
+		return err
+	}
+	return nil
+}
+
+// Compose issues new secret of the email's token and writes the link with it to the email,
+// the secret is renewed on every attempt to send so that only its hash is stored
+func (uc *AccountUseCase) Compose(ctx context.Context, n *entity.Notification) error {
+	token, err := randomHex(32)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	record, err := uc.repo.RenewUserToken(ctx, n.RefID, hashSecret(token))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if record == nil {
+		return ErrNotificationStale
+	}
+
+	validFor := time.Until(record.ExpiresAt).Round(time.Minute)
+	switch record.Purpose {
+	case entity.TokenEmailVerification:
+		n.Subject = "Confirm your email"
+		n.Body = fmt.Sprintf("Confirm your email by following the link:\n%s/verify-email?token=%s\n\nThe link is valid for %v.",
+			uc.cfg.AppURL, token, validFor)
+	case entity.TokenPasswordReset:
+		n.Subject = "Reset your password"
+		n.Body = fmt.Sprintf("To set new password follow the link:\n%s/reset-password?token=%s\n\n"+
+			"The link is valid for %v. If you did not request it, ignore this email.",
+			uc.cfg.AppURL, token, validFor)
+	case entity.TokenEmailChange:
+		n.Subject = "Confirm your new email"
+		n.Body = fmt.Sprintf("Confirm your new email by following the link:\n%s/confirm-email?token=%s\n\nThe link is valid for %v.",
+			uc.cfg.AppURL, token, validFor)
+	default:
+		return fmt.Errorf("email of %s token is not found", record.Purpose)
+	}
+	return nil
+}
+
+// createToken stores one-time token of the user and returns its id, secret of the token
+// is issued only when the email with it is sent
+func (uc *AccountUseCase) createToken(ctx context.Context, userID int, purpose string, ttl time.Duration) (int, error) {
+	token := &entity.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := createUserToken(ctx, uc.repo, token); err != nil {
+		return 0, err
+	}
+	return token.ID, nil
+}
+
+// createUserToken stores the token with hash of a random secret nobody knows,
+// the secret sent to the user is issued by AccountUseCase.Compose
+func createUserToken(ctx context.Context, repo AccountRepo, token *entity.UserToken) error {
+	secret, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+
+	token.TokenHash = hashSecret(secret)
+	return repo.CreateUserToken(ctx, token)
+}
+
+// queueTokenMail queues email with the link of the user's token, it is composed
+// by AccountUseCase.Compose when sent so that only reference to the token is stored
+func queueTokenMail(ctx context.Context, queue NotificationRepo, userID int, email, event string, tokenID int) error {
+	return queue.CreateNotification(ctx, &entity.Notification{
+		UserID:    userID,
+		Event:     event,
+		Channel:   entity.ChannelEmail,
+		Recipient: email,
+		RefID:     tokenID,
+	})
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// accountUserStub returns the unverified user
+type accountUserStub struct {
+	UserRepo
+}
+
+func (u *accountUserStub) GetUserMeta(ctx context.Context, id int) (*dto.UserMetaResponse, error) {
+	return &dto.UserMetaResponse{UserID: id}, nil
+}
+
+func (u *accountUserStub) GetUserPrivateByID(ctx context.Context, id int) (*dto.UserInfoResponse, error) {
+	return &dto.UserInfoResponse{ID: id, Email: "user@example.com"}, nil
+}
+
+func (u *accountUserStub) GetUserPrivateByEmail(ctx context.Context, email string) (*dto.UserInfoResponse, error) {
+	return &dto.UserInfoResponse{ID: 3, Email: email}, nil
+}
+
+// verifiedUserStub returns the user with verified email
+type verifiedUserStub struct {
+	accountUserStub
+}
+
+func (u *verifiedUserStub) GetUserMeta(ctx context.Context, id int) (*dto.UserMetaResponse, error) {
+	return &dto.UserMetaResponse{UserID: id, IsEmailVerified: true}, nil
+}
+
+// tokenRepoStub keeps one-time tokens in memory, renewed tokens are recorded
+type tokenRepoStub struct {
+	AccountRepo
+	tokens []*entity.UserToken
+}
+
+func (r *tokenRepoStub) CreateUserToken(ctx context.Context, token *entity.UserToken) error {
+	token.ID = len(r.tokens) + 1
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *tokenRepoStub) RenewUserToken(ctx context.Context, id int, tokenHash string) (*entity.UserToken, error) {
+	if id < 1 || id > len(r.tokens) || r.tokens[id-1].ExpiresAt.Before(time.Now()) {
+		return nil, nil
+	}
+	r.tokens[id-1].TokenHash = tokenHash
+	return r.tokens[id-1], nil
+}
+
+// notificationQueueStub records queued notifications or fails queueing
+type notificationQueueStub struct {
+	NotificationRepo
+	queued []*entity.Notification
+	err    error
+}
+
+func (q *notificationQueueStub) CreateNotification(ctx context.Context, n *entity.Notification) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.queued = append(q.queued, n)
+	return nil
+}
+
+func TestAccountUseCase_HandleEvent(t *testing.T) {
+	testLogger := logrus.New()
+	cfg := &config.AUTH{VerifyTTL: time.Hour, AppURL: "https://truckly.example"}
+	event := &entity.OutboxEvent{ID: 1, AggregateType: entity.AggregateUser, AggregateID: 3, Type: entity.UserCreated}
+
+	t.Run("verification email is queued", func(t *testing.T) {
+		queue := &notificationQueueStub{}
+		uc := NewAccountUseCase(&accountUserStub{}, &tokenRepoStub{}, nil, queue, cfg, logger.New(testLogger))
+
+		require.NoError(t, uc.HandleEvent(context.Background(), event))
+		require.Len(t, queue.queued, 1)
+
+		n := queue.queued[0]
+		require.Equal(t, 3, n.UserID)
+		require.Equal(t, entity.NotifyEmailVerification, n.Event)
+		require.Equal(t, entity.ChannelEmail, n.Channel)
+		require.Equal(t, "user@example.com", n.Recipient)
+		require.Equal(t, 1, n.RefID)
+		require.Empty(t, n.Body)
+	})
+
+	t.Run("failure is returned to the bus for retry", func(t *testing.T) {
+		queue := &notificationQueueStub{err: errors.New("database is unavailable")}
+		uc := NewAccountUseCase(&accountUserStub{}, &tokenRepoStub{}, nil, queue, cfg, logger.New(testLogger))
+
+		require.Error(t, uc.HandleEvent(context.Background(), event))
+	})
+
+	t.Run("verified email is skipped", func(t *testing.T) {
+		queue := &notificationQueueStub{}
+		uc := NewAccountUseCase(&verifiedUserStub{}, &tokenRepoStub{}, nil, queue, cfg, logger.New(testLogger))
+
+		require.NoError(t, uc.HandleEvent(context.Background(), event))
+		require.Empty(t, queue.queued)
+	})
+}
+
+func TestAccountUseCase_Compose(t *testing.T) {
+	testLogger := logrus.New()
+	cfg := &config.AUTH{VerifyTTL: time.Hour, ResetTTL: time.Hour, AppURL: "https://truckly.example"}
+
+	t.Run("link is issued when the email is sent", func(t *testing.T) {
+		tokens := &tokenRepoStub{}
+		queue := &notificationQueueStub{}
+		uc := NewAccountUseCase(&accountUserStub{}, tokens, nil, queue, cfg, logger.New(testLogger))
+
+		err := uc.RequestPasswordReset(context.Background(), &dto.PasswordForgotRequestBody{Email: "user@example.com"})
+		require.NoError(t, err)
+		require.Len(t, queue.queued, 1)
+		storedHash := tokens.tokens[0].TokenHash
+
+		n := queue.queued[0]
+		require.NoError(t, uc.Compose(context.Background(), n))
+		require.Equal(t, "Reset your password", n.Subject)
+
+		prefix := "https://truckly.example/reset-password?token="
+		start := strings.Index(n.Body, prefix)
+		require.NotEqual(t, -1, start)
+		token := strings.Fields(n.Body[start+len(prefix):])[0]
+		require.Equal(t, hashSecret(token), tokens.tokens[0].TokenHash)
+		require.NotEqual(t, storedHash, tokens.tokens[0].TokenHash)
+	})
+
+	t.Run("expired token is stale", func(t *testing.T) {
+		tokens := &tokenRepoStub{}
+		uc := NewAccountUseCase(&accountUserStub{}, tokens, nil, &notificationQueueStub{}, cfg, logger.New(testLogger))
+		tokens.tokens = append(tokens.tokens, &entity.UserToken{
+			ID: 1, UserID: 3, Purpose: entity.TokenEmailVerification, ExpiresAt: time.Now().Add(-time.Minute),
+		})
+
+		err := uc.Compose(context.Background(), &entity.Notification{Event: entity.NotifyEmailVerification, RefID: 1})
+		require.ErrorIs(t, err, ErrNotificationStale)
+	})
+}
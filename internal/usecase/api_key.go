@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ErrInvalidAPIKey is shown when the key is malformed, unknown or revoked
+var ErrInvalidAPIKey = errors.New("api key is invalid or revoked")
+
+// Maximum number of active api keys of the client
+var maxAPIKeys = 20
+
+// APIKeyUseCase is a struct that provides all use cases of clients' api keys,
+// keys belong to the client's account and act on its behalf within their scopes.
+// Key of the organization creates deliveries billed to the organization
+type APIKeyUseCase struct {
+	repo      APIKeyRepo
+	orgs      OrganizationRepo
+	cfg       *config.APIKEY
+	appLogger *logger.Logger
+}
+
+func NewAPIKeyUseCase(r APIKeyRepo, o OrganizationRepo, cfg *config.APIKEY, l *logger.Logger) *APIKeyUseCase {
+	return &APIKeyUseCase{repo: r, orgs: o, cfg: cfg, appLogger: l}
+}
+
+// CreateAPIKey usecase issues new key of the client, the plain key is known only in the response.
+// Keys of the organization are issued by its owners and managers
+func (uc *APIKeyUseCase) CreateAPIKey(ctx context.Context, userID int, req *dto.APIKeyRequestBody) (*entity.APIKey, error) {
+	if req.OrganizationID != 0 {
+		role, err := uc.orgs.GetMemberRole(ctx, req.OrganizationID, userID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		if role == "" {
+			uc.appLogger.Error(ErrNotOrgMember)
+			return nil, ErrNotOrgMember
+		}
+		if role != entity.OrgRoleOwner && role != entity.OrgRoleManager {
+			uc.appLogger.Error(ErrOrgForbidden)
+			return nil, ErrOrgForbidden
+		}
+	}
+
+	keys, err := uc.repo.GetAPIKeys(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if len(keys) >= maxAPIKeys {
+		err = fmt.Errorf("api keys limit reached")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	key := &entity.APIKey{
+		UserID: userID,
+		Name:   req.Name,
+		Scopes: req.Scopes,
+	}
+	if req.OrganizationID != 0 {
+		key.OrgID = &req.OrganizationID
+	}
+
+	if err = generateAPIKey(key); err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.repo.CreateAPIKey(ctx, key)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	uc.setRateLimit(key)
+	return key, nil
+}
+
+// GetAPIKeys usecase gets active keys of the client
+func (uc *APIKeyUseCase) GetAPIKeys(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	keys, err := uc.repo.GetAPIKeys(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	for _, key := range keys {
+		uc.setRateLimit(key)
+	}
+	return keys, nil
+}
+
+// RotateAPIKey usecase replaces the key with the new one keeping its name, scopes and organization,
+// the old key stops working at once
+func (uc *APIKeyUseCase) RotateAPIKey(ctx context.Context, userID, keyID int) (*entity.APIKey, error) {
+	key := &entity.APIKey{ID: keyID, UserID: userID}
+	if err := generateAPIKey(key); err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err := uc.repo.RotateAPIKey(ctx, key)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	uc.setRateLimit(key)
+	return key, nil
+}
+
+// RevokeAPIKey usecase revokes the key of the client
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, userID, keyID int) error {
+	err := uc.repo.RevokeAPIKey(ctx, userID, keyID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Authenticate usecase finds active key by the plain one presented in request,
+// key of the organization stops working once its owner leaves the organization
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, rawKey string) (*entity.APIKey, error) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(rawKey, entity.APIKeyPrefix), "_")
+	if !strings.HasPrefix(rawKey, entity.APIKeyPrefix) || !ok || id == "" || secret == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := uc.repo.GetAPIKeyByPrefix(ctx, entity.APIKeyPrefix+id)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.RevokedAt != nil || subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.KeyHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.OrgID != nil {
+		role, err := uc.orgs.GetMemberRole(ctx, *key.OrgID, key.UserID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		if role == "" {
+			return nil, ErrInvalidAPIKey
+		}
+	}
+	uc.setRateLimit(key)
+
+	if err = uc.repo.TouchAPIKey(ctx, key.ID); err != nil {
+		uc.appLogger.Error(err)
+	}
+	return key, nil
+}
+
+// setRateLimit sets requests per minute of the key, keys of organizations are allowed more
+func (uc *APIKeyUseCase) setRateLimit(key *entity.APIKey) {
+	key.RateLimit = uc.cfg.RateLimit
+	if key.OrgID != nil {
+		key.RateLimit = uc.cfg.OrgRateLimit
+	}
+}
+
+// generateAPIKey generates plain key of the form trk_<id>_<secret> and sets it
+// to the key with its public part and hash of the secret
+func generateAPIKey(key *entity.APIKey) error {
+	id, err := randomHex(6)
+	if err != nil {
+		return err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+
+	key.Prefix = entity.APIKeyPrefix + id
+	key.Key = key.Prefix + "_" + secret
+	key.KeyHash = hashSecret(secret)
+	return nil
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// apiKeyRepoStub keeps api keys in memory instead of the database
+type apiKeyRepoStub struct {
+	APIKeyRepo
+	keys []*entity.APIKey
+}
+
+func (r *apiKeyRepoStub) CreateAPIKey(ctx context.Context, key *entity.APIKey) error {
+	key.ID = len(r.keys) + 1
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *apiKeyRepoStub) GetAPIKeys(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	return nil, nil
+}
+
+func (r *apiKeyRepoStub) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			stored := *key
+			stored.RateLimit = 0
+			return &stored, nil
+		}
+	}
+	return nil, ErrInvalidAPIKey
+}
+
+func (r *apiKeyRepoStub) TouchAPIKey(ctx context.Context, keyID int) error {
+	return nil
+}
+
+// memberRoleStub returns roles of the members of the organization
+type memberRoleStub struct {
+	OrganizationRepo
+	roles map[int]string
+}
+
+func (o *memberRoleStub) GetMemberRole(ctx context.Context, orgID, userID int) (string, error) {
+	return o.roles[userID], nil
+}
+
+func TestAPIKeyUseCase_CreateAPIKey(t *testing.T) {
+	testLogger := logrus.New()
+	cfg := &config.APIKEY{RateLimit: 60, OrgRateLimit: 600}
+	orgs := &memberRoleStub{roles: map[int]string{
+		1: entity.OrgRoleOwner,
+		2: entity.OrgRoleManager,
+		3: entity.OrgRoleRequester,
+	}}
+
+	tests := []struct {
+		name          string
+		userID        int
+		orgID         int
+		wantRateLimit int
+		error         error
+	}{
+		{name: "personal key has the default limit", userID: 4, wantRateLimit: 60},
+		{name: "owner creates key of the organization", userID: 1, orgID: 5, wantRateLimit: 600},
+		{name: "manager creates key of the organization", userID: 2, orgID: 5, wantRateLimit: 600},
+		{name: "requester can't create key of the organization", userID: 3, orgID: 5, error: ErrOrgForbidden},
+		{name: "non-member can't create key of the organization", userID: 4, orgID: 5, error: ErrNotOrgMember},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &apiKeyRepoStub{}
+			uc := NewAPIKeyUseCase(repo, orgs, cfg, logger.New(testLogger))
+
+			key, err := uc.CreateAPIKey(context.Background(), tt.userID, &dto.APIKeyRequestBody{
+				Name:           "erp",
+				Scopes:         []string{entity.ScopeDeliveriesCreate},
+				OrganizationID: tt.orgID,
+			})
+			if tt.error != nil {
+				require.ErrorIs(t, err, tt.error)
+				require.Empty(t, repo.keys)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRateLimit, key.RateLimit)
+			if tt.orgID != 0 {
+				require.Equal(t, tt.orgID, *key.OrgID)
+			}
+
+			// Limit is defined by the server on every request
+			got, err := uc.Authenticate(context.Background(), key.Key)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRateLimit, got.RateLimit)
+		})
+	}
+}
+
+func TestAPIKeyUseCase_Authenticate_LeftOrganization(t *testing.T) {
+	testLogger := logrus.New()
+	orgs := &memberRoleStub{roles: map[int]string{1: entity.OrgRoleOwner}}
+	uc := NewAPIKeyUseCase(&apiKeyRepoStub{}, orgs, &config.APIKEY{RateLimit: 60, OrgRateLimit: 600}, logger.New(testLogger))
+
+	key, err := uc.CreateAPIKey(context.Background(), 1, &dto.APIKeyRequestBody{
+		Name:           "erp",
+		Scopes:         []string{entity.ScopeDeliveriesCreate},
+		OrganizationID: 5,
+	})
+	require.NoError(t, err)
+
+	delete(orgs.roles, 1)
+	_, err = uc.Authenticate(context.Background(), key.Key)
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Number of messages returned by history request without limit
+var defaultChatHistoryLimit = 50
+
+// ChatUseCase is a struct that provides all use cases
+// of the chat between client and courier of the delivery
+type ChatUseCase struct {
+	repo      ChatRepo
+	broker    ChatBroker
+	cfg       *config.CHAT
+	appLogger *logger.Logger
+}
+
+func NewChatUseCase(r ChatRepo, b ChatBroker, cfg *config.CHAT, l *logger.Logger) *ChatUseCase {
+	return &ChatUseCase{
+		repo:      r,
+		broker:    b,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// SendMessage usecase stores the participant's message
+// and delivers it to the other side in real time
+func (uc *ChatUseCase) SendMessage(ctx context.Context, senderID, deliveryID int, req *dto.ChatMessageRequestBody) (*entity.ChatMessage, error) {
+	channel, err := uc.repo.GetChatChannel(ctx, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if !isChatParticipant(channel, senderID) {
+		err = fmt.Errorf("user is not delivery participant")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if !uc.isChatOpen(channel) {
+		err = fmt.Errorf("chat is closed")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	msg := &entity.ChatMessage{
+		DeliveryID: deliveryID,
+		SenderID:   senderID,
+		Text:       req.Text,
+	}
+	err = uc.repo.CreateMessage(ctx, msg)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	// The message is already stored and will be fetched
+	// with history, so failed real time delivery is only logged
+	_ = uc.broker.Publish(ctx, deliveryID, &entity.ChatEvent{
+		Type:    entity.ChatEventMessage,
+		Message: msg,
+	})
+	return msg, nil
+}
+
+// GetMessages usecase gets page of the chat's history, newest messages first,
+// it is available to participants and staff reading any delivery after the chat is closed as well
+func (uc *ChatUseCase) GetMessages(ctx context.Context, userID, deliveryID int, readAny bool, query *dto.ChatHistoryQuery) ([]*entity.ChatMessage, error) {
+	err := uc.checkReadAccess(ctx, userID, deliveryID, readAny)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultChatHistoryLimit
+	}
+
+	messages, err := uc.repo.GetMessages(ctx, deliveryID, query.BeforeID, limit)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return messages, nil
+}
+
+// MarkRead usecase marks messages of the other participant up to
+// the given one as read and notifies them with read receipt
+func (uc *ChatUseCase) MarkRead(ctx context.Context, readerID, deliveryID int, req *dto.ChatReadRequestBody) error {
+	channel, err := uc.repo.GetChatChannel(ctx, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if !isChatParticipant(channel, readerID) {
+		err = fmt.Errorf("user is not delivery participant")
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	cnt, err := uc.repo.MarkRead(ctx, deliveryID, readerID, req.LastMessageID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if cnt > 0 {
+		_ = uc.broker.Publish(ctx, deliveryID, &entity.ChatEvent{
+			Type:     entity.ChatEventRead,
+			ReaderID: readerID,
+			ReadUpTo: req.LastMessageID,
+		})
+	}
+	return nil
+}
+
+// Subscribe usecase subscribes participant or staff reading any delivery to real time events of the chat
+func (uc *ChatUseCase) Subscribe(ctx context.Context, userID, deliveryID int, readAny bool) (<-chan *entity.ChatEvent, func(), error) {
+	err := uc.checkReadAccess(ctx, userID, deliveryID, readAny)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, nil, err
+	}
+
+	events, unsubscribe, err := uc.broker.Subscribe(ctx, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, nil, err
+	}
+	return events, unsubscribe, nil
+}
+
+// checkReadAccess checks that the user is participant of the delivery
+// or staff allowed to read any delivery, staff can't write to the chat
+func (uc *ChatUseCase) checkReadAccess(ctx context.Context, userID, deliveryID int, readAny bool) error {
+	channel, err := uc.repo.GetChatChannel(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if !readAny && !isChatParticipant(channel, userID) {
+		return fmt.Errorf("user is not delivery participant")
+	}
+	return nil
+}
+
+// isChatOpen checks that the chat is opened by courier's acceptance
+// and is not closed yet after completion of the delivery
+func (uc *ChatUseCase) isChatOpen(channel *entity.ChatChannel) bool {
+	switch channel.StatusID {
+	case 2: // active
+		return true
+	case 3: // completed
+		return channel.CompletedAt != nil && time.Since(*channel.CompletedAt) < uc.cfg.CloseAfter
+	default:
+		return false
+	}
+}
+
+// isChatParticipant checks that the user is client or assigned courier of the delivery
+func isChatParticipant(channel *entity.ChatChannel, userID int) bool {
+	if channel.CourierID == 0 {
+		return false
+	}
+	return userID == channel.ClientID || userID == channel.CourierID
+}
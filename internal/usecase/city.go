@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Errors of the city checks, they are shown to users as is
+var (
+	ErrCityNotServed        = errors.New("city is not served")
+	ErrTypeNotEnabledInCity = errors.New("delivery type is not available in this city")
+)
+
+// City of users signed up without choosing one
+var defaultCityID = 1
+
+// CityUseCase is a struct that provides all use cases of cities
+type CityUseCase struct {
+	repo      CityRepo
+	appLogger *logger.Logger
+}
+
+func NewCityUseCase(r CityRepo, l *logger.Logger) *CityUseCase {
+	return &CityUseCase{repo: r, appLogger: l}
+}
+
+// CreateCity usecase creates new city
+func (uc *CityUseCase) CreateCity(ctx context.Context, req *dto.CityRequestBody) (*entity.City, error) {
+	city, err := cityFromRequest(req)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.repo.CreateCity(ctx, city)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return city, nil
+}
+
+// UpdateCity usecase updates settings of the city
+func (uc *CityUseCase) UpdateCity(ctx context.Context, cityID int, req *dto.CityRequestBody) error {
+	city, err := cityFromRequest(req)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	city.ID = cityID
+
+	err = uc.repo.UpdateCity(ctx, city)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetCities usecase gets all cities
+func (uc *CityUseCase) GetCities(ctx context.Context) ([]*entity.City, error) {
+	cities, err := uc.repo.GetCities(ctx)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return cities, nil
+}
+
+// cityFromRequest converts request body to the city validating its time zone
+func cityFromRequest(req *dto.CityRequestBody) (*entity.City, error) {
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone")
+	}
+
+	return &entity.City{
+		Name:             req.Name,
+		GeocoderID:       req.GeocoderID,
+		Timezone:         req.Timezone,
+		Currency:         req.Currency,
+		TariffMultiplier: req.TariffMultiplier,
+		EnabledTypes:     req.EnabledTypes,
+		IsActive:         req.IsActive,
+	}, nil
+}
+
+// servingCity gets the city and checks that the delivery type is served in it
+func servingCity(ctx context.Context, repo CityRepo, cityID, typeID int) (*entity.City, error) {
+	city, err := repo.GetCityByID(ctx, cityID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !city.IsActive {
+		return nil, ErrCityNotServed
+	}
+	if !city.AllowsType(typeID) {
+		return nil, ErrTypeNotEnabledInCity
+	}
+	return city, nil
+}
@@ -5,22 +5,28 @@ import (
 	"fmt"
 	"github.com/dacore-x/truckly/internal/dto"
 	"github.com/dacore-x/truckly/pkg/logger"
-	"math"
+	"strings"
 
 	"github.com/dacore-x/truckly/internal/entity"
 	"sync"
-	"time"
 )
 
-// Delivery Search Radius for courier's request
-var searchR = 2.
+// Default delivery search radius for courier's request, in m
+var defaultSearchRadius = 2000.
 
 // DeliveryUseCase is a struct that provides all use cases of the delivery entity
 type DeliveryUseCase struct {
-	repo      DeliveryRepo
-	geo       GeoWebAPI
-	service   PriceEstimatorService
-	appLogger *logger.Logger
+	repo       DeliveryRepo
+	geo        GeoWebAPI
+	service    PriceEstimatorService
+	dispatcher DeliveryDispatcher
+	zones      CoverageChecker
+	cities     CityRepo
+	places     SavedPlaceRepo
+	orgs       OrganizationRepo
+	timeline   TimelineRepo
+	planner    *RoutePlanner
+	appLogger  *logger.Logger
 }
 
 // ObjectResponse is an internal struct for syncing results of goroutines
@@ -35,12 +41,59 @@ type DistanceResponse struct {
 	Error    error
 }
 
-func NewDeliveryUseCase(r DeliveryRepo, g GeoWebAPI, s PriceEstimatorService, l *logger.Logger) *DeliveryUseCase {
-	return &DeliveryUseCase{repo: r, geo: g, service: s, appLogger: l}
+// NewDeliveryUseCase creates delivery usecases, dispatcher is optional
+// and new deliveries go straight to the open marketplace if it is nil
+func NewDeliveryUseCase(r DeliveryRepo, g GeoWebAPI, s PriceEstimatorService, d DeliveryDispatcher, z CoverageChecker, c CityRepo, sp SavedPlaceRepo, o OrganizationRepo, t TimelineRepo, l *logger.Logger) *DeliveryUseCase {
+	return &DeliveryUseCase{repo: r, geo: g, service: s, dispatcher: d, zones: z, cities: c, places: sp, orgs: o, timeline: t, planner: NewRoutePlanner(g, l), appLogger: l}
 }
 
-// CreateDelivery creates new user's delivery
+// CreateDelivery creates new user's delivery, the delivery created on behalf
+// of the organization requires the user to be its member
 func (uc *DeliveryUseCase) CreateDelivery(ctx context.Context, delivery *entity.Delivery) error {
+	if delivery.OrgID != nil {
+		role, err := uc.orgs.GetMemberRole(ctx, *delivery.OrgID, delivery.ClientID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return err
+		}
+		if role == "" {
+			uc.appLogger.Error(ErrNotOrgMember)
+			return ErrNotOrgMember
+		}
+	}
+
+	city, err := servingCity(ctx, uc.cities, delivery.CityID, delivery.TypeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = uc.resolvePlaces(ctx, delivery)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	from := &dto.PointRequest{Lat: delivery.Geo.FromLatitude, Lon: delivery.Geo.FromLongitude}
+	to := &dto.PointRequest{Lat: delivery.Geo.ToLatitude, Lon: delivery.Geo.ToLongitude}
+	zone, err := uc.zones.CheckCoverage(ctx, delivery.TypeID, from, to)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	capacity, err := uc.repo.GetVehicleCapacity(ctx, delivery.TypeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if delivery.CargoWeight > capacity.MaxWeight || delivery.CargoVolume > capacity.MaxVolume {
+		err = fmt.Errorf("cargo exceeds vehicle capacity")
+		uc.appLogger.Error(err)
+		return err
+	}
+
 	fromObj := make(chan ObjectResponse, 2)
 	toObj := make(chan ObjectResponse, 2)
 	distCh := make(chan DistanceResponse, 2)
@@ -52,12 +105,23 @@ func (uc *DeliveryUseCase) CreateDelivery(ctx context.Context, delivery *entity.
 	var wg sync.WaitGroup
 	wg.Add(3)
 
+	// Objects of the chosen places are already known and aren't geocoded again
 	go func() {
+		if delivery.Geo.FromObject != "" {
+			fromObj <- ObjectResponse{Object: delivery.Geo.FromObject}
+			wg.Done()
+			return
+		}
 		fromObject, err := uc.geo.GetObjectByCoords(delivery.Geo.FromLatitude, delivery.Geo.FromLongitude)
 		fromObj <- ObjectResponse{Object: fromObject, Error: err}
 		wg.Done()
 	}()
 	go func() {
+		if delivery.Geo.ToObject != "" {
+			toObj <- ObjectResponse{Object: delivery.Geo.ToObject}
+			wg.Done()
+			return
+		}
 		toObject, err := uc.geo.GetObjectByCoords(delivery.Geo.ToLatitude, delivery.Geo.ToLongitude)
 		toObj <- ObjectResponse{Object: toObject, Error: err}
 		wg.Done()
@@ -94,7 +158,7 @@ func (uc *DeliveryUseCase) CreateDelivery(ctx context.Context, delivery *entity.
 	body := &dto.EstimatePriceInternalRequestBody{
 		TypeID:    delivery.TypeID,
 		HasLoader: delivery.HasLoader,
-		Time:      time.Now(),
+		Time:      city.Now(),
 		Distance:  distResponse.Distance, // in m
 	}
 	price, err := uc.service.EstimateDeliveryPrice(body)
@@ -107,47 +171,209 @@ func (uc *DeliveryUseCase) CreateDelivery(ctx context.Context, delivery *entity.
 	delivery.Geo.FromObject = fromObjResponse.Object
 	delivery.Geo.ToObject = toObjResponse.Object
 	delivery.Geo.Distance = distResponse.Distance
-	delivery.Price = price
+	delivery.Price = price * city.TariffMultiplier
+
+	// Delivery is tagged with the pickup zone and priced by its tariff
+	if zone != nil {
+		delivery.ZoneID = &zone.ID
+		delivery.Price *= zone.TariffMultiplier
+	}
+
+	delivery.PIN, err = generatePIN()
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
 
 	err = uc.repo.CreateDelivery(ctx, delivery)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return err
 	}
+	addEvent(ctx, uc.timeline, uc.appLogger, delivery.ID, delivery.ClientID, entity.EventCreated, map[string]float64{"price": delivery.Price})
+
+	if uc.dispatcher != nil {
+		uc.dispatcher.DispatchDelivery(ctx, delivery)
+	}
 	return nil
 }
 
-func (uc *DeliveryUseCase) GetDeliveryByID(ctx context.Context, clientID, deliveryID int) (*dto.DeliveryFullInfoResponse, error) {
-	delivery, err := uc.repo.GetDeliveryByID(ctx, clientID, deliveryID)
+// resolvePlaces sets points and objects of the places chosen
+// from the client's address book or from suggestions,
+// instructions of saved places fill in the unset ones of the stops
+func (uc *DeliveryUseCase) resolvePlaces(ctx context.Context, delivery *entity.Delivery) error {
+	geo := delivery.Geo
+	if geo.FromSavedPlaceID != 0 {
+		place, err := uc.savedPlace(ctx, delivery, geo.FromSavedPlaceID)
+		if err != nil {
+			return err
+		}
+		geo.FromLatitude, geo.FromLongitude = place.Latitude, place.Longitude
+		geo.FromObject = place.Object
+		delivery.PickupContact = withPlaceInstructions(delivery.PickupContact, place)
+	} else if geo.FromPlaceID != "" {
+		place, err := uc.geo.GetObjectByID(geo.FromPlaceID)
+		if err != nil {
+			return fmt.Errorf("error getting from place")
+		}
+		geo.FromLatitude, geo.FromLongitude = place.Point.Lat, place.Point.Lon
+		geo.FromObject = place.FullAddress
+	}
+
+	if geo.ToSavedPlaceID != 0 {
+		place, err := uc.savedPlace(ctx, delivery, geo.ToSavedPlaceID)
+		if err != nil {
+			return err
+		}
+		geo.ToLatitude, geo.ToLongitude = place.Latitude, place.Longitude
+		geo.ToObject = place.Object
+		delivery.DropoffContact = withPlaceInstructions(delivery.DropoffContact, place)
+	} else if geo.ToPlaceID != "" {
+		place, err := uc.geo.GetObjectByID(geo.ToPlaceID)
+		if err != nil {
+			return fmt.Errorf("error getting to place")
+		}
+		geo.ToLatitude, geo.ToLongitude = place.Point.Lat, place.Point.Lon
+		geo.ToObject = place.FullAddress
+	}
+	return nil
+}
+
+// savedPlace gets the place from the organization's address book if the delivery
+// is created on behalf of the organization and from the client's one otherwise
+func (uc *DeliveryUseCase) savedPlace(ctx context.Context, delivery *entity.Delivery, placeID int) (*entity.SavedPlace, error) {
+	if delivery.OrgID != nil {
+		return uc.orgs.GetOrganizationPlaceByID(ctx, *delivery.OrgID, placeID)
+	}
+	return uc.places.GetPlaceByID(ctx, delivery.ClientID, placeID)
+}
+
+// withPlaceInstructions fills unset instructions of the stop's contact with the saved place's ones
+func withPlaceInstructions(contact *entity.StopContact, place *entity.SavedPlace) *entity.StopContact {
+	if contact == nil {
+		contact = &entity.StopContact{}
+	}
+	if contact.Entrance == "" {
+		contact.Entrance = place.Entrance
+	}
+	if contact.Floor == "" {
+		contact.Floor = place.Floor
+	}
+	if contact.Notes == "" {
+		contact.Notes = place.Notes
+	}
+	return contact
+}
+
+// GetDeliveryByID gets full info of the delivery, contacts of the stops
+// are masked for everyone except the client and the courier performing it,
+// staff reading any delivery sees it masked as well
+func (uc *DeliveryUseCase) GetDeliveryByID(ctx context.Context, clientID, deliveryID int, readAny bool) (*dto.DeliveryFullInfoResponse, error) {
+	delivery, err := uc.repo.GetDeliveryByID(ctx, clientID, deliveryID, readAny)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return nil, err
 	}
+
+	// Courier sees contacts only while the delivery is active (status 2)
+	isPerformer := delivery.CourierID == clientID && delivery.StatusID == 2
+	if delivery.ClientID != clientID && !isPerformer {
+		maskContact(delivery.PickupContact)
+		maskContact(delivery.DropoffContact)
+	}
+	if delivery.ClientID != clientID {
+		delivery.PIN = ""
+	}
 	return delivery, nil
 }
 
+// maskContact hides personal data and access instructions of the contact
+// leaving the initial of the name and the last digits of the phone
+func maskContact(contact *dto.StopContactResponse) {
+	if contact == nil {
+		return
+	}
+
+	if name := []rune(contact.Name); len(name) > 0 {
+		contact.Name = string(name[0]) + "."
+	}
+	if phone := []rune(contact.PhoneNumber); len(phone) > 2 {
+		contact.PhoneNumber = strings.Repeat("*", len(phone)-2) + string(phone[len(phone)-2:])
+	}
+	contact.Entrance = ""
+	contact.Floor = ""
+	contact.Intercom = ""
+	contact.Notes = ""
+	contact.IsMasked = true
+}
+
+// AcceptDelivery assigns delivery to the courier if courier's vehicle
+// has enough free capacity for the cargo and the limit of simultaneous
+// deliveries for the vehicle type is not reached, the limits are checked
+// in the same transaction that assigns the delivery
 func (uc *DeliveryUseCase) AcceptDelivery(ctx context.Context, courierID, deliveryID int) error {
-	amount, err := uc.repo.GetActiveDeliveryAmount(ctx, courierID)
+	err := uc.repo.AcceptDelivery(ctx, courierID, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, courierID, entity.EventAccepted, nil)
+	return nil
+}
+
+// PickUpDelivery marks that courier has picked up the cargo
+// so that only dropoff of the delivery is left in courier's route
+func (uc *DeliveryUseCase) PickUpDelivery(ctx context.Context, courierID, deliveryID int) error {
+	ok, err := uc.repo.IsDeliveryPerformer(ctx, courierID, deliveryID)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return err
 	}
 
-	if amount != 0 {
-		err = fmt.Errorf("multiple delivery accept")
+	if !ok {
+		err = fmt.Errorf("user is not delivery performer")
 		uc.appLogger.Error(err)
 		return err
 	}
 
-	err = uc.repo.AcceptDelivery(ctx, courierID, deliveryID)
+	err = uc.repo.PickUpDelivery(ctx, deliveryID)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return err
 	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, courierID, entity.EventPickedUp, nil)
 	return nil
 }
 
+// GetActiveRoute orders pickups and dropoffs of all courier's active deliveries
+// into a sequence of stops minimizing travel distance within time windows
+func (uc *DeliveryUseCase) GetActiveRoute(ctx context.Context, courierID int) (*dto.RouteResponse, error) {
+	deliveries, err := uc.repo.GetActiveDeliveriesByCourierID(ctx, courierID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	presence, err := uc.repo.GetCourierPresence(ctx, courierID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	return uc.planner.Plan(presence, deliveries), nil
+}
+
+// ChangeDeliveryStatus changes status of the courier's delivery,
+// delivery can be completed only with proof of delivery
 func (uc *DeliveryUseCase) ChangeDeliveryStatus(ctx context.Context, courierID, deliveryID, statusID int) error {
+	if statusID == 3 {
+		err := fmt.Errorf("delivery must be completed with proof of delivery")
+		uc.appLogger.Error(err)
+		return err
+	}
+
 	ok, err := uc.repo.IsDeliveryPerformer(ctx, courierID, deliveryID)
 	if err != nil {
 		uc.appLogger.Error(err)
@@ -165,6 +391,8 @@ func (uc *DeliveryUseCase) ChangeDeliveryStatus(ctx context.Context, courierID,
 		uc.appLogger.Error(err)
 		return err
 	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, courierID, entity.EventStatusChanged, map[string]int{"status_id": statusID})
 	return nil
 }
 
@@ -181,37 +409,37 @@ func (uc *DeliveryUseCase) CancelDelivery(ctx context.Context, clientID, deliver
 		return err
 	}
 
-	err = uc.repo.ChangeDeliveryStatus(ctx, 4, deliveryID)
+	err = uc.repo.CancelDelivery(ctx, clientID, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, clientID, entity.EventCancelled, nil)
+	return nil
+}
+
+// CancelAnyDelivery cancels the delivery on behalf of the staff member,
+// ownership is not checked since the caller is permitted to cancel any delivery
+func (uc *DeliveryUseCase) CancelAnyDelivery(ctx context.Context, staffID, deliveryID int) error {
+	err := uc.repo.CancelDelivery(ctx, staffID, deliveryID)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return err
 	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, staffID, entity.EventCancelled, nil)
 	return nil
 }
 
+// GetDeliveriesByGeolocation searches new deliveries with pickup point within
+// the great-circle radius of courier's location, the nearest ones go first
 func (uc *DeliveryUseCase) GetDeliveriesByGeolocation(ctx context.Context, query *dto.DeliveryListGeolocationQuery) ([]*dto.DeliveryBriefResponse, error) {
-	// 1 км на входящей широте = посчитанное количество градусов
-	oneKM := 1 / (111.11 * math.Cos(query.Latitude)) // degree
-
-	searchD := searchR * oneKM // degree
-
-	//		Область поиска заказов для курьера
-	//
-	//		Описание: в качестве области поиска взят квадрат с гранями,
-	//	    удалёнными от точки поиска (местоположение курьера) на searchD
-	//	    (в градусах)
-	//
-	//          +-------------------------------------+
-	//          |            lat+searchD              |
-	//          |	              |                   |
-	//          |  lon-searchD    |  lon+searchD      |
-	//          |	       <------*------>	          |
-	//          |	              |                   |
-	//          |                 |                   |
-	//			|           lat-searchD               |
-	//          +-------------------------------------+
-
-	results, err := uc.repo.GetDeliveriesByGeolocation(ctx, query, searchD)
+	if query.Radius == 0 {
+		query.Radius = defaultSearchRadius
+	}
+
+	results, err := uc.repo.GetDeliveriesByGeolocation(ctx, query)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return nil, err
@@ -236,3 +464,21 @@ func (uc *DeliveryUseCase) GetDeliveriesByCourierID(ctx context.Context, courier
 	}
 	return results, nil
 }
+
+// GetTimeline gets events of the delivery in chronological order
+// for its client, courier or staff reading any delivery
+func (uc *DeliveryUseCase) GetTimeline(ctx context.Context, userID, deliveryID int, readAny bool) ([]*entity.DeliveryEvent, error) {
+	// Delivery is returned only to the users who have access to it
+	_, err := uc.repo.GetDeliveryByID(ctx, userID, deliveryID, readAny)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	events, err := uc.timeline.GetEvents(ctx, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return events, nil
+}
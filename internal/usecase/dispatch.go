@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/geohelper"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Weights of the criteria used for ranking couriers
+var (
+	distanceWeight = 0.5
+	vehicleWeight  = 0.2
+	ratingWeight   = 0.2
+	idleWeight     = 0.1
+)
+
+// Courier idle time after which idle criterion reaches its maximum
+var maxIdleTime = 30 * time.Minute
+
+// Interval of checking whether courier has responded to the offer
+var offerPollInterval = time.Second
+
+// Dispatches are checked for being left by a stopped dispatcher with the interval,
+// dispatch is stale if nothing has happened to it longer than offer timeout and the margin
+var (
+	staleDispatchInterval = time.Minute
+	staleDispatchMargin   = time.Minute
+)
+
+// DispatchUseCase is a struct that provides all use cases
+// of the automatic dispatch of deliveries to couriers
+type DispatchUseCase struct {
+	repo          DispatchRepo
+	geo           GeoWebAPI
+	offerTimeout  time.Duration
+	maxCandidates int
+	searchRadius  float64 // in m
+	timeline      TimelineRepo
+	notifier      Notifier
+	appLogger     *logger.Logger
+}
+
+func NewDispatchUseCase(r DispatchRepo, g GeoWebAPI, t TimelineRepo, n Notifier, cfg *config.DISPATCH, l *logger.Logger) *DispatchUseCase {
+	return &DispatchUseCase{
+		repo:          r,
+		geo:           g,
+		timeline:      t,
+		notifier:      n,
+		offerTimeout:  cfg.OfferTimeout,
+		maxCandidates: cfg.MaxCandidates,
+		searchRadius:  cfg.SearchRadius,
+		appLogger:     l,
+	}
+}
+
+// DispatchDelivery hides new delivery from the open marketplace
+// and starts offering it to the best couriers in background
+func (uc *DispatchUseCase) DispatchDelivery(ctx context.Context, delivery *entity.Delivery) {
+	err := uc.repo.SetDeliveryDispatching(ctx, delivery.ID, true)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return
+	}
+
+	go uc.dispatch(delivery)
+}
+
+// Run releases deliveries left in dispatch by a stopped dispatcher on start
+// and then periodically until the context is done
+func (uc *DispatchUseCase) Run(ctx context.Context) {
+	uc.releaseStale(ctx)
+
+	ticker := time.NewTicker(staleDispatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.releaseStale(ctx)
+		}
+	}
+}
+
+// releaseStale passes stale dispatches to the open marketplace
+func (uc *DispatchUseCase) releaseStale(ctx context.Context) {
+	n, err := uc.repo.ReleaseStaleDispatches(ctx, uc.offerTimeout+staleDispatchMargin)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return
+	}
+	if n != 0 {
+		uc.appLogger.Infof("dispatch: %v stale deliveries are passed to the open marketplace", n)
+	}
+}
+
+// dispatch offers the delivery to ranked candidates one by one
+// and falls back to the open marketplace if nobody accepted it
+func (uc *DispatchUseCase) dispatch(delivery *entity.Delivery) {
+	ctx := context.Background()
+
+	candidates, err := uc.repo.GetDispatchCandidates(ctx, delivery.ID)
+	if err != nil {
+		uc.appLogger.Error(err)
+	}
+	candidates = uc.rankCandidates(delivery, candidates)
+
+	for _, candidate := range candidates {
+		// Stop dispatching if delivery has been cancelled meanwhile
+		statusID, err := uc.repo.GetDeliveryStatus(ctx, delivery.ID)
+		if err != nil || statusID != 1 {
+			break
+		}
+
+		accepted, err := uc.offer(ctx, delivery, candidate)
+		if err != nil {
+			uc.appLogger.Error(err)
+			continue
+		}
+		if accepted {
+			return
+		}
+	}
+
+	err = uc.repo.SetDeliveryDispatching(ctx, delivery.ID, false)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return
+	}
+	uc.appLogger.Infof("dispatch: delivery %v is passed to the open marketplace", delivery.ID)
+}
+
+// offer sends the offer to the courier and waits for the response until timeout
+func (uc *DispatchUseCase) offer(ctx context.Context, delivery *entity.Delivery, candidate *entity.CourierCandidate) (bool, error) {
+	offer := &entity.Offer{
+		DeliveryID: delivery.ID,
+		CourierID:  candidate.CourierID,
+		Score:      candidate.Score,
+		Distance:   candidate.Distance,
+		ExpiresAt:  time.Now().Add(uc.offerTimeout),
+	}
+	err := uc.repo.CreateOffer(ctx, offer)
+	if err != nil {
+		return false, err
+	}
+	uc.appLogger.Infof("dispatch: offer %v of delivery %v is sent to courier %v (score %.3f, distance %.0f m, eta %.0f s)",
+		offer.ID, delivery.ID, candidate.CourierID, candidate.Score, candidate.Distance, candidate.Duration)
+	uc.notifier.NotifyUser(ctx, candidate.CourierID, delivery.ID, entity.NotifyNewOrderNearby)
+
+	ticker := time.NewTicker(offerPollInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(uc.offerTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			status, err := uc.repo.GetOfferStatus(ctx, offer.ID)
+			if err != nil {
+				return false, err
+			}
+			if status != entity.OfferPending {
+				uc.appLogger.Infof("dispatch: offer %v is %v by courier %v", offer.ID, status, candidate.CourierID)
+				return status == entity.OfferAccepted, nil
+			}
+		case <-timeout.C:
+			expired, err := uc.repo.ExpireOffer(ctx, offer.ID)
+			if err != nil {
+				return false, err
+			}
+			if expired {
+				uc.appLogger.Infof("dispatch: offer %v is expired for courier %v", offer.ID, candidate.CourierID)
+				return false, nil
+			}
+
+			// Courier has responded right before the timeout
+			status, err := uc.repo.GetOfferStatus(ctx, offer.ID)
+			if err != nil {
+				return false, err
+			}
+			uc.appLogger.Infof("dispatch: offer %v is %v by courier %v", offer.ID, status, candidate.CourierID)
+			return status == entity.OfferAccepted, nil
+		}
+	}
+}
+
+// rankCandidates drops couriers outside the search radius, scores the rest
+// by road distance to pickup, vehicle fit, rating and idle time and returns
+// the best ones in descending order of the score
+func (uc *DispatchUseCase) rankCandidates(delivery *entity.Delivery, candidates []*entity.CourierCandidate) []*entity.CourierCandidate {
+	ranked := make([]*entity.CourierCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		c.Distance = geohelper.Haversine(c.Latitude, c.Longitude, delivery.Geo.FromLatitude, delivery.Geo.FromLongitude)
+		if c.Distance > uc.searchRadius {
+			continue
+		}
+		ranked = append(ranked, c)
+	}
+
+	uc.setRoadDistances(delivery, ranked)
+
+	for _, c := range ranked {
+		distanceScore := math.Max(1-c.Distance/uc.searchRadius, 0)
+
+		// Vehicle of the same type fits best, larger ones are less preferable
+		vehicleScore := 1 / float64(1+c.TypeID-delivery.TypeID)
+
+		ratingScore := c.Rating / 5
+
+		idleScore := math.Min(time.Since(c.IdleSince).Seconds()/maxIdleTime.Seconds(), 1)
+
+		c.Score = distanceWeight*distanceScore + vehicleWeight*vehicleScore + ratingWeight*ratingScore + idleWeight*idleScore
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+
+	if len(ranked) > uc.maxCandidates {
+		ranked = ranked[:uc.maxCandidates]
+	}
+	return ranked
+}
+
+// setRoadDistances replaces straight-line distances of candidates with road distances
+// and durations to pickup calculated by a single distance matrix request
+func (uc *DispatchUseCase) setRoadDistances(delivery *entity.Delivery, candidates []*entity.CourierCandidate) {
+	if len(candidates) == 0 {
+		return
+	}
+
+	sources := make([]dto.PointRequest, 0, len(candidates))
+	for _, c := range candidates {
+		sources = append(sources, dto.PointRequest{Lat: c.Latitude, Lon: c.Longitude})
+	}
+	targets := []dto.PointRequest{{Lat: delivery.Geo.FromLatitude, Lon: delivery.Geo.FromLongitude}}
+
+	matrix, err := uc.geo.GetDistanceMatrix(sources, targets)
+	if err != nil {
+		uc.appLogger.Warnf("dispatch: using straight-line distances: %v", err)
+		return
+	}
+
+	for i, c := range candidates {
+		c.Distance = matrix.Distances[i][0]
+		c.Duration = matrix.Durations[i][0]
+	}
+}
+
+// SetCourierPresence usecase updates courier's online status and location
+func (uc *DispatchUseCase) SetCourierPresence(ctx context.Context, courierID int, req *dto.CourierPresenceRequestBody) error {
+	err := uc.repo.SetCourierPresence(ctx, courierID, req)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetPendingOffers usecase gets courier's offers waiting for response
+func (uc *DispatchUseCase) GetPendingOffers(ctx context.Context, courierID int) ([]*dto.OfferResponse, error) {
+	offers, err := uc.repo.GetPendingOffersByCourierID(ctx, courierID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return offers, nil
+}
+
+// AcceptOffer usecase accepts courier's offer and assigns delivery to the courier
+func (uc *DispatchUseCase) AcceptOffer(ctx context.Context, courierID, offerID int) error {
+	deliveryID, err := uc.repo.AcceptOffer(ctx, courierID, offerID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, courierID, entity.EventAccepted, map[string]int{"offer_id": offerID})
+	return nil
+}
+
+// DeclineOffer usecase declines courier's offer so that it is passed to the next courier
+func (uc *DispatchUseCase) DeclineOffer(ctx context.Context, courierID, offerID int) error {
+	err := uc.repo.DeclineOffer(ctx, courierID, offerID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
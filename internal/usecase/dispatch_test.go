@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// geoStub returns the distance matrix or the error instead of requesting 2GIS
+type geoStub struct {
+	GeoWebAPI
+	matrix *dto.RouteMatrix
+	err    error
+}
+
+func (g *geoStub) GetDistanceMatrix(sources, targets []dto.PointRequest) (*dto.RouteMatrix, error) {
+	return g.matrix, g.err
+}
+
+func TestDispatchUseCase_rankCandidates(t *testing.T) {
+	// Pickup point, 0.009 degree of latitude is about 1 km
+	delivery := &entity.Delivery{
+		TypeID: 1,
+		Geo:    &entity.Geo{FromLatitude: 55.75, FromLongitude: 37.6},
+	}
+	idleSince := time.Now().Add(-time.Hour)
+	candidate := func(id, typeID int, lat, rating float64) *entity.CourierCandidate {
+		return &entity.CourierCandidate{
+			CourierID: id,
+			TypeID:    typeID,
+			Latitude:  lat,
+			Longitude: 37.6,
+			Rating:    rating,
+			IdleSince: idleSince,
+		}
+	}
+	noMatrix := &geoStub{err: errors.New("geo is unavailable")}
+
+	tests := []struct {
+		name          string
+		geo           GeoWebAPI
+		candidates    []*entity.CourierCandidate
+		want          []int // ids of couriers in order of ranking
+		wantDistances []float64
+	}{
+		{
+			name:       "no candidates",
+			geo:        noMatrix,
+			candidates: []*entity.CourierCandidate{},
+			want:       []int{},
+		},
+		{
+			name: "couriers outside search radius are dropped",
+			geo:  noMatrix,
+			candidates: []*entity.CourierCandidate{
+				candidate(1, 1, 55.85, 5),
+				candidate(2, 1, 55.759, 4),
+			},
+			want: []int{2},
+		},
+		{
+			name: "closer courier goes first",
+			geo:  noMatrix,
+			candidates: []*entity.CourierCandidate{
+				candidate(1, 1, 55.777, 4),
+				candidate(2, 1, 55.759, 4),
+			},
+			want: []int{2, 1},
+		},
+		{
+			name: "vehicle of the same type is preferred",
+			geo:  noMatrix,
+			candidates: []*entity.CourierCandidate{
+				candidate(1, 3, 55.759, 4),
+				candidate(2, 1, 55.759, 4),
+			},
+			want: []int{2, 1},
+		},
+		{
+			name: "higher rating is preferred",
+			geo:  noMatrix,
+			candidates: []*entity.CourierCandidate{
+				candidate(1, 1, 55.759, 3),
+				candidate(2, 1, 55.759, 5),
+			},
+			want: []int{2, 1},
+		},
+		{
+			name: "road distances replace straight-line ones",
+			geo: &geoStub{matrix: &dto.RouteMatrix{
+				Distances: [][]float64{{4000}, {1500}},
+				Durations: [][]float64{{600}, {200}},
+			}},
+			candidates: []*entity.CourierCandidate{
+				candidate(1, 1, 55.759, 4),
+				candidate(2, 1, 55.768, 4),
+			},
+			want:          []int{2, 1},
+			wantDistances: []float64{1500, 4000},
+		},
+		{
+			name: "only best candidates are kept",
+			geo:  noMatrix,
+			candidates: []*entity.CourierCandidate{
+				candidate(1, 1, 55.786, 4),
+				candidate(2, 1, 55.759, 4),
+				candidate(3, 1, 55.768, 4),
+			},
+			want: []int{2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &DispatchUseCase{
+				geo:           tt.geo,
+				maxCandidates: 2,
+				searchRadius:  5000,
+				appLogger:     logger.New(logrus.New()),
+			}
+
+			ranked := uc.rankCandidates(delivery, tt.candidates)
+
+			got := make([]int, 0, len(ranked))
+			distances := make([]float64, 0, len(ranked))
+			for _, c := range ranked {
+				got = append(got, c.CourierID)
+				distances = append(distances, c.Distance)
+			}
+			require.Equal(t, tt.want, got)
+			if tt.wantDistances != nil {
+				require.Equal(t, tt.wantDistances, distances)
+			}
+		})
+	}
+}
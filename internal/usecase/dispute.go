@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ErrBanForbidden is returned when the resolver's roles don't allow banning users,
+// it is shown to users as is
+var ErrBanForbidden = errors.New("permission users:ban is required to ban the user")
+
+// Number of files the opener may attach to the dispute
+var maxDisputeAttachments = 5
+
+// DisputeUseCase is a struct that provides all use cases of disputes on deliveries
+type DisputeUseCase struct {
+	repo       DisputeRepo
+	deliveries DeliveryRepo
+	roles      RoleRepo
+	timeline   TimelineRepo
+	store      BlobStore
+	cfg        *config.PROOF // attachments are limited the same way as proof files
+	appLogger  *logger.Logger
+}
+
+func NewDisputeUseCase(r DisputeRepo, d DeliveryRepo, ro RoleRepo, t TimelineRepo, s BlobStore, cfg *config.PROOF, l *logger.Logger) *DisputeUseCase {
+	return &DisputeUseCase{
+		repo:       r,
+		deliveries: d,
+		roles:      ro,
+		timeline:   t,
+		store:      s,
+		cfg:        cfg,
+		appLogger:  l,
+	}
+}
+
+// OpenDispute usecase opens ticket of the client or courier about a problem with the delivery
+func (uc *DisputeUseCase) OpenDispute(ctx context.Context, userID, deliveryID int, req *dto.DisputeRequestBody) (*entity.Dispute, error) {
+	delivery, err := uc.deliveries.GetDeliveryByID(ctx, userID, deliveryID, false)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if userID != delivery.ClientID && userID != delivery.CourierID {
+		err = fmt.Errorf("user is not delivery participant")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if delivery.CourierID == 0 {
+		err = fmt.Errorf("delivery is not accepted yet")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	dispute := &entity.Dispute{
+		DeliveryID:  deliveryID,
+		OpenerID:    userID,
+		Category:    req.Category,
+		Description: req.Description,
+		Attachments: []*entity.DisputeAttachment{},
+	}
+	err = uc.repo.CreateDispute(ctx, dispute)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, userID, entity.EventDisputeOpened, map[string]interface{}{
+		"dispute_id": dispute.ID,
+		"category":   dispute.Category,
+	})
+	return dispute, nil
+}
+
+// UploadAttachment usecase stores photo attached by the opener to unresolved dispute
+func (uc *DisputeUseCase) UploadAttachment(ctx context.Context, userID, disputeID int, r io.Reader) (*entity.DisputeAttachment, error) {
+	dispute, err := uc.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if dispute.OpenerID != userID {
+		err = fmt.Errorf("user is not dispute opener")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if dispute.IsClosed() {
+		err = fmt.Errorf("dispute is already closed")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if len(dispute.Attachments) >= maxDisputeAttachments {
+		err = fmt.Errorf("dispute can't have more than %d attachments", maxDisputeAttachments)
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, uc.cfg.MaxFileSize+1))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if int64(len(data)) > uc.cfg.MaxFileSize {
+		err = fmt.Errorf("file is too large")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	ext, ok := proofFileTypes[http.DetectContentType(data)]
+	if !ok {
+		err = fmt.Errorf("file must be jpeg or png image")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	key := fmt.Sprintf("disputes/%d/%d%s", disputeID, time.Now().UnixNano(), ext)
+	err = uc.store.Put(ctx, key, bytes.NewReader(data))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	attachment := &entity.DisputeAttachment{DisputeID: disputeID, FileKey: key}
+	err = uc.repo.AddAttachment(ctx, attachment)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, dispute.DeliveryID, userID, entity.EventDisputeAttachment, map[string]interface{}{
+		"dispute_id":    disputeID,
+		"attachment_id": attachment.ID,
+	})
+	return attachment, nil
+}
+
+// GetAttachment usecase opens file attached to the dispute, caller must close the file
+func (uc *DisputeUseCase) GetAttachment(ctx context.Context, userID, disputeID, attachmentID int, readAny bool) (io.ReadCloser, error) {
+	dispute, err := uc.GetDispute(ctx, userID, disputeID, readAny)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	for _, attachment := range dispute.Attachments {
+		if attachment.ID != attachmentID {
+			continue
+		}
+
+		f, err := uc.store.Get(ctx, attachment.FileKey)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		return f, nil
+	}
+
+	err = fmt.Errorf("attachment is not found")
+	uc.appLogger.Error(err)
+	return nil, err
+}
+
+// GetDispute usecase gets the dispute for participants of its delivery
+// and staff reading any delivery
+func (uc *DisputeUseCase) GetDispute(ctx context.Context, userID, disputeID int, readAny bool) (*entity.Dispute, error) {
+	dispute, err := uc.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	// Delivery is returned only to the users who have access to it
+	_, err = uc.deliveries.GetDeliveryByID(ctx, userID, dispute.DeliveryID, readAny)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// GetDeliveryDisputes usecase gets all disputes of the delivery
+// for its participants and staff reading any delivery
+func (uc *DisputeUseCase) GetDeliveryDisputes(ctx context.Context, userID, deliveryID int, readAny bool) ([]*entity.Dispute, error) {
+	_, err := uc.deliveries.GetDeliveryByID(ctx, userID, deliveryID, readAny)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	disputes, err := uc.repo.GetDisputesByDeliveryID(ctx, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return disputes, nil
+}
+
+// GetDisputes usecase gets page of the admins' queue of disputes
+func (uc *DisputeUseCase) GetDisputes(ctx context.Context, query *dto.DisputeListQuery) ([]*entity.Dispute, error) {
+	disputes, err := uc.repo.GetDisputes(ctx, query)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return disputes, nil
+}
+
+// AssignDispute usecase assigns unresolved dispute to the staff member managing disputes
+func (uc *DisputeUseCase) AssignDispute(ctx context.Context, adminID, disputeID int, req *dto.DisputeAssignBody) error {
+	roles, err := uc.roles.GetUserRoles(ctx, req.AssigneeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if !entity.RolesGrant(roles, entity.PermDisputesManage) {
+		err = fmt.Errorf("assignee can't manage disputes")
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	dispute, err := uc.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = uc.repo.AssignDispute(ctx, disputeID, req.AssigneeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, dispute.DeliveryID, adminID, entity.EventDisputeAssigned, map[string]interface{}{
+		"dispute_id":  disputeID,
+		"assignee_id": req.AssigneeID,
+	})
+	return nil
+}
+
+// ChangeDisputeStatus usecase returns unresolved dispute to the queue or takes it in progress
+func (uc *DisputeUseCase) ChangeDisputeStatus(ctx context.Context, adminID, disputeID int, req *dto.DisputeStatusBody) error {
+	dispute, err := uc.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = uc.repo.SetDisputeStatus(ctx, disputeID, req.Status)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, dispute.DeliveryID, adminID, entity.EventDisputeStatusChanged, map[string]interface{}{
+		"dispute_id": disputeID,
+		"status":     req.Status,
+	})
+	return nil
+}
+
+// ResolveDispute usecase closes the dispute with admin's decision, refund and penalty
+// are recorded to the ledger and the penalized participant may be banned
+func (uc *DisputeUseCase) ResolveDispute(ctx context.Context, adminID, disputeID int, req *dto.DisputeResolveBody) (*entity.DisputeResolution, error) {
+	dispute, err := uc.repo.GetDisputeByID(ctx, disputeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if dispute.IsClosed() {
+		err = fmt.Errorf("dispute is already closed")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	// Resolving disputes doesn't grant banning users, so it is checked separately
+	if req.BanUser {
+		roles, err := uc.roles.GetUserRoles(ctx, adminID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		if !entity.RolesGrant(roles, entity.PermUsersBan) {
+			return nil, ErrBanForbidden
+		}
+	}
+
+	// Route is allowed only to staff resolving disputes, who may not be able to read any delivery
+	delivery, err := uc.deliveries.GetDeliveryByID(ctx, adminID, dispute.DeliveryID, true)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	hasPenalty := req.PenaltyAmount > 0 || req.BanUser
+	if req.Status == entity.DisputeRejected && (req.RefundAmount > 0 || hasPenalty) {
+		err = fmt.Errorf("rejected dispute can't have refund or penalty")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if req.RefundAmount > delivery.Price {
+		err = fmt.Errorf("refund exceeds delivery price")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if hasPenalty && req.PenalizedUserID != delivery.ClientID && req.PenalizedUserID != delivery.CourierID {
+		err = fmt.Errorf("penalized user is not delivery participant")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	resolution := &entity.DisputeResolution{
+		DisputeID:  disputeID,
+		Status:     req.Status,
+		Resolution: req.Resolution,
+		Entries:    []*entity.LedgerEntry{},
+	}
+	if req.RefundAmount > 0 {
+		resolution.Entries = append(resolution.Entries, &entity.LedgerEntry{
+			UserID:     delivery.ClientID,
+			DeliveryID: delivery.ID,
+			DisputeID:  &resolution.DisputeID,
+			Kind:       entity.LedgerRefund,
+			Amount:     req.RefundAmount,
+		})
+	}
+	if req.PenaltyAmount > 0 {
+		resolution.Entries = append(resolution.Entries, &entity.LedgerEntry{
+			UserID:     req.PenalizedUserID,
+			DeliveryID: delivery.ID,
+			DisputeID:  &resolution.DisputeID,
+			Kind:       entity.LedgerPenalty,
+			Amount:     -req.PenaltyAmount,
+		})
+	}
+
+	if req.BanUser {
+		resolution.BannedID = req.PenalizedUserID
+	}
+
+	err = uc.repo.ResolveDispute(ctx, resolution)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, dispute.DeliveryID, adminID, entity.EventDisputeResolved, resolution)
+	return resolution, nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// disputeRepoStub returns the open dispute and records resolutions
+type disputeRepoStub struct {
+	DisputeRepo
+	resolved []*entity.DisputeResolution
+}
+
+func (r *disputeRepoStub) GetDisputeByID(ctx context.Context, disputeID int) (*entity.Dispute, error) {
+	return &entity.Dispute{ID: disputeID, DeliveryID: 5, OpenerID: 2, Status: entity.DisputeOpen}, nil
+}
+
+func (r *disputeRepoStub) ResolveDispute(ctx context.Context, resolution *entity.DisputeResolution) error {
+	r.resolved = append(r.resolved, resolution)
+	return nil
+}
+
+// disputeDeliveryStub returns the delivery of client 2 performed by courier 3
+type disputeDeliveryStub struct {
+	DeliveryRepo
+}
+
+func (d *disputeDeliveryStub) GetDeliveryByID(ctx context.Context, clientID, deliveryID int, readAny bool) (*dto.DeliveryFullInfoResponse, error) {
+	return &dto.DeliveryFullInfoResponse{ID: deliveryID, ClientID: 2, CourierID: 3, Price: 100}, nil
+}
+
+// userRolesStub returns the same roles for every user
+type userRolesStub struct {
+	RoleRepo
+	roles []string
+}
+
+func (r *userRolesStub) GetUserRoles(ctx context.Context, userID int) ([]string, error) {
+	return r.roles, nil
+}
+
+// timelineStub drops the delivery's events
+type timelineStub struct {
+	TimelineRepo
+}
+
+func (t *timelineStub) AddEvent(ctx context.Context, event *entity.DeliveryEvent) error {
+	return nil
+}
+
+func TestDisputeUseCase_ResolveDispute(t *testing.T) {
+	testLogger := logrus.New()
+	body := &dto.DisputeResolveBody{
+		Status:          entity.DisputeResolved,
+		Resolution:      "courier damaged the cargo",
+		PenaltyAmount:   50,
+		PenalizedUserID: 3,
+		BanUser:         true,
+	}
+
+	tests := []struct {
+		name       string
+		roles      []string
+		body       *dto.DisputeResolveBody
+		wantErr    error
+		wantBanned int
+	}{
+		{
+			name:    "finance can't ban the user",
+			roles:   []string{entity.RoleFinance},
+			body:    body,
+			wantErr: ErrBanForbidden,
+		},
+		{
+			name:       "support bans the user",
+			roles:      []string{entity.RoleSupport},
+			body:       body,
+			wantBanned: 3,
+		},
+		{
+			name:  "finance penalizes without ban",
+			roles: []string{entity.RoleFinance},
+			body: &dto.DisputeResolveBody{
+				Status:          entity.DisputeResolved,
+				Resolution:      "courier damaged the cargo",
+				PenaltyAmount:   50,
+				PenalizedUserID: 3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &disputeRepoStub{}
+			uc := NewDisputeUseCase(repo, &disputeDeliveryStub{}, &userRolesStub{roles: tt.roles}, &timelineStub{},
+				nil, nil, logger.New(testLogger))
+
+			resolution, err := uc.ResolveDispute(context.Background(), 10, 1, tt.body)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Empty(t, repo.resolved)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, repo.resolved, 1)
+			require.Equal(t, tt.wantBanned, resolution.BannedID)
+		})
+	}
+}
@@ -2,28 +2,45 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/dacore-x/truckly/pkg/geohelper"
 	"github.com/dacore-x/truckly/pkg/logger"
 
 	"github.com/dacore-x/truckly/internal/dto"
 )
 
+// Number of suggestions returned if the limit is not set
+var defaultSuggestLimit = 5
+
 // GeoUseCase is a struct that provides all use cases connected with geo data
 type GeoUseCase struct {
 	webapi    GeoWebAPI
+	cities    CityRepo
+	cache     SuggestCache
 	appLogger *logger.Logger
 }
 
-func NewGeoUseCase(w GeoWebAPI, l *logger.Logger) *GeoUseCase {
+func NewGeoUseCase(w GeoWebAPI, c CityRepo, s SuggestCache, l *logger.Logger) *GeoUseCase {
 	return &GeoUseCase{
 		webapi:    w,
+		cities:    c,
+		cache:     s,
 		appLogger: l,
 	}
 }
 
 // GetCoordsByObject returning coordinates of geo object by query string
-func (uc *GeoUseCase) GetCoordsByObject(ctx context.Context, q string) (*dto.PointResponse, error) {
-	res, err := uc.webapi.GetCoordsByObject(q)
+// searched in the user's city
+func (uc *GeoUseCase) GetCoordsByObject(ctx context.Context, q string, cityID int) (*dto.PointResponse, error) {
+	city, err := uc.cities.GetCityByID(ctx, cityID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	res, err := uc.webapi.GetCoordsByObject(q, city.GeocoderID)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return nil, err
@@ -42,3 +59,49 @@ func (uc *GeoUseCase) GetObjectByCoords(ctx context.Context, lat, lon float64) (
 
 	return res, nil
 }
+
+// GetSuggestions returning ranked geo objects matching the user's input,
+// they are biased to the location if it is set or else searched in the user's city
+func (uc *GeoUseCase) GetSuggestions(ctx context.Context, query *dto.SuggestQuery, cityID int) ([]*dto.SuggestionResponse, error) {
+	city, err := uc.cities.GetCityByID(ctx, cityID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultSuggestLimit
+	}
+
+	var location *dto.PointRequest
+	if query.Lat != nil && query.Lon != nil {
+		location = &dto.PointRequest{Lat: *query.Lat, Lon: *query.Lon}
+	}
+
+	// Input is normalized and location is rounded to ~1km so that
+	// suggestions are reused between keystrokes and nearby users
+	q := strings.Join(strings.Fields(strings.ToLower(query.Q)), " ")
+	key := fmt.Sprintf("%d:%d:%s", city.ID, limit, q)
+	if location != nil {
+		key = fmt.Sprintf("%d:%d:%.2f,%.2f:%s", city.ID, limit, location.Lat, location.Lon, q)
+	}
+
+	suggestions, ok := uc.cache.GetSuggestions(ctx, key)
+	if !ok {
+		suggestions, err = uc.webapi.GetSuggestions(q, city.GeocoderID, location, limit)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		uc.cache.SetSuggestions(ctx, key, suggestions)
+	}
+
+	// Distance depends on the exact location so it isn't cached
+	if location != nil {
+		for _, s := range suggestions {
+			s.Distance = geohelper.Haversine(location.Lat, location.Lon, s.Point.Lat, s.Point.Lon)
+		}
+	}
+	return suggestions, nil
+}
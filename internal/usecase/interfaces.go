@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/dacore-x/truckly/internal/dto"
 	"github.com/dacore-x/truckly/internal/entity"
@@ -30,60 +32,533 @@ type (
 		GetUserMeta(context.Context, int) (*dto.UserMetaResponse, error)
 	}
 
+	// Session interface represents usecases of users' sessions
+	Session interface {
+		Login(ctx context.Context, req *dto.UserLoginRequestBody, client *entity.SessionClient) (*entity.AuthTokens, error)
+		Refresh(ctx context.Context, refreshToken string, client *entity.SessionClient) (*entity.AuthTokens, error)
+		CheckSession(ctx context.Context, userID int, sessionID string) (*entity.Session, error)
+		GetSessions(ctx context.Context, userID int, currentID string) ([]*entity.Session, error)
+		RevokeSession(ctx context.Context, userID int, sessionID string) error
+		RevokeAllSessions(ctx context.Context, userID int) error
+	}
+
+	// SessionStore interface represents storage contract of users' sessions,
+	// only hashes of refresh tokens are stored
+	SessionStore interface {
+		CreateSession(ctx context.Context, session *entity.Session, refreshHash string) error
+		GetSession(ctx context.Context, sessionID string) (*entity.Session, error)
+		RotateSession(ctx context.Context, session *entity.Session, refreshHash, newRefreshHash string) (bool, error)
+		MarkSessionTwoFactor(ctx context.Context, sessionID string) error
+		IsRefreshHashUsed(ctx context.Context, sessionID, refreshHash string) (bool, error)
+		GetSessions(ctx context.Context, userID int) ([]*entity.Session, error)
+		DeleteSession(ctx context.Context, userID int, sessionID string) error
+		DeleteSessions(ctx context.Context, userID int) error
+	}
+
+	// Account interface represents usecases of users' email verification and passwords
+	Account interface {
+		SendVerificationEmail(ctx context.Context, userID int) error
+		VerifyEmail(context.Context, *dto.EmailVerifyRequestBody) error
+		RequestPasswordReset(context.Context, *dto.PasswordForgotRequestBody) error
+		ResetPassword(context.Context, *dto.PasswordResetRequestBody) error
+		ChangePassword(ctx context.Context, userID int, sessionID string, req *dto.PasswordChangeRequestBody) error
+	}
+
+	// AccountRepo interface represents repository contract of users' one-time tokens and passwords
+	AccountRepo interface {
+		CreateUserToken(context.Context, *entity.UserToken) error
+		RenewUserToken(ctx context.Context, id int, tokenHash string) (*entity.UserToken, error)
+		VerifyEmail(ctx context.Context, tokenHash string) (int, error)
+		ResetPassword(ctx context.Context, tokenHash, passwordHash string) (int, error)
+		UpdatePassword(ctx context.Context, userID int, passwordHash string) error
+		VerifyPhone(ctx context.Context, userID int, phone string) error
+		ChangeEmail(ctx context.Context, tokenHash string) (*entity.UserChange, error)
+	}
+
+	// Profile interface represents usecases of changes of users' profiles and their history
+	Profile interface {
+		UpdateProfile(ctx context.Context, userID int, req *dto.UserUpdateRequestBody) (*dto.UserUpdateResponse, error)
+		ConfirmEmailChange(context.Context, *dto.EmailChangeRequestBody) error
+		UploadAvatar(ctx context.Context, userID int, r io.Reader) error
+		GetAvatar(ctx context.Context, userID int) (io.ReadCloser, error)
+		GetHistory(ctx context.Context, userID int, at time.Time) ([]*entity.UserChange, *dto.UserSnapshot, error)
+	}
+
+	// ProfileRepo interface represents repository contract of changes of users' profiles
+	ProfileRepo interface {
+		UpdateProfile(ctx context.Context, userID, changedBy int, values map[string]string) ([]*entity.UserChange, error)
+		GetUserChanges(ctx context.Context, userID int) ([]*entity.UserChange, error)
+	}
+
+	// Phone interface represents usecases of users' phone numbers verification
+	Phone interface {
+		SendCode(ctx context.Context, userID int) error
+		VerifyCode(ctx context.Context, userID int, req *dto.PhoneVerifyRequestBody) error
+	}
+
+	// PhoneCodeStore interface represents storage contract of one-time codes
+	// of phone numbers' verification, only hashes of codes are stored
+	PhoneCodeStore interface {
+		StartCooldown(ctx context.Context, userID int, cooldown time.Duration) (bool, error)
+		CountSend(ctx context.Context, userID int) (int, error)
+		SaveCode(ctx context.Context, code *entity.PhoneCode, ttl time.Duration) error
+		UseAttempt(ctx context.Context, userID int) (*entity.PhoneCode, error)
+		DeleteCode(ctx context.Context, userID int) error
+	}
+
+	// SMSSender interface represents contract of sending transactional SMS
+	SMSSender interface {
+		SendSMS(ctx context.Context, to, text string) error
+	}
+
+	// TwoFactor interface represents usecases of users' TOTP second factor
+	TwoFactor interface {
+		Enroll(ctx context.Context, userID int) (*entity.TwoFactorEnrollment, error)
+		Confirm(ctx context.Context, userID int, sessionID string, req *dto.TwoFactorCodeRequestBody) ([]string, error)
+		Disable(ctx context.Context, userID int, req *dto.TwoFactorCodeRequestBody) error
+		RegenerateRecoveryCodes(ctx context.Context, userID int, req *dto.TwoFactorCodeRequestBody) ([]string, error)
+	}
+
+	// TwoFactorRepo interface represents repository contract of users' second factor,
+	// only hashes of recovery codes are stored
+	TwoFactorRepo interface {
+		GetTwoFactor(ctx context.Context, userID int) (*entity.TwoFactor, error)
+		SaveTwoFactorSecret(ctx context.Context, userID int, secret string) error
+		EnableTwoFactor(ctx context.Context, userID int, codeHashes []string) error
+		ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+		UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+		UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+		DeleteTwoFactor(ctx context.Context, userID int) error
+	}
+
+	// TwoFactorStore interface represents storage contract of unsuccessful attempts of passing the second factor
+	TwoFactorStore interface {
+		CountAttempt(ctx context.Context, userID int, window time.Duration) (int, error)
+		ResetAttempts(ctx context.Context, userID int) error
+	}
+
+	// Organization interface represents usecases of organizations,
+	// their members, shared address book and reports
+	Organization interface {
+		CreateOrganization(ctx context.Context, userID int, req *dto.OrganizationRequestBody) (*entity.Organization, error)
+		GetOrganizations(ctx context.Context, userID int) ([]*entity.Organization, error)
+		GetOrganization(ctx context.Context, userID, orgID int) (*entity.Organization, error)
+		UpdateOrganization(ctx context.Context, userID, orgID int, req *dto.OrganizationRequestBody) error
+		GetMembers(ctx context.Context, userID, orgID int) ([]*entity.OrganizationMember, error)
+		InviteMember(ctx context.Context, userID, orgID int, req *dto.OrganizationInviteRequestBody) error
+		AcceptInvitation(ctx context.Context, userID int, req *dto.OrganizationAcceptRequestBody) (*entity.Organization, error)
+		UpdateMemberRole(ctx context.Context, userID, orgID, memberID int, req *dto.OrganizationMemberRoleRequestBody) error
+		RemoveMember(ctx context.Context, userID, orgID, memberID int) error
+		CreatePlace(ctx context.Context, userID, orgID int, req *dto.SavedPlaceRequestBody) (*entity.SavedPlace, error)
+		UpdatePlace(ctx context.Context, userID, orgID, placeID int, req *dto.SavedPlaceRequestBody) error
+		DeletePlace(ctx context.Context, userID, orgID, placeID int) error
+		GetPlaces(ctx context.Context, userID, orgID int) ([]*entity.SavedPlace, error)
+		GetDeliveries(ctx context.Context, userID, orgID int, query *dto.OrganizationDeliveriesQuery) ([]*dto.DeliveryBriefResponse, error)
+		GetSpending(ctx context.Context, userID, orgID int, query *dto.OrganizationSpendingQuery) (*dto.OrganizationSpendingResponse, error)
+	}
+
+	// OrganizationRepo interface represents repository contract of organizations,
+	// only hashes of invitations' tokens are stored
+	OrganizationRepo interface {
+		CreateOrganization(ctx context.Context, org *entity.Organization, ownerID int) error
+		UpdateOrganization(context.Context, *entity.Organization) error
+		GetOrganizationByID(ctx context.Context, orgID int) (*entity.Organization, error)
+		GetUserOrganizations(ctx context.Context, userID int) ([]*entity.Organization, error)
+		GetMemberRole(ctx context.Context, orgID, userID int) (string, error)
+		GetMembers(ctx context.Context, orgID int) ([]*entity.OrganizationMember, error)
+		UpdateMemberRole(ctx context.Context, orgID, userID int, role string) error
+		DeleteMember(ctx context.Context, orgID, userID int) error
+		CreateInvitation(context.Context, *entity.OrganizationInvitation) error
+		RenewInvitation(ctx context.Context, id int, tokenHash string) (*entity.OrganizationInvitation, error)
+		AcceptInvitation(ctx context.Context, tokenHash string, userID int, email string) (int, error)
+		CreateOrganizationPlace(context.Context, *entity.SavedPlace) error
+		UpdateOrganizationPlace(context.Context, *entity.SavedPlace) error
+		DeleteOrganizationPlace(ctx context.Context, orgID, placeID int) error
+		GetOrganizationPlaces(ctx context.Context, orgID int) ([]*entity.SavedPlace, error)
+		GetOrganizationPlaceByID(ctx context.Context, orgID, placeID int) (*entity.SavedPlace, error)
+		CountOrganizationPlaces(ctx context.Context, orgID int) (int, error)
+		GetOrganizationDeliveries(ctx context.Context, orgID, clientID, page int) ([]*dto.DeliveryBriefResponse, error)
+		GetOrganizationSpending(ctx context.Context, orgID int, from, to time.Time) ([]*dto.MemberSpendingResponse, error)
+	}
+
+	// Role interface represents usecases of users' roles and permissions
+	Role interface {
+		GetRoles(ctx context.Context, userID int) ([]string, error)
+		GetPermissions(ctx context.Context, userID int) ([]string, error)
+		HasPermission(ctx context.Context, userID int, permission string) (bool, error)
+		GrantRole(ctx context.Context, staffID, userID int, req *dto.RoleRequestBody) error
+		RevokeRole(ctx context.Context, staffID, userID int, role string) error
+	}
+
+	// RoleRepo interface represents repository contract of users' roles
+	RoleRepo interface {
+		GetUserRoles(ctx context.Context, userID int) ([]string, error)
+		GrantRole(ctx context.Context, userID int, role string, grantedBy int) error
+		RevokeRole(ctx context.Context, userID int, role string) error
+	}
+
+	// PermissionCache interface represents contract of the cache of users' permissions
+	PermissionCache interface {
+		GetPermissions(ctx context.Context, userID int) ([]string, bool, error)
+		SetPermissions(ctx context.Context, userID int, permissions []string, ttl time.Duration) error
+		DeletePermissions(ctx context.Context, userID int) error
+	}
+
+	// APIKey interface represents usecases of clients' api keys
+	APIKey interface {
+		CreateAPIKey(ctx context.Context, userID int, req *dto.APIKeyRequestBody) (*entity.APIKey, error)
+		GetAPIKeys(ctx context.Context, userID int) ([]*entity.APIKey, error)
+		RotateAPIKey(ctx context.Context, userID, keyID int) (*entity.APIKey, error)
+		RevokeAPIKey(ctx context.Context, userID, keyID int) error
+		Authenticate(ctx context.Context, rawKey string) (*entity.APIKey, error)
+	}
+
+	// APIKeyRepo interface represents repository contract of api keys
+	APIKeyRepo interface {
+		CreateAPIKey(context.Context, *entity.APIKey) error
+		GetAPIKeys(ctx context.Context, userID int) ([]*entity.APIKey, error)
+		GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+		RotateAPIKey(context.Context, *entity.APIKey) error
+		RevokeAPIKey(ctx context.Context, userID, keyID int) error
+		TouchAPIKey(ctx context.Context, keyID int) error
+	}
+
 	// Delivery interface represents delivery usecases
 	Delivery interface {
 		CreateDelivery(context.Context, *entity.Delivery) error
-		GetDeliveryByID(ctx context.Context, clientID int, deliveryID int) (*dto.DeliveryFullInfoResponse, error)
+		GetDeliveryByID(ctx context.Context, clientID, deliveryID int, readAny bool) (*dto.DeliveryFullInfoResponse, error)
 		GetDeliveriesByGeolocation(ctx context.Context, query *dto.DeliveryListGeolocationQuery) ([]*dto.DeliveryBriefResponse, error)
 		GetDeliveriesByClientID(ctx context.Context, clientID int, page int) ([]*dto.DeliveryBriefResponse, error)
 		GetDeliveriesByCourierID(ctx context.Context, courierID int, page int) ([]*dto.DeliveryBriefResponse, error)
 		AcceptDelivery(ctx context.Context, courierID int, deliveryID int) error
+		PickUpDelivery(ctx context.Context, courierID, deliveryID int) error
+		GetActiveRoute(ctx context.Context, courierID int) (*dto.RouteResponse, error)
 		ChangeDeliveryStatus(ctx context.Context, courierID, deliveryID, statusID int) error
 		CancelDelivery(ctx context.Context, clientID, deliveryID int) error
+		CancelAnyDelivery(ctx context.Context, staffID, deliveryID int) error
+		GetTimeline(ctx context.Context, userID, deliveryID int, readAny bool) ([]*entity.DeliveryEvent, error)
 	}
 
 	// DeliveryRepo interface represents delivery's repository contract
 	DeliveryRepo interface {
 		CreateDelivery(context.Context, *entity.Delivery) error
-		GetDeliveryByID(ctx context.Context, clientID int, deliveryID int) (*dto.DeliveryFullInfoResponse, error)
-		GetDeliveriesByGeolocation(context.Context, *dto.DeliveryListGeolocationQuery, float64) ([]*dto.DeliveryBriefResponse, error)
+		GetDeliveryByID(ctx context.Context, clientID, deliveryID int, readAny bool) (*dto.DeliveryFullInfoResponse, error)
+		GetDeliveriesByGeolocation(context.Context, *dto.DeliveryListGeolocationQuery) ([]*dto.DeliveryBriefResponse, error)
 		GetDeliveriesByClientID(ctx context.Context, clientID int, page int) ([]*dto.DeliveryBriefResponse, error)
 		GetDeliveriesByCourierID(ctx context.Context, courierID int, page int) ([]*dto.DeliveryBriefResponse, error)
 		AcceptDelivery(ctx context.Context, courierID int, deliveryID int) error
-		GetActiveDeliveryAmount(ctx context.Context, courierID int) (int, error)
+		PickUpDelivery(ctx context.Context, deliveryID int) error
+		CancelDelivery(ctx context.Context, clientID, deliveryID int) error
+		GetVehicleCapacity(ctx context.Context, typeID int) (*entity.VehicleCapacity, error)
+		GetCourierPresence(ctx context.Context, courierID int) (*entity.CourierPresence, error)
+		GetActiveDeliveriesByCourierID(ctx context.Context, courierID int) ([]*entity.Delivery, error)
 		IsDeliveryPerformer(ctx context.Context, courierID, deliveryID int) (bool, error)
 		IsDeliveryOwner(ctx context.Context, courierID, deliveryID int) (bool, error)
 		ChangeDeliveryStatus(ctx context.Context, statusID, deliveryID int) error
 	}
 
-	// Metrics interface represents metrics usecases
+	// Proof interface represents proof of delivery usecases
+	Proof interface {
+		UploadProofFile(ctx context.Context, courierID, deliveryID int, kind string, r io.Reader) error
+		GetProofFile(ctx context.Context, userID, deliveryID int, kind string, readAny bool) (io.ReadCloser, error)
+		CompleteDelivery(ctx context.Context, courierID, deliveryID int, req *dto.ProofCompleteBody) error
+	}
+
+	// ProofRepo interface represents proofs of delivery repository contract
+	ProofRepo interface {
+		GetProof(ctx context.Context, deliveryID int) (*entity.Proof, error)
+		SetProofFile(ctx context.Context, deliveryID int, kind, key string) error
+		UsePINAttempt(ctx context.Context, deliveryID, maxAttempts int) (string, error)
+		CompleteDelivery(context.Context, *entity.Proof) error
+	}
+
+	// TimelineRepo interface represents repository contract of deliveries' timelines
+	TimelineRepo interface {
+		AddEvent(context.Context, *entity.DeliveryEvent) error
+		GetEvents(ctx context.Context, deliveryID int) ([]*entity.DeliveryEvent, error)
+	}
+
+	// BlobStore interface represents contract of the storage of uploaded files
+	BlobStore interface {
+		Put(ctx context.Context, key string, r io.Reader) error
+		Get(ctx context.Context, key string) (io.ReadCloser, error)
+	}
+
+	// Review interface represents ratings and reviews usecases
+	Review interface {
+		CreateReview(ctx context.Context, authorID, deliveryID int, req *dto.ReviewRequestBody) (*entity.Review, error)
+		GetUserReviews(ctx context.Context, userID, page int) ([]*entity.Review, error)
+		GetReviews(ctx context.Context, page int) ([]*entity.Review, error)
+		ModerateReview(ctx context.Context, reviewID int, hidden bool) error
+	}
+
+	// ReviewRepo interface represents reviews' repository contract
+	ReviewRepo interface {
+		CreateReview(context.Context, *entity.Review) error
+		GetRecentScores(ctx context.Context, targetID, limit int) ([]int, error)
+		SetRating(ctx context.Context, userID int, rating float64) error
+		GetReviewsByTargetID(ctx context.Context, targetID, page int) ([]*entity.Review, error)
+		GetReviews(ctx context.Context, page int) ([]*entity.Review, error)
+		SetCommentHidden(ctx context.Context, reviewID int, hidden bool) error
+	}
+
+	// Dispute interface represents usecases of disputes on deliveries
+	Dispute interface {
+		OpenDispute(ctx context.Context, userID, deliveryID int, req *dto.DisputeRequestBody) (*entity.Dispute, error)
+		UploadAttachment(ctx context.Context, userID, disputeID int, r io.Reader) (*entity.DisputeAttachment, error)
+		GetAttachment(ctx context.Context, userID, disputeID, attachmentID int, readAny bool) (io.ReadCloser, error)
+		GetDispute(ctx context.Context, userID, disputeID int, readAny bool) (*entity.Dispute, error)
+		GetDeliveryDisputes(ctx context.Context, userID, deliveryID int, readAny bool) ([]*entity.Dispute, error)
+		GetDisputes(ctx context.Context, query *dto.DisputeListQuery) ([]*entity.Dispute, error)
+		AssignDispute(ctx context.Context, adminID, disputeID int, req *dto.DisputeAssignBody) error
+		ChangeDisputeStatus(ctx context.Context, adminID, disputeID int, req *dto.DisputeStatusBody) error
+		ResolveDispute(ctx context.Context, adminID, disputeID int, req *dto.DisputeResolveBody) (*entity.DisputeResolution, error)
+	}
+
+	// DisputeRepo interface represents repository contract of disputes and ledger
+	DisputeRepo interface {
+		CreateDispute(context.Context, *entity.Dispute) error
+		GetDisputeByID(ctx context.Context, disputeID int) (*entity.Dispute, error)
+		GetDisputesByDeliveryID(ctx context.Context, deliveryID int) ([]*entity.Dispute, error)
+		GetDisputes(context.Context, *dto.DisputeListQuery) ([]*entity.Dispute, error)
+		AddAttachment(context.Context, *entity.DisputeAttachment) error
+		AssignDispute(ctx context.Context, disputeID, assigneeID int) error
+		SetDisputeStatus(ctx context.Context, disputeID int, status string) error
+		ResolveDispute(context.Context, *entity.DisputeResolution) error
+	}
+
+	// Notification interface represents usecases of user's notification settings
+	Notification interface {
+		GetPreferences(ctx context.Context, userID int) (*entity.NotificationPreferences, error)
+		UpdatePreferences(ctx context.Context, userID int, req *dto.NotificationPreferencesBody) error
+	}
+
+	// Notifier interface represents contract of notifying users about deliveries,
+	// notifications are sent asynchronously so failures are not reported to the caller
+	Notifier interface {
+		NotifyUser(ctx context.Context, userID, deliveryID int, event string)
+	}
+
+	// NotificationRepo interface represents notifications' repository contract
+	NotificationRepo interface {
+		GetPreferences(ctx context.Context, userID int) (*entity.NotificationPreferences, error)
+		SetPreferences(context.Context, *entity.NotificationPreferences) error
+		GetRecipient(ctx context.Context, userID int) (*entity.NotificationRecipient, error)
+		GetDeliveryNotice(ctx context.Context, deliveryID int) (*entity.DeliveryNotice, error)
+		CreateNotification(context.Context, *entity.Notification) error
+		ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]*entity.Notification, error)
+		UpdateNotification(context.Context, *entity.Notification) error
+	}
+
+	// NotificationSender interface represents contract of the channel's sender
+	NotificationSender interface {
+		Send(context.Context, *entity.Notification) error
+	}
+
+	// NotificationComposer interface represents contract of composing the event's notifications
+	// right before they are sent, so that one-time links in them are never stored
+	NotificationComposer interface {
+		Compose(context.Context, *entity.Notification) error
+	}
+
+	// Webhook interface represents usecases of clients' webhooks
+	Webhook interface {
+		CreateWebhook(ctx context.Context, userID int, req *dto.WebhookRequestBody) (*entity.Webhook, error)
+		UpdateWebhook(ctx context.Context, userID, webhookID int, req *dto.WebhookRequestBody) error
+		DeleteWebhook(ctx context.Context, userID, webhookID int) error
+		GetWebhooks(ctx context.Context, userID int) ([]*entity.Webhook, error)
+		GetWebhookDeliveries(ctx context.Context, userID, webhookID, page int) ([]*entity.WebhookDelivery, error)
+		Redeliver(ctx context.Context, userID, webhookID, deliveryID int) (*entity.WebhookDelivery, error)
+	}
+
+	// WebhookRepo interface represents repository contract of webhooks and their deliveries
+	WebhookRepo interface {
+		CreateWebhook(context.Context, *entity.Webhook) error
+		UpdateWebhook(context.Context, *entity.Webhook) error
+		DeleteWebhook(ctx context.Context, userID, webhookID int) error
+		GetWebhooks(ctx context.Context, userID int) ([]*entity.Webhook, error)
+		GetWebhookByID(ctx context.Context, userID, webhookID int) (*entity.Webhook, error)
+		GetSubscribedWebhooks(ctx context.Context, userID int, event string) ([]*entity.Webhook, error)
+		GetWebhookEventData(ctx context.Context, deliveryID int) (*entity.WebhookEventData, error)
+		CreateWebhookDelivery(context.Context, *entity.WebhookDelivery) error
+		GetWebhookDeliveries(ctx context.Context, webhookID, page int) ([]*entity.WebhookDelivery, error)
+		GetWebhookDeliveryByID(ctx context.Context, webhookID, deliveryID int) (*entity.WebhookDelivery, error)
+		ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error)
+		RecordWebhookAttempt(ctx context.Context, delivery *entity.WebhookDelivery, disableAfter int) (bool, error)
+	}
+
+	// WebhookClient interface represents contract of the client of webhook endpoints
+	WebhookClient interface {
+		Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+	}
+
+	// OutboxRepo interface represents repository contract of the outbox of domain events
+	OutboxRepo interface {
+		ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]*entity.OutboxEvent, error)
+		MarkOutboxEventPublished(ctx context.Context, id int) error
+		FailOutboxEvent(ctx context.Context, event *entity.OutboxEvent, lastError string, nextAttemptAt time.Time, dead bool) error
+		DeletePublishedOutboxEvents(ctx context.Context, before time.Time) (int64, error)
+	}
+
+	// EventBus interface represents contract of the bus domain events are relayed to
+	// from the outbox, each group of handlers receives events at least once in order
+	// of publishing, so handlers must tolerate duplicates. Groups handling the event
+	// in Publish add themselves to its HandledGroups and aren't called for it again
+	EventBus interface {
+		Publish(context.Context, *entity.OutboxEvent) error
+		Subscribe(ctx context.Context, group string, handler func(context.Context, *entity.OutboxEvent) error) error
+	}
+
+	// Chat interface represents usecases of the chat between client and courier
+	Chat interface {
+		SendMessage(ctx context.Context, senderID, deliveryID int, req *dto.ChatMessageRequestBody) (*entity.ChatMessage, error)
+		GetMessages(ctx context.Context, userID, deliveryID int, readAny bool, query *dto.ChatHistoryQuery) ([]*entity.ChatMessage, error)
+		MarkRead(ctx context.Context, readerID, deliveryID int, req *dto.ChatReadRequestBody) error
+		Subscribe(ctx context.Context, userID, deliveryID int, readAny bool) (<-chan *entity.ChatEvent, func(), error)
+	}
+
+	// ChatRepo interface represents chats' repository contract
+	ChatRepo interface {
+		GetChatChannel(ctx context.Context, deliveryID int) (*entity.ChatChannel, error)
+		CreateMessage(context.Context, *entity.ChatMessage) error
+		GetMessages(ctx context.Context, deliveryID, beforeID, limit int) ([]*entity.ChatMessage, error)
+		MarkRead(ctx context.Context, deliveryID, readerID, upToID int) (int, error)
+	}
+
+	// ChatBroker interface represents contract of the real time delivery of chat events
+	ChatBroker interface {
+		Publish(ctx context.Context, deliveryID int, event *entity.ChatEvent) error
+		Subscribe(ctx context.Context, deliveryID int) (<-chan *entity.ChatEvent, func(), error)
+	}
+
+	// Dispatch interface represents automatic dispatch usecases
+	Dispatch interface {
+		SetCourierPresence(ctx context.Context, courierID int, req *dto.CourierPresenceRequestBody) error
+		GetPendingOffers(ctx context.Context, courierID int) ([]*dto.OfferResponse, error)
+		AcceptOffer(ctx context.Context, courierID, offerID int) error
+		DeclineOffer(ctx context.Context, courierID, offerID int) error
+	}
+
+	// DeliveryDispatcher interface represents dispatcher contract
+	// used to offer new deliveries to couriers
+	DeliveryDispatcher interface {
+		DispatchDelivery(context.Context, *entity.Delivery)
+	}
+
+	// DispatchRepo interface represents dispatch's repository contract
+	DispatchRepo interface {
+		SetCourierPresence(ctx context.Context, courierID int, req *dto.CourierPresenceRequestBody) error
+		GetDispatchCandidates(ctx context.Context, deliveryID int) ([]*entity.CourierCandidate, error)
+		SetDeliveryDispatching(ctx context.Context, deliveryID int, dispatching bool) error
+		ReleaseStaleDispatches(ctx context.Context, staleAfter time.Duration) (int, error)
+		GetDeliveryStatus(ctx context.Context, deliveryID int) (int, error)
+		CreateOffer(context.Context, *entity.Offer) error
+		GetOfferStatus(ctx context.Context, offerID int) (string, error)
+		ExpireOffer(ctx context.Context, offerID int) (bool, error)
+		AcceptOffer(ctx context.Context, courierID, offerID int) (int, error)
+		DeclineOffer(ctx context.Context, courierID, offerID int) error
+		GetPendingOffersByCourierID(ctx context.Context, courierID int) ([]*dto.OfferResponse, error)
+	}
+
+	// Zone interface represents service zones' usecases
+	Zone interface {
+		CreateZone(context.Context, *dto.ZoneFeature) (*dto.ZoneFeature, error)
+		ImportZones(context.Context, *dto.ZoneFeatureCollection) (int, error)
+		UpdateZone(ctx context.Context, zoneID int, feature *dto.ZoneFeature) error
+		DeleteZone(ctx context.Context, zoneID int) error
+		GetZones(context.Context) (*dto.ZoneFeatureCollection, error)
+	}
+
+	// CoverageChecker interface represents contract of the service area
+	// check used before estimating price and creating deliveries
+	CoverageChecker interface {
+		CheckCoverage(ctx context.Context, typeID int, from, to *dto.PointRequest) (*entity.ServiceZone, error)
+	}
+
+	// ZoneRepo interface represents service zones' repository contract
+	ZoneRepo interface {
+		CreateZone(context.Context, *entity.ServiceZone) error
+		ImportZones(context.Context, []*entity.ServiceZone) error
+		UpdateZone(context.Context, *entity.ServiceZone) error
+		DeleteZone(ctx context.Context, zoneID int) error
+		GetZones(context.Context) ([]*entity.ServiceZone, error)
+	}
+
+	// City interface represents cities' usecases
+	City interface {
+		CreateCity(context.Context, *dto.CityRequestBody) (*entity.City, error)
+		UpdateCity(ctx context.Context, cityID int, req *dto.CityRequestBody) error
+		GetCities(context.Context) ([]*entity.City, error)
+	}
+
+	// CityRepo interface represents cities' repository contract
+	CityRepo interface {
+		CreateCity(context.Context, *entity.City) error
+		UpdateCity(context.Context, *entity.City) error
+		GetCities(context.Context) ([]*entity.City, error)
+		GetCityByID(ctx context.Context, cityID int) (*entity.City, error)
+	}
+
+	// SavedPlace interface represents usecases of user's address book
+	SavedPlace interface {
+		CreatePlace(ctx context.Context, userID int, req *dto.SavedPlaceRequestBody) (*entity.SavedPlace, error)
+		UpdatePlace(ctx context.Context, userID, placeID int, req *dto.SavedPlaceRequestBody) error
+		DeletePlace(ctx context.Context, userID, placeID int) error
+		GetPlaces(ctx context.Context, userID int) ([]*entity.SavedPlace, error)
+	}
+
+	// SavedPlaceRepo interface represents saved places' repository contract
+	SavedPlaceRepo interface {
+		CreatePlace(context.Context, *entity.SavedPlace) error
+		UpdatePlace(context.Context, *entity.SavedPlace) error
+		DeletePlace(ctx context.Context, userID, placeID int) error
+		GetPlaces(ctx context.Context, userID int) ([]*entity.SavedPlace, error)
+		GetPlaceByID(ctx context.Context, userID, placeID int) (*entity.SavedPlace, error)
+		CountPlaces(ctx context.Context, userID int) (int, error)
+	}
+
+	// Metrics interface represents metrics usecases,
+	// city id 0 stands for all cities
 	Metrics interface {
-		GetMetrics(context.Context) (*dto.MetricsPerDayResponse, error)
-		GetCurrentDeliveries(context.Context) (*dto.MetricsDeliveriesResponse, error)
+		GetMetrics(ctx context.Context, cityID int) (*dto.MetricsPerDayResponse, error)
+		GetCurrentDeliveries(ctx context.Context, cityID int) (*dto.MetricsDeliveriesResponse, error)
 	}
 
-	// MetricsRepo interface represents metrics' repository contract
+	// MetricsRepo interface represents metrics' repository contract,
+	// city id 0 stands for all cities
 	MetricsRepo interface {
-		GetDeliveriesCntPerDay(context.Context) (*dto.DeliveriesCntPerDay, error)
-		GetRevenuePerDay(context.Context) (*dto.RevenuePerDay, error)
-		GetNewClientsCntPerDay(context.Context) (*dto.NewClientsCntPerDay, error)
-		GetDeliveryTypesPercentPerDay(context.Context) (*dto.DeliveryTypesPercentPerDay, error)
-		GetCurrentDeliveries(context.Context) (*dto.MetricsDeliveriesResponse, error)
+		GetDeliveriesCntPerDay(ctx context.Context, cityID int) (*dto.DeliveriesCntPerDay, error)
+		GetRevenuePerDay(ctx context.Context, cityID int) (*dto.RevenuePerDay, error)
+		GetNewClientsCntPerDay(ctx context.Context, cityID int) (*dto.NewClientsCntPerDay, error)
+		GetDeliveryTypesPercentPerDay(ctx context.Context, cityID int) (*dto.DeliveryTypesPercentPerDay, error)
+		GetZonesPerDay(ctx context.Context, cityID int) ([]*dto.ZoneMetricsPerDay, error)
+		GetCurrentDeliveries(ctx context.Context, cityID int) (*dto.MetricsDeliveriesResponse, error)
 	}
 
 	Geo interface {
-		GetCoordsByObject(ctx context.Context, q string) (*dto.PointResponse, error)
+		GetCoordsByObject(ctx context.Context, q string, cityID int) (*dto.PointResponse, error)
 		GetObjectByCoords(ctx context.Context, lat, lon float64) (string, error)
+		GetSuggestions(ctx context.Context, query *dto.SuggestQuery, cityID int) ([]*dto.SuggestionResponse, error)
+	}
+
+	// SuggestCache interface represents contract of the cache
+	// of address suggestions shared between users
+	SuggestCache interface {
+		GetSuggestions(ctx context.Context, key string) ([]*dto.SuggestionResponse, bool)
+		SetSuggestions(ctx context.Context, key string, suggestions []*dto.SuggestionResponse)
 	}
 	// GeoWebAPI interface represents Geo API contract
 	GeoWebAPI interface {
-		GetCoordsByObject(q string) (*dto.PointResponse, error)
+		GetCoordsByObject(q, cityGeocoderID string) (*dto.PointResponse, error)
+		GetSuggestions(q, cityGeocoderID string, location *dto.PointRequest, limit int) ([]*dto.SuggestionResponse, error)
+		GetObjectByID(id string) (*dto.SuggestionResponse, error)
 		GetObjectByCoords(lat, lon float64) (string, error)
 		GetDistanceBetweenPoints(latFrom, lonFrom, latTo, lonTo float64) (float64, error)
+		GetDistanceMatrix(sources, targets []dto.PointRequest) (*dto.RouteMatrix, error)
 	}
 
 	PriceEstimator interface {
-		EstimateDeliveryPrice(ctx context.Context, body *dto.EstimatePriceRequestBody) (float64, error)
+		EstimateDeliveryPrice(ctx context.Context, body *dto.EstimatePriceRequestBody) (*dto.EstimatePriceResponse, error)
 	}
 
 	PriceEstimatorService interface {
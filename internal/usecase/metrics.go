@@ -22,12 +22,13 @@ func NewMetricsUseCase(r MetricsRepo, l *logger.Logger) *MetricsUseCase {
 	}
 }
 
-// GetMetrics usecase gets all metrics per last 24 hours from storage
-func (uc *MetricsUseCase) GetMetrics(ctx context.Context) (*dto.MetricsPerDayResponse, error) {
+// GetMetrics usecase gets all metrics per last 24 hours from storage,
+// metrics are calculated for the city if its id is set and for all cities otherwise
+func (uc *MetricsUseCase) GetMetrics(ctx context.Context, cityID int) (*dto.MetricsPerDayResponse, error) {
 	resp := &dto.MetricsPerDayResponse{}
 
 	// Get new and completed deliveries' counts per last 24 hours
-	firstMetric, err := uc.repo.GetDeliveriesCntPerDay(context.Background())
+	firstMetric, err := uc.repo.GetDeliveriesCntPerDay(context.Background(), cityID)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return nil, err
@@ -37,7 +38,7 @@ func (uc *MetricsUseCase) GetMetrics(ctx context.Context) (*dto.MetricsPerDayRes
 	resp.DeliveriesCnt = firstMetric
 
 	// Get revenue sum per last 24 hours
-	secondMetric, err := uc.repo.GetRevenuePerDay(context.Background())
+	secondMetric, err := uc.repo.GetRevenuePerDay(context.Background(), cityID)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return nil, err
@@ -47,7 +48,7 @@ func (uc *MetricsUseCase) GetMetrics(ctx context.Context) (*dto.MetricsPerDayRes
 	resp.Revenue = secondMetric
 
 	// Get new registered clients' count per last 24 hours
-	thirdMetric, err := uc.repo.GetNewClientsCntPerDay(context.Background())
+	thirdMetric, err := uc.repo.GetNewClientsCntPerDay(context.Background(), cityID)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return nil, err
@@ -57,7 +58,7 @@ func (uc *MetricsUseCase) GetMetrics(ctx context.Context) (*dto.MetricsPerDayRes
 	resp.NewClientsCnt = thirdMetric
 
 	// Get different delivery types' percentages per last 24 hours
-	fourthMetric, err := uc.repo.GetDeliveryTypesPercentPerDay(context.Background())
+	fourthMetric, err := uc.repo.GetDeliveryTypesPercentPerDay(context.Background(), cityID)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return nil, err
@@ -66,12 +67,23 @@ func (uc *MetricsUseCase) GetMetrics(ctx context.Context) (*dto.MetricsPerDayRes
 	// Attach different delivery types' percentages per last 24 hours metric to response
 	resp.DeliveryTypesPercent = fourthMetric
 
+	// Get deliveries' counts and revenue of service zones per last 24 hours
+	fifthMetric, err := uc.repo.GetZonesPerDay(context.Background(), cityID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	// Attach service zones' metrics per last 24 hours to response
+	resp.Zones = fifthMetric
+
 	return resp, nil
 }
 
 // GetCurrentDeliveries usecase gets list of brief information about current deliveries
-func (uc *MetricsUseCase) GetCurrentDeliveries(context.Context) (*dto.MetricsDeliveriesResponse, error) {
-	list, err := uc.repo.GetCurrentDeliveries(context.Background())
+// of the city if its id is set and of all cities otherwise
+func (uc *MetricsUseCase) GetCurrentDeliveries(ctx context.Context, cityID int) (*dto.MetricsDeliveriesResponse, error) {
+	list, err := uc.repo.GetCurrentDeliveries(context.Background(), cityID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/dacore-x/truckly/pkg/phonehelper"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ErrNotificationStale is returned by composers when the link of the notification
+// is already used, replaced or expired, such notifications are not retried
+var ErrNotificationStale = errors.New("link of the notification is no longer valid")
+
+// Number of notifications taken from the queue at once and time they are
+// hidden from other workers while being sent
+var (
+	notificationBatchSize = 50
+	notificationLease     = time.Minute
+)
+
+// notificationTemplate represents subject and text of the event's notification,
+// subject is used as title of emails and pushes
+type notificationTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func newNotificationTemplate(subject, body string) *notificationTemplate {
+	return &notificationTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// Templates of the events, they are rendered with entity.DeliveryNotice
+var notificationTemplates = map[string]*notificationTemplate{
+	entity.NotifyDeliveryAccepted: newNotificationTemplate(
+		"Courier is on the way",
+		"Courier has accepted delivery #{{.DeliveryID}} from {{.FromObject}} to {{.ToObject}}.",
+	),
+	entity.NotifyDeliveryPickedUp: newNotificationTemplate(
+		"Cargo is picked up",
+		"Courier has picked up the cargo of delivery #{{.DeliveryID}} and is heading to {{.ToObject}}.",
+	),
+	entity.NotifyDeliveryCompleted: newNotificationTemplate(
+		"Delivery is completed",
+		"Delivery #{{.DeliveryID}} to {{.ToObject}} is completed. Please rate the courier.",
+	),
+	entity.NotifyDeliveryCancelled: newNotificationTemplate(
+		"Delivery is cancelled",
+		"Client has cancelled delivery #{{.DeliveryID}} from {{.FromObject}} to {{.ToObject}}.",
+	),
+	entity.NotifyDeliveryPIN: newNotificationTemplate(
+		"PIN of the delivery",
+		"Courier is bringing delivery #{{.DeliveryID}} from {{.FromObject}}. Tell the courier PIN {{.PIN}} to receive it.",
+	),
+	entity.NotifyNewOrderNearby: newNotificationTemplate(
+		"New order nearby",
+		"New delivery #{{.DeliveryID}} from {{.FromObject}} to {{.ToObject}} for {{printf \"%.2f\" .Price}} is offered to you.",
+	),
+}
+
+// Notifications of the deliveries' domain events
+var deliveryNotifications = map[string]string{
+	entity.DeliveryAccepted:  entity.NotifyDeliveryAccepted,
+	entity.DeliveryPickedUp:  entity.NotifyDeliveryPickedUp,
+	entity.DeliveryCompleted: entity.NotifyDeliveryCompleted,
+	entity.DeliveryCancelled: entity.NotifyDeliveryCancelled,
+}
+
+// NotificationUseCase is a struct that provides all use cases of notifications,
+// they are queued by events and sent by the worker via channels' senders
+type NotificationUseCase struct {
+	repo      NotificationRepo
+	senders   map[string]NotificationSender
+	composers map[string]NotificationComposer
+	cfg       *config.NOTIFY
+	appLogger *logger.Logger
+}
+
+func NewNotificationUseCase(r NotificationRepo, s map[string]NotificationSender, c map[string]NotificationComposer,
+	cfg *config.NOTIFY, l *logger.Logger) *NotificationUseCase {
+	return &NotificationUseCase{
+		repo:      r,
+		senders:   s,
+		composers: c,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// GetPreferences usecase gets notification preferences of the user
+func (uc *NotificationUseCase) GetPreferences(ctx context.Context, userID int) (*entity.NotificationPreferences, error) {
+	prefs, err := uc.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences usecase sets channels the user wants to be notified via,
+// push token of the user's device is kept if the new one is not sent
+func (uc *NotificationUseCase) UpdatePreferences(ctx context.Context, userID int, req *dto.NotificationPreferencesBody) error {
+	prefs, err := uc.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	prefs.EmailEnabled = *req.EmailEnabled
+	prefs.SMSEnabled = *req.SMSEnabled
+	prefs.PushEnabled = *req.PushEnabled
+	if req.PushToken != "" {
+		prefs.PushToken = req.PushToken
+	}
+
+	err = uc.repo.SetPreferences(ctx, prefs)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// HandleEvent queues notification of the delivery's domain event to the participant it concerns:
+// client learns about courier's progress and courier learns about cancellation
+func (uc *NotificationUseCase) HandleEvent(ctx context.Context, e *entity.OutboxEvent) error {
+	event, ok := deliveryNotifications[e.Type]
+	if !ok || e.AggregateType != entity.AggregateDelivery {
+		return nil
+	}
+
+	notice, err := uc.repo.GetDeliveryNotice(ctx, e.AggregateID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	userID := notice.ClientID
+	if event == entity.NotifyDeliveryCancelled {
+		userID = notice.CourierID
+	}
+	if userID == 0 {
+		return nil
+	}
+
+	err = uc.enqueue(ctx, userID, event, notice)
+	if err != nil {
+		return err
+	}
+
+	if e.Type == entity.DeliveryAccepted {
+		return uc.sendPIN(ctx, notice)
+	}
+	return nil
+}
+
+// sendPIN queues SMS with the PIN to the dropoff contact so that the recipient can hand it
+// to the courier, the PIN is sent to the client via their channels if there is no contact
+func (uc *NotificationUseCase) sendPIN(ctx context.Context, notice *entity.DeliveryNotice) error {
+	if notice.PIN == "" {
+		return nil
+	}
+
+	phone, err := phonehelper.NormalizeE164(notice.DropoffPhone)
+	if err != nil {
+		return uc.enqueue(ctx, notice.ClientID, entity.NotifyDeliveryPIN, notice)
+	}
+
+	_, body, err := render(entity.NotifyDeliveryPIN, notice)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	n := &entity.Notification{
+		UserID:    notice.ClientID,
+		Event:     entity.NotifyDeliveryPIN,
+		Channel:   entity.ChannelSMS,
+		Recipient: phone,
+		Body:      body,
+	}
+	if err = uc.repo.CreateNotification(ctx, n); err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// NotifyUser queues notification of the event about the delivery to the user
+func (uc *NotificationUseCase) NotifyUser(ctx context.Context, userID, deliveryID int, event string) {
+	notice, err := uc.repo.GetDeliveryNotice(ctx, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return
+	}
+	uc.enqueue(ctx, userID, event, notice)
+}
+
+// enqueue renders the event's template and queues it for each channel the user has enabled
+func (uc *NotificationUseCase) enqueue(ctx context.Context, userID int, event string, notice *entity.DeliveryNotice) error {
+	subject, body, err := render(event, notice)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	recipient, err := uc.repo.GetRecipient(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	prefs := recipient.Preferences
+	addresses := map[string]string{}
+	if prefs.EmailEnabled && recipient.Email != "" {
+		addresses[entity.ChannelEmail] = recipient.Email
+	}
+	if prefs.SMSEnabled && recipient.PhoneNumber != "" {
+		addresses[entity.ChannelSMS] = recipient.PhoneNumber
+	}
+	if prefs.PushEnabled && prefs.PushToken != "" {
+		addresses[entity.ChannelPush] = prefs.PushToken
+	}
+
+	for channel, address := range addresses {
+		n := &entity.Notification{
+			UserID:    userID,
+			Event:     event,
+			Channel:   channel,
+			Recipient: address,
+			Subject:   subject,
+			Body:      body,
+		}
+		if err = uc.repo.CreateNotification(ctx, n); err != nil {
+			uc.appLogger.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// render renders subject and text of the event's notification
+func render(event string, notice *entity.DeliveryNotice) (string, string, error) {
+	tmpl, ok := notificationTemplates[event]
+	if !ok {
+		return "", "", fmt.Errorf("template of %s notification is not found", event)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, notice); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.body.Execute(&body, notice); err != nil {
+		return "", "", err
+	}
+	return subject.String(), body.String(), nil
+}
+
+// Run sends queued notifications until the context is done,
+// several workers may run at once since notifications are claimed
+func (uc *NotificationUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.sendPending(ctx)
+		}
+	}
+}
+
+// sendPending sends notifications due for sending and schedules retries of failed ones
+// with exponential backoff until attempts are exhausted
+func (uc *NotificationUseCase) sendPending(ctx context.Context) {
+	notifications, err := uc.repo.ClaimNotifications(ctx, notificationBatchSize, notificationLease)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return
+	}
+
+	for _, n := range notifications {
+		err = uc.send(ctx, n)
+		if err == nil {
+			now := time.Now()
+			n.Status = entity.NotificationSent
+			n.LastError = ""
+			n.SentAt = &now
+		} else {
+			n.LastError = err.Error()
+			if n.Attempts >= uc.cfg.MaxAttempts || errors.Is(err, ErrNotificationStale) {
+				n.Status = entity.NotificationFailed
+			} else {
+				n.NextAttemptAt = time.Now().Add(uc.cfg.RetryDelay << (n.Attempts - 1))
+			}
+		}
+
+		if err = uc.repo.UpdateNotification(ctx, n); err != nil {
+			uc.appLogger.Error(err)
+		}
+	}
+}
+
+// send sends the notification via sender of its channel, notifications
+// of events with composer are composed right before sending
+func (uc *NotificationUseCase) send(ctx context.Context, n *entity.Notification) error {
+	sender, ok := uc.senders[n.Channel]
+	if !ok {
+		return fmt.Errorf("sender of %s channel is not set", n.Channel)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationLease/2)
+	defer cancel()
+
+	if composer, ok := uc.composers[n.Event]; ok {
+		if err := composer.Compose(ctx, n); err != nil {
+			return err
+		}
+	}
+	return sender.Send(ctx, n)
+}
@@ -0,0 +1,454 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Errors of organizations' members, they are shown to users as is
+var (
+	ErrNotOrgMember    = errors.New("user is not a member of the organization")
+	ErrOrgForbidden    = errors.New("role in the organization doesn't allow this action")
+	ErrOwnOrgOwnership = errors.New("owners can't change their own role or leave the organization")
+)
+
+// Time invitations are valid for, limit of places in the organization's
+// address book and period of the spending report if it is not set
+var (
+	orgInvitationTTL      = 7 * 24 * time.Hour
+	maxOrganizationPlaces = 200
+	defaultSpendingPeriod = 30 * 24 * time.Hour
+)
+
+// OrganizationUseCase is a struct that provides all use cases of organizations,
+// their members, shared address book and reports. Invitations are queued as
+// notifications and their links are issued by Compose when they are sent
+type OrganizationUseCase struct {
+	repo      OrganizationRepo
+	users     UserRepo
+	geo       GeoWebAPI
+	queue     NotificationRepo
+	cfg       *config.AUTH
+	appLogger *logger.Logger
+}
+
+func NewOrganizationUseCase(r OrganizationRepo, u UserRepo, g GeoWebAPI, q NotificationRepo, cfg *config.AUTH, l *logger.Logger) *OrganizationUseCase {
+	return &OrganizationUseCase{
+		repo:      r,
+		users:     u,
+		geo:       g,
+		queue:     q,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// CreateOrganization usecase creates a new organization owned by the user
+func (uc *OrganizationUseCase) CreateOrganization(ctx context.Context, userID int, req *dto.OrganizationRequestBody) (*entity.Organization, error) {
+	org := organizationFromRequest(req)
+	err := uc.repo.CreateOrganization(ctx, org, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return org, nil
+}
+
+// GetOrganizations usecase gets organizations the user is a member of
+func (uc *OrganizationUseCase) GetOrganizations(ctx context.Context, userID int) ([]*entity.Organization, error) {
+	orgs, err := uc.repo.GetUserOrganizations(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// GetOrganization usecase gets the organization with the member's role in it
+func (uc *OrganizationUseCase) GetOrganization(ctx context.Context, userID, orgID int) (*entity.Organization, error) {
+	role, err := uc.memberRole(ctx, orgID, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	org, err := uc.repo.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	org.Role = role
+	return org, nil
+}
+
+// UpdateOrganization usecase updates name and billing details of the organization,
+// only owners are allowed to
+func (uc *OrganizationUseCase) UpdateOrganization(ctx context.Context, userID, orgID int, req *dto.OrganizationRequestBody) error {
+	if _, err := uc.memberRole(ctx, orgID, userID, entity.OrgRoleOwner); err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	org := organizationFromRequest(req)
+	org.ID = orgID
+	err := uc.repo.UpdateOrganization(ctx, org)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetMembers usecase gets members of the organization
+func (uc *OrganizationUseCase) GetMembers(ctx context.Context, userID, orgID int) ([]*entity.OrganizationMember, error) {
+	if _, err := uc.memberRole(ctx, orgID, userID); err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	members, err := uc.repo.GetMembers(ctx, orgID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return members, nil
+}
+
+// InviteMember usecase sends invitation to join the organization by email,
+// owners invite members of any role and managers invite only requesters
+func (uc *OrganizationUseCase) InviteMember(ctx context.Context, userID, orgID int, req *dto.OrganizationInviteRequestBody) error {
+	role, err := uc.memberRole(ctx, orgID, userID, entity.OrgRoleOwner, entity.OrgRoleManager)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if role == entity.OrgRoleManager && req.Role != entity.OrgRoleRequester {
+		return ErrOrgForbidden
+	}
+
+	// Secret of the invitation is issued only when its email is sent,
+	// the invitation replaces the pending one sent to the email before
+	secret, err := randomHex(32)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	inv := &entity.OrganizationInvitation{
+		OrganizationID: orgID,
+		Email:          strings.ToLower(req.Email),
+		Role:           req.Role,
+		TokenHash:      hashSecret(secret),
+		InvitedBy:      userID,
+		ExpiresAt:      time.Now().Add(orgInvitationTTL),
+	}
+	err = uc.repo.CreateInvitation(ctx, inv)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	// Invitee may have no account yet, so the email is queued on behalf of the inviter
+	err = uc.queue.CreateNotification(ctx, &entity.Notification{
+		UserID:    userID,
+		Event:     entity.NotifyOrgInvitation,
+		Channel:   entity.ChannelEmail,
+		Recipient: inv.Email,
+		RefID:     inv.ID,
+	})
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// Compose issues new secret of the invitation and writes the link with it to the email,
+// the secret is renewed on every attempt to send so that only its hash is stored
+func (uc *OrganizationUseCase) Compose(ctx context.Context, n *entity.Notification) error {
+	token, err := randomHex(32)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	inv, err := uc.repo.RenewInvitation(ctx, n.RefID, hashSecret(token))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if inv == nil {
+		return ErrNotificationStale
+	}
+
+	org, err := uc.repo.GetOrganizationByID(ctx, inv.OrganizationID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	n.Subject = "Invitation to " + org.Name
+	n.Body = fmt.Sprintf("You are invited to join %s on Truckly as %s. Accept the invitation by following the link:\n"+
+		"%s/organizations/accept?token=%s\n\nThe invitation is valid for %v.",
+		org.Name, inv.Role, uc.cfg.AppURL, token, time.Until(inv.ExpiresAt).Round(time.Minute))
+	return nil
+}
+
+// AcceptInvitation usecase adds the user to the organization by the invitation
+// sent to the user's email
+func (uc *OrganizationUseCase) AcceptInvitation(ctx context.Context, userID int, req *dto.OrganizationAcceptRequestBody) (*entity.Organization, error) {
+	user, err := uc.users.GetUserPrivateByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	orgID, err := uc.repo.AcceptInvitation(ctx, hashSecret(req.Token), userID, user.Email)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return uc.GetOrganization(ctx, userID, orgID)
+}
+
+// UpdateMemberRole usecase changes role of the member, only owners are allowed to
+// and they can't change their own role so that the organization keeps its owner
+func (uc *OrganizationUseCase) UpdateMemberRole(ctx context.Context, userID, orgID, memberID int, req *dto.OrganizationMemberRoleRequestBody) error {
+	if _, err := uc.memberRole(ctx, orgID, userID, entity.OrgRoleOwner); err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if userID == memberID {
+		return ErrOwnOrgOwnership
+	}
+
+	err := uc.repo.UpdateMemberRole(ctx, orgID, memberID, req.Role)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// RemoveMember usecase removes the member from the organization. Owners remove
+// anyone, managers remove requesters and any member except owners may leave
+func (uc *OrganizationUseCase) RemoveMember(ctx context.Context, userID, orgID, memberID int) error {
+	role, err := uc.memberRole(ctx, orgID, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	switch {
+	case userID == memberID:
+		if role == entity.OrgRoleOwner {
+			return ErrOwnOrgOwnership
+		}
+	case role == entity.OrgRoleOwner:
+	case role == entity.OrgRoleManager:
+		memberRole, err := uc.repo.GetMemberRole(ctx, orgID, memberID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return err
+		}
+		if memberRole != entity.OrgRoleRequester {
+			return ErrOrgForbidden
+		}
+	default:
+		return ErrOrgForbidden
+	}
+
+	err = uc.repo.DeleteMember(ctx, orgID, memberID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// CreatePlace usecase saves new address to the organization's address book
+func (uc *OrganizationUseCase) CreatePlace(ctx context.Context, userID, orgID int, req *dto.SavedPlaceRequestBody) (*entity.SavedPlace, error) {
+	if _, err := uc.memberRole(ctx, orgID, userID, entity.OrgRoleOwner, entity.OrgRoleManager); err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	cnt, err := uc.repo.CountOrganizationPlaces(ctx, orgID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if cnt >= maxOrganizationPlaces {
+		err = fmt.Errorf("too many saved places")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	place, err := placeFromRequest(uc.geo, req)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	place.OrganizationID = orgID
+
+	err = uc.repo.CreateOrganizationPlace(ctx, place)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return place, nil
+}
+
+// UpdatePlace usecase updates the address of the organization's address book
+func (uc *OrganizationUseCase) UpdatePlace(ctx context.Context, userID, orgID, placeID int, req *dto.SavedPlaceRequestBody) error {
+	if _, err := uc.memberRole(ctx, orgID, userID, entity.OrgRoleOwner, entity.OrgRoleManager); err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	place, err := placeFromRequest(uc.geo, req)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	place.ID = placeID
+	place.OrganizationID = orgID
+
+	err = uc.repo.UpdateOrganizationPlace(ctx, place)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeletePlace usecase deletes the address of the organization's address book
+func (uc *OrganizationUseCase) DeletePlace(ctx context.Context, userID, orgID, placeID int) error {
+	if _, err := uc.memberRole(ctx, orgID, userID, entity.OrgRoleOwner, entity.OrgRoleManager); err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err := uc.repo.DeleteOrganizationPlace(ctx, orgID, placeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetPlaces usecase gets the organization's address book shared by all members
+func (uc *OrganizationUseCase) GetPlaces(ctx context.Context, userID, orgID int) ([]*entity.SavedPlace, error) {
+	if _, err := uc.memberRole(ctx, orgID, userID); err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	places, err := uc.repo.GetOrganizationPlaces(ctx, orgID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return places, nil
+}
+
+// GetDeliveries usecase gets page of the organization's deliveries, owners
+// and managers see all of them and requesters see only their own ones
+func (uc *OrganizationUseCase) GetDeliveries(ctx context.Context, userID, orgID int, query *dto.OrganizationDeliveriesQuery) ([]*dto.DeliveryBriefResponse, error) {
+	role, err := uc.memberRole(ctx, orgID, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	clientID := 0
+	if role == entity.OrgRoleRequester {
+		clientID = userID
+	}
+
+	results, err := uc.repo.GetOrganizationDeliveries(ctx, orgID, clientID, query.Page)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetSpending usecase gets spending report of the organization per member,
+// the period includes both of its dates
+func (uc *OrganizationUseCase) GetSpending(ctx context.Context, userID, orgID int, query *dto.OrganizationSpendingQuery) (*dto.OrganizationSpendingResponse, error) {
+	if _, err := uc.memberRole(ctx, orgID, userID, entity.OrgRoleOwner, entity.OrgRoleManager); err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	to := time.Now()
+	if !query.To.IsZero() {
+		to = query.To.AddDate(0, 0, 1)
+	}
+	from := to.Add(-defaultSpendingPeriod)
+	if !query.From.IsZero() {
+		from = query.From
+	}
+	if !from.Before(to) {
+		err := fmt.Errorf("period is invalid")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	members, err := uc.repo.GetOrganizationSpending(ctx, orgID, from, to)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	report := &dto.OrganizationSpendingResponse{From: from, To: to, Members: members}
+	for _, m := range members {
+		report.Deliveries += m.Deliveries
+		report.Total += m.Total
+	}
+	return report, nil
+}
+
+// memberRole gets role of the user in the organization checking
+// that it is one of the allowed ones if they are given
+func (uc *OrganizationUseCase) memberRole(ctx context.Context, orgID, userID int, allowed ...string) (string, error) {
+	role, err := uc.repo.GetMemberRole(ctx, orgID, userID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", ErrNotOrgMember
+	}
+	if len(allowed) == 0 {
+		return role, nil
+	}
+
+	for _, r := range allowed {
+		if r == role {
+			return role, nil
+		}
+	}
+	return "", ErrOrgForbidden
+}
+
+// organizationFromRequest converts request body to the organization
+func organizationFromRequest(req *dto.OrganizationRequestBody) *entity.Organization {
+	return &entity.Organization{
+		Name:           req.Name,
+		LegalName:      req.LegalName,
+		TaxID:          req.TaxID,
+		BillingEmail:   req.BillingEmail,
+		BillingAddress: req.BillingAddress,
+	}
+}
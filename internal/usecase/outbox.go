@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Interval of deleting published events older than retention and time
+// claimed events are hidden from other instances while being published
+var (
+	outboxCleanupInterval = time.Hour
+	outboxLease           = time.Minute
+)
+
+// OutboxUseCase is a struct that provides relaying of domain events
+// written to the outbox along with the changes to the event bus
+type OutboxUseCase struct {
+	repo      OutboxRepo
+	bus       EventBus
+	cfg       *config.OUTBOX
+	appLogger *logger.Logger
+}
+
+func NewOutboxUseCase(r OutboxRepo, b EventBus, cfg *config.OUTBOX, l *logger.Logger) *OutboxUseCase {
+	return &OutboxUseCase{
+		repo:      r,
+		bus:       b,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// Run relays events to the bus until the context is done, events are marked
+// as published only after the bus accepts them so that they are delivered at least once.
+// Several instances may relay at once since events are claimed
+func (uc *OutboxUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.cfg.PollInterval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(outboxCleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.relay(ctx)
+		case <-cleanup.C:
+			uc.cleanup(ctx)
+		}
+	}
+}
+
+// relay publishes batches of events while there are events due for publishing
+func (uc *OutboxUseCase) relay(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := uc.repo.ClaimOutboxEvents(ctx, uc.cfg.BatchSize, outboxLease)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return
+		}
+		if len(events) == 0 {
+			return
+		}
+
+		for _, event := range events {
+			uc.publish(ctx, event)
+		}
+	}
+}
+
+// publish passes the event to the bus, failed event is published again with
+// exponential backoff and is set aside as dead once attempts are exhausted
+func (uc *OutboxUseCase) publish(ctx context.Context, event *entity.OutboxEvent) {
+	err := uc.bus.Publish(ctx, event)
+	if err == nil {
+		if err = uc.repo.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+			uc.appLogger.Error(err)
+		}
+		return
+	}
+
+	uc.appLogger.Error(err)
+	dead := event.Attempts >= uc.cfg.MaxAttempts
+	if dead {
+		uc.appLogger.Infof("outbox: event %v is dead after %v attempts", event.ID, event.Attempts)
+	}
+
+	nextAttemptAt := time.Now().Add(uc.cfg.RetryDelay << (event.Attempts - 1))
+	if err = uc.repo.FailOutboxEvent(ctx, event, err.Error(), nextAttemptAt, dead); err != nil {
+		uc.appLogger.Error(err)
+	}
+}
+
+// cleanup deletes events published before the retention period
+func (uc *OutboxUseCase) cleanup(ctx context.Context) {
+	n, err := uc.repo.DeletePublishedOutboxEvents(ctx, time.Now().Add(-uc.cfg.Retention))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return
+	}
+	if n != 0 {
+		uc.appLogger.Infof("deleted %d published outbox events", n)
+	}
+}
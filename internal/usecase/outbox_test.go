@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// outboxRepoStub records results of publishing instead of saving them
+type outboxRepoStub struct {
+	OutboxRepo
+	published     []int
+	failed        []int
+	dead          []int
+	nextAttemptAt time.Time
+}
+
+func (r *outboxRepoStub) MarkOutboxEventPublished(ctx context.Context, id int) error {
+	r.published = append(r.published, id)
+	return nil
+}
+
+func (r *outboxRepoStub) FailOutboxEvent(ctx context.Context, event *entity.OutboxEvent, lastError string, nextAttemptAt time.Time, dead bool) error {
+	r.failed = append(r.failed, event.ID)
+	if dead {
+		r.dead = append(r.dead, event.ID)
+	}
+	r.nextAttemptAt = nextAttemptAt
+	return nil
+}
+
+// busStub fails publishing of the events with the ids
+type busStub struct {
+	EventBus
+	failing map[int]bool
+}
+
+func (b *busStub) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	if b.failing[event.ID] {
+		return errors.New("bus is unavailable")
+	}
+	return nil
+}
+
+func TestOutboxUseCase_publish(t *testing.T) {
+	testLogger := logrus.New()
+	cfg := &config.OUTBOX{MaxAttempts: 5, RetryDelay: time.Second}
+
+	tests := []struct {
+		name          string
+		event         *entity.OutboxEvent
+		failing       bool
+		wantPublished bool
+		wantDead      bool
+		wantDelay     time.Duration
+	}{
+		{
+			name:          "published event is marked",
+			event:         &entity.OutboxEvent{ID: 1, Attempts: 1},
+			wantPublished: true,
+		},
+		{
+			name:      "first failure is retried after the retry delay",
+			event:     &entity.OutboxEvent{ID: 2, Attempts: 1},
+			failing:   true,
+			wantDelay: time.Second,
+		},
+		{
+			name:      "delay is doubled for each next failure",
+			event:     &entity.OutboxEvent{ID: 3, Attempts: 4},
+			failing:   true,
+			wantDelay: 8 * time.Second,
+		},
+		{
+			name:      "event is dead once attempts are exhausted",
+			event:     &entity.OutboxEvent{ID: 4, Attempts: 5},
+			failing:   true,
+			wantDead:  true,
+			wantDelay: 16 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &outboxRepoStub{}
+			bus := &busStub{failing: map[int]bool{tt.event.ID: tt.failing}}
+			uc := NewOutboxUseCase(repo, bus, cfg, logger.New(testLogger))
+
+			before := time.Now()
+			uc.publish(context.Background(), tt.event)
+
+			if tt.wantPublished {
+				require.Equal(t, []int{tt.event.ID}, repo.published)
+				require.Empty(t, repo.failed)
+				return
+			}
+			require.Empty(t, repo.published)
+			require.Equal(t, []int{tt.event.ID}, repo.failed)
+			require.Equal(t, tt.wantDead, len(repo.dead) == 1)
+			require.WithinDuration(t, before.Add(tt.wantDelay), repo.nextAttemptAt, time.Second)
+		})
+	}
+}
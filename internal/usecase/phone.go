@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/dacore-x/truckly/pkg/phonehelper"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Errors of the phone number verification, they are shown to users as is
+var (
+	ErrPhoneAlreadyVerified = errors.New("phone number is already verified")
+	ErrCodeCooldown         = errors.New("code has been sent recently, try again later")
+	ErrCodeSendsExceeded    = errors.New("limit of codes for today is reached")
+	ErrInvalidCode          = errors.New("code is invalid or expired")
+	ErrCodeAttemptsExceeded = errors.New("too many attempts, request new code")
+)
+
+// PhoneUseCase is a struct that provides all use cases of users' phone numbers
+// verification by one-time codes sent in SMS
+type PhoneUseCase struct {
+	users     UserRepo
+	repo      AccountRepo
+	store     PhoneCodeStore
+	sms       SMSSender
+	cfg       *config.OTP
+	appLogger *logger.Logger
+}
+
+func NewPhoneUseCase(u UserRepo, r AccountRepo, s PhoneCodeStore, sms SMSSender, cfg *config.OTP, l *logger.Logger) *PhoneUseCase {
+	return &PhoneUseCase{
+		users:     u,
+		repo:      r,
+		store:     s,
+		sms:       sms,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// SendCode usecase sends new code to the user's phone number, codes are sent
+// not more often than the cool-down allows and not more than the daily limit
+func (uc *PhoneUseCase) SendCode(ctx context.Context, userID int) error {
+	user, err := uc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if user.Meta.IsPhoneVerified {
+		return ErrPhoneAlreadyVerified
+	}
+
+	// Numbers stored before validation was introduced are normalized here
+	phone, err := phonehelper.NormalizeE164(user.PhoneNumber)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	started, err := uc.store.StartCooldown(ctx, userID, uc.cfg.Cooldown)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if !started {
+		return ErrCodeCooldown
+	}
+
+	sends, err := uc.store.CountSend(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if sends > uc.cfg.MaxSends {
+		return ErrCodeSendsExceeded
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = uc.store.SaveCode(ctx, &entity.PhoneCode{
+		UserID:   userID,
+		Phone:    phone,
+		CodeHash: hashSecret(code),
+	}, uc.cfg.CodeTTL)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = uc.sms.SendSMS(ctx, phone, fmt.Sprintf("Truckly verification code: %s", code))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// VerifyCode usecase checks the code sent to the user and marks the phone number as verified,
+// the code is dropped after too many wrong attempts
+func (uc *PhoneUseCase) VerifyCode(ctx context.Context, userID int, req *dto.PhoneVerifyRequestBody) error {
+	code, err := uc.store.UseAttempt(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if code == nil {
+		return ErrInvalidCode
+	}
+
+	if code.Attempts > uc.cfg.MaxAttempts {
+		if err = uc.store.DeleteCode(ctx, userID); err != nil {
+			uc.appLogger.Error(err)
+			return err
+		}
+		return ErrCodeAttemptsExceeded
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(req.Code)), []byte(code.CodeHash)) != 1 {
+		return ErrInvalidCode
+	}
+
+	err = uc.repo.VerifyPhone(ctx, userID, code.Phone)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = uc.store.DeleteCode(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// generateOTP generates one-time 6-digit code of the phone number
+func generateOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Limit of places in the user's address book
+var maxSavedPlaces = 50
+
+// SavedPlaceUseCase is a struct that provides all use cases of user's address book
+type SavedPlaceUseCase struct {
+	repo      SavedPlaceRepo
+	geo       GeoWebAPI
+	appLogger *logger.Logger
+}
+
+func NewSavedPlaceUseCase(r SavedPlaceRepo, g GeoWebAPI, l *logger.Logger) *SavedPlaceUseCase {
+	return &SavedPlaceUseCase{repo: r, geo: g, appLogger: l}
+}
+
+// CreatePlace usecase saves new address to the user's address book
+func (uc *SavedPlaceUseCase) CreatePlace(ctx context.Context, userID int, req *dto.SavedPlaceRequestBody) (*entity.SavedPlace, error) {
+	cnt, err := uc.repo.CountPlaces(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if cnt >= maxSavedPlaces {
+		err = fmt.Errorf("too many saved places")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	place, err := placeFromRequest(uc.geo, req)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	place.UserID = userID
+
+	err = uc.repo.CreatePlace(ctx, place)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return place, nil
+}
+
+// UpdatePlace usecase updates the address saved by the user
+func (uc *SavedPlaceUseCase) UpdatePlace(ctx context.Context, userID, placeID int, req *dto.SavedPlaceRequestBody) error {
+	place, err := placeFromRequest(uc.geo, req)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	place.ID = placeID
+	place.UserID = userID
+
+	err = uc.repo.UpdatePlace(ctx, place)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeletePlace usecase deletes the address saved by the user
+func (uc *SavedPlaceUseCase) DeletePlace(ctx context.Context, userID, placeID int) error {
+	err := uc.repo.DeletePlace(ctx, userID, placeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetPlaces usecase gets the user's address book
+func (uc *SavedPlaceUseCase) GetPlaces(ctx context.Context, userID int) ([]*entity.SavedPlace, error) {
+	places, err := uc.repo.GetPlaces(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return places, nil
+}
+
+// placeFromRequest converts request body to the place
+// resolving its object by the point if the user hasn't set it
+func placeFromRequest(geo GeoWebAPI, req *dto.SavedPlaceRequestBody) (*entity.SavedPlace, error) {
+	place := &entity.SavedPlace{
+		Label:     req.Label,
+		Latitude:  req.Point.Lat,
+		Longitude: req.Point.Lon,
+		Object:    req.Object,
+		Entrance:  req.Entrance,
+		Floor:     req.Floor,
+		Notes:     req.Notes,
+	}
+
+	if place.Object == "" {
+		object, err := geo.GetObjectByCoords(place.Latitude, place.Longitude)
+		if err != nil {
+			return nil, fmt.Errorf("error getting geo object")
+		}
+		place.Object = object
+	}
+	return place, nil
+}
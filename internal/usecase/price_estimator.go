@@ -7,48 +7,86 @@ import (
 	"github.com/dacore-x/truckly/pkg/logger"
 
 	"github.com/dacore-x/truckly/internal/dto"
-	"time"
 )
 
 // PriceEstimatorUseCase is a struct that provides all use cases for estimating delivery prices
 type PriceEstimatorUseCase struct {
 	service   PriceEstimatorService
 	geo       GeoWebAPI
+	zones     CoverageChecker
+	cities    CityRepo
+	places    SavedPlaceRepo
 	appLogger *logger.Logger
 }
 
-func NewPriceEstimatorUseCase(s PriceEstimatorService, g GeoWebAPI, l *logger.Logger) *PriceEstimatorUseCase {
+func NewPriceEstimatorUseCase(s PriceEstimatorService, g GeoWebAPI, z CoverageChecker, c CityRepo, sp SavedPlaceRepo, l *logger.Logger) *PriceEstimatorUseCase {
 	return &PriceEstimatorUseCase{
 		geo:       g,
 		service:   s,
+		zones:     z,
+		cities:    c,
+		places:    sp,
 		appLogger: l,
 	}
 }
 
-// EstimateDeliveryPrice usecase estimates delivery price
-func (uc *PriceEstimatorUseCase) EstimateDeliveryPrice(ctx context.Context, req *dto.EstimatePriceRequestBody) (float64, error) {
+// EstimateDeliveryPrice usecase estimates delivery price in the city's currency
+func (uc *PriceEstimatorUseCase) EstimateDeliveryPrice(ctx context.Context, req *dto.EstimatePriceRequestBody) (*dto.EstimatePriceResponse, error) {
 	if req.TypeID < 1 || req.TypeID > 5 {
 		err := errors.New("incorrect type id")
 		uc.appLogger.Error(err)
-		return 0, err
+		return nil, err
+	}
+
+	city, err := servingCity(ctx, uc.cities, req.CityID, req.TypeID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if req.FromSavedPlaceID != 0 {
+		place, err := uc.places.GetPlaceByID(ctx, req.UserID, req.FromSavedPlaceID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		req.FromPoint = &dto.PointRequest{Lat: place.Latitude, Lon: place.Longitude}
+	}
+	if req.ToSavedPlaceID != 0 {
+		place, err := uc.places.GetPlaceByID(ctx, req.UserID, req.ToSavedPlaceID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		req.ToPoint = &dto.PointRequest{Lat: place.Latitude, Lon: place.Longitude}
+	}
+
+	zone, err := uc.zones.CheckCoverage(ctx, req.TypeID, req.FromPoint, req.ToPoint)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
 	}
 
 	distance, err := uc.geo.GetDistanceBetweenPoints(req.FromPoint.Lat, req.FromPoint.Lon, req.ToPoint.Lat, req.ToPoint.Lon)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	body := &dto.EstimatePriceInternalRequestBody{
 		TypeID:    req.TypeID,
 		HasLoader: req.HasLoader,
-		Time:      time.Now(),
+		Time:      city.Now(),
 		Distance:  distance, // in m
 	}
 	price, err := uc.service.EstimateDeliveryPrice(body)
 	if err != nil {
 		uc.appLogger.Error(err)
-		return 0, err
+		return nil, err
 	}
 
-	return price, nil
+	price *= city.TariffMultiplier
+	if zone != nil {
+		price *= zone.TariffMultiplier
+	}
+	return &dto.EstimatePriceResponse{Price: price, Currency: city.Currency}, nil
 }
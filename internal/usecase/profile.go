@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/dacore-x/truckly/pkg/phonehelper"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Errors of the profile changes, they are shown to users as is
+var (
+	ErrEmptyProfileField = errors.New("profile fields can't be empty")
+	ErrEmailTaken        = errors.New("user with this email already exists")
+	ErrNoAvatar          = errors.New("avatar is not uploaded")
+	ErrUserNotCreatedYet = errors.New("user did not exist at the time")
+)
+
+// Limit of the avatar's size in bytes
+var maxAvatarSize int64 = 2 << 20
+
+// ProfileUseCase is a struct that provides all use cases of changes
+// of users' profiles and their history. Every change is recorded,
+// new email is set only after it is confirmed by the link sent to it.
+// Emails are queued as notifications the same way as account emails
+type ProfileUseCase struct {
+	users     UserRepo
+	repo      ProfileRepo
+	accounts  AccountRepo
+	cities    CityRepo
+	codes     PhoneCodeStore
+	store     BlobStore
+	queue     NotificationRepo
+	cfg       *config.AUTH
+	appLogger *logger.Logger
+}
+
+func NewProfileUseCase(u UserRepo, r ProfileRepo, a AccountRepo, c CityRepo, pc PhoneCodeStore, s BlobStore,
+	q NotificationRepo, cfg *config.AUTH, l *logger.Logger) *ProfileUseCase {
+	return &ProfileUseCase{
+		users:     u,
+		repo:      r,
+		accounts:  a,
+		cities:    c,
+		codes:     pc,
+		store:     s,
+		queue:     q,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// UpdateProfile usecase changes fields of the user's profile set in the request.
+// Changed phone number has to be verified again, new email is sent the confirmation link.
+// Everything that may fail is done before the changes are saved
+func (uc *ProfileUseCase) UpdateProfile(ctx context.Context, userID int, req *dto.UserUpdateRequestBody) (*dto.UserUpdateResponse, error) {
+	user, err := uc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for field, value := range map[string]*string{
+		entity.FieldSurname: req.Surname,
+		entity.FieldName:    req.Name,
+	} {
+		if value == nil {
+			continue
+		}
+		values[field] = strings.TrimSpace(*value)
+		if values[field] == "" {
+			return nil, ErrEmptyProfileField
+		}
+	}
+
+	if req.PhoneNumber != nil {
+		phone, err := phonehelper.NormalizeE164(*req.PhoneNumber)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		values[entity.FieldPhoneNumber] = phone
+	}
+
+	if req.CityID != nil {
+		_, err = uc.cities.GetCityByID(ctx, *req.CityID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		values[entity.FieldCityID] = strconv.Itoa(*req.CityID)
+	}
+
+	var newEmail string
+	var tokenID int
+	if req.Email != nil && strings.TrimSpace(*req.Email) != user.Email {
+		newEmail = strings.TrimSpace(*req.Email)
+		record, _ := uc.users.GetUserPrivateByEmail(ctx, newEmail)
+		if record != nil {
+			return nil, ErrEmailTaken
+		}
+
+		token := &entity.UserToken{
+			UserID:    userID,
+			Purpose:   entity.TokenEmailChange,
+			ExpiresAt: time.Now().Add(uc.cfg.VerifyTTL),
+			Email:     newEmail,
+		}
+		err = createUserToken(ctx, uc.accounts, token)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		tokenID = token.ID
+	}
+
+	// Code sent to the old phone number must not verify the new one
+	if phone, ok := values[entity.FieldPhoneNumber]; ok && phone != user.PhoneNumber {
+		err = uc.codes.DeleteCode(ctx, userID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+	}
+
+	if len(values) != 0 {
+		_, err = uc.repo.UpdateProfile(ctx, userID, userID, values)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+	}
+
+	// Changes are already saved, so failure to queue the link is only reported
+	// as email change not pending and the user may request it again
+	if newEmail != "" {
+		err = queueTokenMail(ctx, uc.queue, userID, newEmail, entity.NotifyEmailChange, tokenID)
+		if err != nil {
+			uc.appLogger.Error(err)
+			newEmail = ""
+		}
+	}
+
+	user, err = uc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return &dto.UserUpdateResponse{
+		User:               user,
+		EmailChangePending: newEmail != "",
+	}, nil
+}
+
+// ConfirmEmailChange usecase sets the new email confirmed by the link
+// and notifies the old email about the change
+func (uc *ProfileUseCase) ConfirmEmailChange(ctx context.Context, req *dto.EmailChangeRequestBody) error {
+	change, err := uc.accounts.ChangeEmail(ctx, hashSecret(req.Token))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	// Email is already changed, so failed notification is only logged
+	err = uc.queue.CreateNotification(ctx, &entity.Notification{
+		UserID:    change.UserID,
+		Event:     entity.NotifyEmailChanged,
+		Channel:   entity.ChannelEmail,
+		Recipient: change.OldValue,
+		Subject:   "Your email has been changed",
+		Body: fmt.Sprintf("Email of your account has been changed to %s.\n\n"+
+			"If you did not change it, contact the support.", change.NewValue),
+	})
+	if err != nil {
+		uc.appLogger.Error(err)
+	}
+	return nil
+}
+
+// UploadAvatar usecase stores new avatar of the user, previous avatars
+// are kept in the store since they are referenced by the profile's history
+func (uc *ProfileUseCase) UploadAvatar(ctx context.Context, userID int, r io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(r, maxAvatarSize+1))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if int64(len(data)) > maxAvatarSize {
+		err = fmt.Errorf("file is too large")
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	ext, ok := proofFileTypes[http.DetectContentType(data)]
+	if !ok {
+		err = fmt.Errorf("file must be jpeg or png image")
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	name, err := randomHex(8)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	key := fmt.Sprintf("avatars/%d/%s%s", userID, name, ext)
+	err = uc.store.Put(ctx, key, bytes.NewReader(data))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	_, err = uc.repo.UpdateProfile(ctx, userID, userID, map[string]string{entity.FieldAvatar: key})
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetAvatar usecase opens current avatar of the user, caller must close the file
+func (uc *ProfileUseCase) GetAvatar(ctx context.Context, userID int) (io.ReadCloser, error) {
+	user, err := uc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if user.AvatarKey == "" {
+		return nil, ErrNoAvatar
+	}
+
+	f, err := uc.store.Get(ctx, user.AvatarKey)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return f, nil
+}
+
+// GetHistory usecase returns all changes of the user's profile, the latest go first.
+// If the time is set, the profile as it was at that time is restored from the changes
+func (uc *ProfileUseCase) GetHistory(ctx context.Context, userID int, at time.Time) ([]*entity.UserChange, *dto.UserSnapshot, error) {
+	user, err := uc.users.GetUserByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, nil, err
+	}
+
+	changes, err := uc.repo.GetUserChanges(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, nil, err
+	}
+
+	if at.IsZero() {
+		return changes, nil, nil
+	}
+	if at.Before(user.CreatedAt) {
+		return nil, nil, ErrUserNotCreatedYet
+	}
+
+	values := map[string]string{
+		entity.FieldSurname:     user.Surname,
+		entity.FieldName:        user.Name,
+		entity.FieldEmail:       user.Email,
+		entity.FieldPhoneNumber: user.PhoneNumber,
+		entity.FieldCityID:      strconv.Itoa(user.Meta.CityID),
+		entity.FieldAvatar:      user.AvatarKey,
+	}
+	// Changes made after the time are reverted starting from the latest one
+	for _, change := range changes {
+		if !change.ChangedAt.After(at) {
+			break
+		}
+		values[change.Field] = change.OldValue
+	}
+
+	cityID, err := strconv.Atoi(values[entity.FieldCityID])
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, nil, err
+	}
+
+	return changes, &dto.UserSnapshot{
+		At:          at,
+		Surname:     values[entity.FieldSurname],
+		Name:        values[entity.FieldName],
+		Email:       values[entity.FieldEmail],
+		PhoneNumber: values[entity.FieldPhoneNumber],
+		CityID:      cityID,
+		AvatarKey:   values[entity.FieldAvatar],
+	}, nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// profileUserStub returns the user with old email, no other user has the new one
+type profileUserStub struct {
+	UserRepo
+}
+
+func (u *profileUserStub) GetUserByID(ctx context.Context, id int) (*dto.UserMeResponse, error) {
+	return &dto.UserMeResponse{ID: id, Name: "Ivan", Email: "old@example.com"}, nil
+}
+
+func (u *profileUserStub) GetUserPrivateByEmail(ctx context.Context, email string) (*dto.UserInfoResponse, error) {
+	return nil, errors.New("user is not found")
+}
+
+// profileRepoStub records changed values of the profile
+type profileRepoStub struct {
+	ProfileRepo
+	values map[string]string
+}
+
+func (r *profileRepoStub) UpdateProfile(ctx context.Context, userID, changedBy int, values map[string]string) ([]*entity.UserChange, error) {
+	r.values = values
+	return []*entity.UserChange{}, nil
+}
+
+func TestProfileUseCase_UpdateProfile(t *testing.T) {
+	testLogger := logrus.New()
+	cfg := &config.AUTH{VerifyTTL: time.Hour, AppURL: "https://truckly.example"}
+	name, email := "Petr", "new@example.com"
+	req := &dto.UserUpdateRequestBody{Name: &name, Email: &email}
+
+	t.Run("link to the new email is queued", func(t *testing.T) {
+		tokens := &tokenRepoStub{}
+		queue := &notificationQueueStub{}
+		repo := &profileRepoStub{}
+		uc := NewProfileUseCase(&profileUserStub{}, repo, tokens, nil, nil, nil, queue, cfg, logger.New(testLogger))
+
+		resp, err := uc.UpdateProfile(context.Background(), 3, req)
+		require.NoError(t, err)
+		require.True(t, resp.EmailChangePending)
+		require.Equal(t, "Petr", repo.values[entity.FieldName])
+
+		require.Len(t, tokens.tokens, 1)
+		require.Equal(t, entity.TokenEmailChange, tokens.tokens[0].Purpose)
+		require.Equal(t, email, tokens.tokens[0].Email)
+
+		require.Len(t, queue.queued, 1)
+		require.Equal(t, entity.NotifyEmailChange, queue.queued[0].Event)
+		require.Equal(t, email, queue.queued[0].Recipient)
+		require.Equal(t, tokens.tokens[0].ID, queue.queued[0].RefID)
+		require.Empty(t, queue.queued[0].Body)
+	})
+
+	t.Run("failure to queue the link doesn't fail saved changes", func(t *testing.T) {
+		queue := &notificationQueueStub{err: errors.New("database is unavailable")}
+		repo := &profileRepoStub{}
+		uc := NewProfileUseCase(&profileUserStub{}, repo, &tokenRepoStub{}, nil, nil, nil, queue, cfg, logger.New(testLogger))
+
+		resp, err := uc.UpdateProfile(context.Background(), 3, req)
+		require.NoError(t, err)
+		require.False(t, resp.EmailChangePending)
+		require.Equal(t, "Petr", repo.values[entity.FieldName])
+	})
+}
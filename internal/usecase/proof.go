@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/geohelper"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ErrInvalidPIN is returned when the courier enters wrong recipient's PIN
+var ErrInvalidPIN = errors.New("invalid pin")
+
+// Extensions of the images accepted as photo or signature
+var proofFileTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// ProofUseCase is a struct that provides all use cases of proof of delivery
+type ProofUseCase struct {
+	repo       ProofRepo
+	deliveries DeliveryRepo
+	timeline   TimelineRepo
+	store      BlobStore
+	cfg        *config.PROOF
+	appLogger  *logger.Logger
+}
+
+func NewProofUseCase(r ProofRepo, d DeliveryRepo, t TimelineRepo, s BlobStore, cfg *config.PROOF, l *logger.Logger) *ProofUseCase {
+	return &ProofUseCase{
+		repo:       r,
+		deliveries: d,
+		timeline:   t,
+		store:      s,
+		cfg:        cfg,
+		appLogger:  l,
+	}
+}
+
+// UploadProofFile usecase stores photo of the handed over cargo
+// or recipient's signature uploaded by the courier performing the delivery
+func (uc *ProofUseCase) UploadProofFile(ctx context.Context, courierID, deliveryID int, kind string, r io.Reader) error {
+	err := uc.checkPerformer(ctx, courierID, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, uc.cfg.MaxFileSize+1))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if int64(len(data)) > uc.cfg.MaxFileSize {
+		err = fmt.Errorf("file is too large")
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	ext, ok := proofFileTypes[http.DetectContentType(data)]
+	if !ok {
+		err = fmt.Errorf("file must be jpeg or png image")
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	key := fmt.Sprintf("proofs/%d/%s%s", deliveryID, kind, ext)
+	err = uc.store.Put(ctx, key, bytes.NewReader(data))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	err = uc.repo.SetProofFile(ctx, deliveryID, kind, key)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	eventType := entity.EventPhotoUploaded
+	if kind == entity.ProofSignature {
+		eventType = entity.EventSignatureUploaded
+	}
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, courierID, eventType, map[string]string{"key": key})
+	return nil
+}
+
+// GetProofFile usecase opens photo or signature of the delivery
+// for its client, courier or staff reading any delivery, caller must close the file
+func (uc *ProofUseCase) GetProofFile(ctx context.Context, userID, deliveryID int, kind string, readAny bool) (io.ReadCloser, error) {
+	// Delivery is returned only to the users who have access to it
+	_, err := uc.deliveries.GetDeliveryByID(ctx, userID, deliveryID, readAny)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	proof, err := uc.repo.GetProof(ctx, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	key := proof.PhotoKey
+	if kind == entity.ProofSignature {
+		key = proof.SignatureKey
+	}
+	if key == "" {
+		err = fmt.Errorf("file is not uploaded")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	f, err := uc.store.Get(ctx, key)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return f, nil
+}
+
+// CompleteDelivery usecase completes active delivery if the courier has entered
+// recipient's PIN being within tolerance of the dropoff point
+func (uc *ProofUseCase) CompleteDelivery(ctx context.Context, courierID, deliveryID int, req *dto.ProofCompleteBody) error {
+	err := uc.checkPerformer(ctx, courierID, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	delivery, err := uc.deliveries.GetDeliveryByID(ctx, courierID, deliveryID, false)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if delivery.StatusID != 2 {
+		err = fmt.Errorf("delivery is not active")
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	// Position is checked first so that attempts aren't spent far from the recipient
+	distance := geohelper.Haversine(req.Point.Lat, req.Point.Lon, delivery.ToObject.Latitude, delivery.ToObject.Longitude)
+	if distance > uc.cfg.GeoTolerance {
+		err = fmt.Errorf("courier is too far from dropoff point")
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	// PIN is short so attempts are limited, support resolves blocked deliveries
+	pin, err := uc.repo.UsePINAttempt(ctx, deliveryID, uc.cfg.MaxPINAttempts)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(req.PIN), []byte(pin)) != 1 {
+		addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, courierID, entity.EventPINFailed, nil)
+		uc.appLogger.Error(ErrInvalidPIN)
+		return ErrInvalidPIN
+	}
+
+	proof, err := uc.repo.GetProof(ctx, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	proof.Latitude = req.Point.Lat
+	proof.Longitude = req.Point.Lon
+	proof.Distance = distance
+	err = uc.repo.CompleteDelivery(ctx, proof)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, courierID, entity.EventCompleted, proof)
+	return nil
+}
+
+// checkPerformer checks that the courier performs the delivery
+func (uc *ProofUseCase) checkPerformer(ctx context.Context, courierID, deliveryID int) error {
+	ok, err := uc.deliveries.IsDeliveryPerformer(ctx, courierID, deliveryID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("user is not delivery performer")
+	}
+	return nil
+}
+
+// generatePIN generates one-time 4-digit code of the recipient
+func generatePIN() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(10000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%04d", n.Int64()), nil
+}
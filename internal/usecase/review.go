@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Rating is a weighted average of the latest scores where weight of each
+// older score decreases by ratingDecay, default rating is added as prior
+// with ratingPriorWeight so that a few first scores don't swing it too much
+var (
+	ratingWindow      = 100
+	ratingDecay       = 0.97
+	ratingPriorWeight = 3.
+	defaultRating     = 5.
+)
+
+// ReviewUseCase is a struct that provides all use cases of ratings and reviews
+type ReviewUseCase struct {
+	repo       ReviewRepo
+	deliveries DeliveryRepo
+	timeline   TimelineRepo
+	appLogger  *logger.Logger
+}
+
+func NewReviewUseCase(r ReviewRepo, d DeliveryRepo, t TimelineRepo, l *logger.Logger) *ReviewUseCase {
+	return &ReviewUseCase{
+		repo:       r,
+		deliveries: d,
+		timeline:   t,
+		appLogger:  l,
+	}
+}
+
+// CreateReview usecase rates the other participant of the completed delivery
+// and recomputes their rating
+func (uc *ReviewUseCase) CreateReview(ctx context.Context, authorID, deliveryID int, req *dto.ReviewRequestBody) (*entity.Review, error) {
+	delivery, err := uc.deliveries.GetDeliveryByID(ctx, authorID, deliveryID, false)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if delivery.StatusID != 3 {
+		err = fmt.Errorf("only completed delivery can be rated")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	review := &entity.Review{
+		DeliveryID: deliveryID,
+		AuthorID:   authorID,
+		Score:      req.Score,
+		Comment:    req.Comment,
+		Tags:       req.Tags,
+	}
+	if review.Tags == nil {
+		review.Tags = []string{}
+	}
+
+	switch authorID {
+	case delivery.ClientID:
+		review.AuthorRole = entity.ReviewByClient
+		review.TargetID = delivery.CourierID
+	case delivery.CourierID:
+		review.AuthorRole = entity.ReviewByCourier
+		review.TargetID = delivery.ClientID
+	default:
+		err = fmt.Errorf("user is not delivery participant")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.repo.CreateReview(ctx, review)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.updateRating(ctx, review.TargetID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	addEvent(ctx, uc.timeline, uc.appLogger, deliveryID, authorID, entity.EventReviewed, map[string]interface{}{
+		"author_role": review.AuthorRole,
+		"score":       review.Score,
+	})
+	return review, nil
+}
+
+// GetUserReviews usecase gets page of reviews given to the user,
+// texts hidden by moderators aren't shown
+func (uc *ReviewUseCase) GetUserReviews(ctx context.Context, userID, page int) ([]*entity.Review, error) {
+	reviews, err := uc.repo.GetReviewsByTargetID(ctx, userID, page)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	for _, r := range reviews {
+		if r.IsCommentHidden {
+			r.Comment = ""
+		}
+	}
+	return reviews, nil
+}
+
+// GetReviews usecase gets page of all reviews for moderation
+func (uc *ReviewUseCase) GetReviews(ctx context.Context, page int) ([]*entity.Review, error) {
+	reviews, err := uc.repo.GetReviews(ctx, page)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ModerateReview usecase hides or shows text of the review,
+// score of the review still counts in the rating
+func (uc *ReviewUseCase) ModerateReview(ctx context.Context, reviewID int, hidden bool) error {
+	err := uc.repo.SetCommentHidden(ctx, reviewID, hidden)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// updateRating recomputes the user's rating by the latest scores
+func (uc *ReviewUseCase) updateRating(ctx context.Context, userID int) error {
+	scores, err := uc.repo.GetRecentScores(ctx, userID, ratingWindow)
+	if err != nil {
+		return err
+	}
+	return uc.repo.SetRating(ctx, userID, weightedRating(scores))
+}
+
+// weightedRating computes rating by scores ordered from the newest one
+func weightedRating(scores []int) float64 {
+	sum := defaultRating * ratingPriorWeight
+	weights := ratingPriorWeight
+
+	w := 1.
+	for _, s := range scores {
+		sum += float64(s) * w
+		weights += w
+		w *= ratingDecay
+	}
+	return math.Round(sum/weights*100) / 100
+}
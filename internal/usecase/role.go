@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// ErrOwnRoleRevoke is returned when a staff member revokes their own role
+// managing roles, it is shown to users as is
+var ErrOwnRoleRevoke = errors.New("can't revoke your own role that manages roles")
+
+// Time permissions of the user are cached for, the cache is also
+// invalidated on every change of the user's roles
+var permissionsTTL = 10 * time.Minute
+
+// RoleUseCase is a struct that provides all use cases of users' roles
+// and the permissions granted by them
+type RoleUseCase struct {
+	repo      RoleRepo
+	cache     PermissionCache
+	appLogger *logger.Logger
+}
+
+func NewRoleUseCase(r RoleRepo, c PermissionCache, l *logger.Logger) *RoleUseCase {
+	return &RoleUseCase{
+		repo:      r,
+		cache:     c,
+		appLogger: l,
+	}
+}
+
+// GetRoles usecase gets roles of the user
+func (uc *RoleUseCase) GetRoles(ctx context.Context, userID int) ([]string, error) {
+	roles, err := uc.repo.GetUserRoles(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetPermissions usecase gets permissions of the user from the cache,
+// on cache miss they are derived from the user's roles and cached
+func (uc *RoleUseCase) GetPermissions(ctx context.Context, userID int) ([]string, error) {
+	permissions, ok, err := uc.cache.GetPermissions(ctx, userID)
+	if err == nil && ok {
+		return permissions, nil
+	}
+
+	roles, err := uc.repo.GetUserRoles(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	permissions = entity.Permissions(roles)
+	// Failing to cache the permissions only makes the next request slower
+	_ = uc.cache.SetPermissions(ctx, userID, permissions, permissionsTTL)
+	return permissions, nil
+}
+
+// HasPermission usecase checks if the user has the permission
+func (uc *RoleUseCase) HasPermission(ctx context.Context, userID int, permission string) (bool, error) {
+	permissions, err := uc.GetPermissions(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return false, err
+	}
+
+	for _, p := range permissions {
+		if p == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GrantRole usecase grants the role to the user on behalf of the staff member
+func (uc *RoleUseCase) GrantRole(ctx context.Context, staffID, userID int, req *dto.RoleRequestBody) error {
+	err := uc.repo.GrantRole(ctx, userID, req.Role, staffID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	return uc.invalidate(ctx, userID)
+}
+
+// RevokeRole usecase revokes the role from the user, staff members can't
+// revoke their own roles managing roles so that nobody is locked out
+func (uc *RoleUseCase) RevokeRole(ctx context.Context, staffID, userID int, role string) error {
+	if staffID == userID {
+		for _, p := range entity.RolePermissions[role] {
+			if p == entity.PermRolesManage {
+				return ErrOwnRoleRevoke
+			}
+		}
+	}
+
+	err := uc.repo.RevokeRole(ctx, userID, role)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	return uc.invalidate(ctx, userID)
+}
+
+// invalidate deletes cached permissions of the user after change of the roles
+func (uc *RoleUseCase) invalidate(ctx context.Context, userID int) error {
+	err := uc.cache.DeletePermissions(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/geohelper"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/dacore-x/truckly/pkg/routeopt"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Time budget of the route solver
+var routeSolveBudget = 200 * time.Millisecond
+
+// Time spent by courier at each stop
+var stopServiceTime = 5 * time.Minute
+
+// Average speed used to estimate durations when geo API is unavailable, in m/s
+var fallbackSpeed = 8.
+
+// routeStops returns stops left to visit for the deliveries,
+// pickup is skipped if the cargo has already been picked up
+func routeStops(deliveries []*entity.Delivery) []*entity.RouteStop {
+	stops := make([]*entity.RouteStop, 0, len(deliveries)*2)
+	for _, d := range deliveries {
+		if d.PickedUpAt == nil {
+			stops = append(stops, &entity.RouteStop{
+				DeliveryID: d.ID,
+				Type:       entity.StopPickup,
+				Object:     d.Geo.FromObject,
+				Latitude:   d.Geo.FromLatitude,
+				Longitude:  d.Geo.FromLongitude,
+				Window:     d.PickupWindow,
+			})
+		}
+		stops = append(stops, &entity.RouteStop{
+			DeliveryID: d.ID,
+			Type:       entity.StopDropoff,
+			Object:     d.Geo.ToObject,
+			Latitude:   d.Geo.ToLatitude,
+			Longitude:  d.Geo.ToLongitude,
+			Window:     d.DropoffWindow,
+		})
+	}
+	return stops
+}
+
+// RoutePlanner is a struct that orders courier's stops
+// minimizing travel distance with respect to pickup-before-dropoff
+// constraints and time windows of the stops
+type RoutePlanner struct {
+	geo       GeoWebAPI
+	appLogger *logger.Logger
+}
+
+func NewRoutePlanner(g GeoWebAPI, l *logger.Logger) *RoutePlanner {
+	return &RoutePlanner{geo: g, appLogger: l}
+}
+
+// Plan orders stops of the deliveries starting from courier's location,
+// starts from the first stop if courier's location is unknown
+func (rp *RoutePlanner) Plan(presence *entity.CourierPresence, deliveries []*entity.Delivery) *dto.RouteResponse {
+	stops := routeStops(deliveries)
+	resp := &dto.RouteResponse{
+		Stops: make([]*dto.RouteStopResponse, 0, len(stops)),
+	}
+	if len(stops) == 0 {
+		return resp
+	}
+
+	// Node 0 is courier's location, nodes 1..n are the stops
+	points := make([]dto.PointRequest, 0, len(stops)+1)
+	if presence != nil {
+		points = append(points, dto.PointRequest{Lat: presence.Latitude, Lon: presence.Longitude})
+	} else {
+		points = append(points, dto.PointRequest{Lat: stops[0].Latitude, Lon: stops[0].Longitude})
+	}
+	for _, stop := range stops {
+		points = append(points, dto.PointRequest{Lat: stop.Latitude, Lon: stop.Longitude})
+	}
+
+	matrix := rp.routeMatrix(points)
+
+	start := time.Now()
+	problem := &routeopt.Problem{
+		Distances:   matrix.Distances,
+		Durations:   matrix.Durations,
+		Windows:     make([]routeopt.Window, len(points)),
+		Precedence:  make(map[int]int),
+		ServiceTime: stopServiceTime.Seconds(),
+	}
+	problem.Windows[0] = routeopt.NoWindow
+
+	pickupNodes := make(map[int]int)
+	for i, stop := range stops {
+		node := i + 1
+		problem.Windows[node] = routeopt.NoWindow
+		if stop.Window != nil {
+			problem.Windows[node] = routeopt.Window{
+				Earliest: stop.Window.From.Sub(start).Seconds(),
+				Latest:   stop.Window.To.Sub(start).Seconds(),
+			}
+		}
+
+		if stop.Type == entity.StopPickup {
+			pickupNodes[stop.DeliveryID] = node
+		} else if pickup, ok := pickupNodes[stop.DeliveryID]; ok {
+			problem.Precedence[node] = pickup
+		}
+	}
+
+	solution := routeopt.Solve(problem, routeSolveBudget)
+
+	for i, node := range solution.Order {
+		stop := stops[node-1]
+		stop.ETA = start.Add(time.Duration(solution.Arrivals[i]) * time.Second)
+
+		stopResp := &dto.RouteStopResponse{
+			DeliveryID: stop.DeliveryID,
+			Type:       stop.Type,
+			Object:     stop.Object,
+			Latitude:   stop.Latitude,
+			Longitude:  stop.Longitude,
+			ETA:        stop.ETA,
+		}
+		if stop.Window != nil {
+			stopResp.WindowFrom = &stop.Window.From
+			stopResp.WindowTo = &stop.Window.To
+			stopResp.IsLate = stop.ETA.After(stop.Window.To)
+		}
+		resp.Stops = append(resp.Stops, stopResp)
+	}
+
+	resp.Distance = solution.Distance
+	if len(solution.Arrivals) != 0 {
+		resp.Duration = solution.Arrivals[len(solution.Arrivals)-1]
+	}
+	return resp
+}
+
+// routeMatrix gets distances and durations between points from geo API
+// with a single request and falls back to straight-line estimation
+func (rp *RoutePlanner) routeMatrix(points []dto.PointRequest) *dto.RouteMatrix {
+	matrix, err := rp.geo.GetDistanceMatrix(points, points)
+	if err == nil {
+		return matrix
+	}
+	rp.appLogger.Warnf("route planner: using straight-line distances: %v", err)
+
+	matrix = &dto.RouteMatrix{
+		Distances: make([][]float64, len(points)),
+		Durations: make([][]float64, len(points)),
+	}
+	for i, from := range points {
+		matrix.Distances[i] = make([]float64, len(points))
+		matrix.Durations[i] = make([]float64, len(points))
+		for j, to := range points {
+			d := geohelper.Haversine(from.Lat, from.Lon, to.Lat, to.Lon)
+			matrix.Distances[i][j] = d
+			matrix.Durations[i][j] = d / fallbackSpeed
+		}
+	}
+	return matrix
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Errors of the authentication, they are shown to users as is
+var (
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used, session is revoked")
+	ErrSessionNotFound     = errors.New("session is not found")
+	ErrUserBanned          = errors.New("user is banned")
+)
+
+// SessionUseCase is a struct that provides all use cases of users' sessions,
+// each session has short-lived access tokens and a refresh token rotated on every use
+type SessionUseCase struct {
+	users     UserRepo
+	store     SessionStore
+	twoFactor TwoFactorRepo
+	failures  TwoFactorStore
+	cfg       *config.AUTH
+	appLogger *logger.Logger
+}
+
+func NewSessionUseCase(u UserRepo, s SessionStore, t TwoFactorRepo, f TwoFactorStore, cfg *config.AUTH, l *logger.Logger) *SessionUseCase {
+	return &SessionUseCase{
+		users:     u,
+		store:     s,
+		twoFactor: t,
+		failures:  f,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// Login usecase checks user's credentials and the second factor if the user has enabled it
+// and starts new session on the client's device. The session remembers that the second
+// factor is passed, so it is not asked again until the session ends
+func (uc *SessionUseCase) Login(ctx context.Context, req *dto.UserLoginRequestBody, client *entity.SessionClient) (*entity.AuthTokens, error) {
+	user, err := uc.users.GetUserPrivateByEmail(ctx, req.Email)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, ErrInvalidCredentials
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	tf, err := uc.twoFactor.GetTwoFactor(ctx, user.ID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	twoFactor := tf != nil && tf.EnabledAt != nil
+	if twoFactor {
+		if req.Code == "" {
+			return nil, ErrTwoFactorRequired
+		}
+		if err = checkSecondFactor(ctx, uc.twoFactor, uc.failures, tf, req.Code); err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+	}
+
+	sessionID, err := randomHex(16)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	session := &entity.Session{
+		ID:         sessionID,
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(uc.cfg.RefreshTTL),
+		TwoFactor:  twoFactor,
+	}
+	err = uc.store.CreateSession(ctx, session, hashSecret(secret))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return uc.issueTokens(session, secret)
+}
+
+// Refresh usecase exchanges refresh token for the new pair of tokens. Presenting
+// a token that has already been exchanged means it has leaked, so the whole session
+// is revoked and both the legitimate client and the attacker have to log in again.
+// Sessions of the banned user are revoked instead of being refreshed
+func (uc *SessionUseCase) Refresh(ctx context.Context, refreshToken string, client *entity.SessionClient) (*entity.AuthTokens, error) {
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := uc.store.GetSession(ctx, sessionID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Sessions are also revoked on the ban event,
+	// the check covers the time until it is handled
+	meta, err := uc.users.GetUserMeta(ctx, session.UserID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if meta.IsBanned {
+		if err = uc.store.DeleteSessions(ctx, session.UserID); err != nil {
+			uc.appLogger.Error(err)
+			return nil, err
+		}
+		return nil, ErrUserBanned
+	}
+
+	newSecret, err := randomHex(32)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	session.UserAgent = client.UserAgent
+	session.IP = client.IP
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(uc.cfg.RefreshTTL)
+
+	hash := hashSecret(secret)
+	rotated, err := uc.store.RotateSession(ctx, session, hash, hashSecret(newSecret))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if rotated {
+		return uc.issueTokens(session, newSecret)
+	}
+
+	used, err := uc.store.IsRefreshHashUsed(ctx, sessionID, hash)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if !used {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	uc.appLogger.Warnf("refresh token of session %s of user %d is reused from %s", sessionID, session.UserID, client.IP)
+	err = uc.store.DeleteSession(ctx, session.UserID, sessionID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return nil, ErrRefreshTokenReused
+}
+
+// CheckSession usecase checks that the session of the access token is not revoked
+func (uc *SessionUseCase) CheckSession(ctx context.Context, userID int, sessionID string) (*entity.Session, error) {
+	session, err := uc.store.GetSession(ctx, sessionID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if session == nil || session.UserID != userID {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// GetSessions usecase gets active sessions of the user, the current one is marked
+func (uc *SessionUseCase) GetSessions(ctx context.Context, userID int, currentID string) ([]*entity.Session, error) {
+	sessions, err := uc.store.GetSessions(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.ID == currentID
+	}
+	return sessions, nil
+}
+
+// RevokeSession usecase ends the session of the user, its tokens stop working at once
+func (uc *SessionUseCase) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	_, err := uc.CheckSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	err = uc.store.DeleteSession(ctx, userID, sessionID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// RevokeAllSessions usecase ends all sessions of the user
+func (uc *SessionUseCase) RevokeAllSessions(ctx context.Context, userID int) error {
+	err := uc.store.DeleteSessions(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// HandleEvent revokes all sessions of the banned user
+func (uc *SessionUseCase) HandleEvent(ctx context.Context, e *entity.OutboxEvent) error {
+	if e.AggregateType != entity.AggregateUser || e.Type != entity.UserBanned {
+		return nil
+	}
+	return uc.RevokeAllSessions(ctx, e.AggregateID)
+}
+
+// issueTokens signs access token of the session, refresh token
+// consists of session's id and the secret only the client knows
+func (uc *SessionUseCase) issueTokens(session *entity.Session, secret string) (*entity.AuthTokens, error) {
+	accessExpiresAt := time.Now().Add(uc.cfg.AccessTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": session.UserID,
+		"sid": session.ID,
+		"exp": accessExpiresAt.Unix(),
+	})
+
+	accessToken, err := token.SignedString([]byte(os.Getenv("SECRET")))
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, fmt.Errorf("failed to create token")
+	}
+
+	return &entity.AuthTokens{
+		SessionID:        session.ID,
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     session.ID + "." + secret,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// hashSecret returns hash of the token's secret kept in storage
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
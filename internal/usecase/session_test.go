@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/infrastructure/repository/cache"
+)
+
+// userMetaStub returns ban status of the users instead of querying the database
+type userMetaStub struct {
+	UserRepo
+	banned map[int]bool
+}
+
+func (u *userMetaStub) GetUserMeta(ctx context.Context, id int) (*dto.UserMetaResponse, error) {
+	return &dto.UserMetaResponse{UserID: id, IsBanned: u.banned[id]}, nil
+}
+
+// newTestSessionUseCase returns the usecase with sessions stored in in-memory redis
+// and the session of the user started with the refresh token returned
+func newTestSessionUseCase(t *testing.T, users *userMetaStub, userID int) (*SessionUseCase, *miniredis.Miniredis, string) {
+	t.Setenv("SECRET", "test")
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	testLogger := logrus.New()
+	store := cache.NewSessionStore(rdb, logger.New(testLogger))
+	cfg := &config.AUTH{AccessTTL: time.Minute, RefreshTTL: time.Hour}
+	uc := NewSessionUseCase(users, store, nil, nil, cfg, logger.New(testLogger))
+
+	now := time.Now().UTC()
+	session := &entity.Session{ID: "s1", UserID: userID, CreatedAt: now, LastUsedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, store.CreateSession(context.Background(), session, hashSecret("secret")))
+	return uc, mr, "s1.secret"
+}
+
+func TestSessionUseCase_Refresh(t *testing.T) {
+	ctx := context.Background()
+	client := &entity.SessionClient{UserAgent: "curl/8.0", IP: "10.0.0.1"}
+
+	t.Run("refresh token is rotated", func(t *testing.T) {
+		uc, _, token := newTestSessionUseCase(t, &userMetaStub{}, 1)
+
+		tokens, err := uc.Refresh(ctx, token, client)
+		require.NoError(t, err)
+		require.NotEqual(t, token, tokens.RefreshToken)
+		require.True(t, strings.HasPrefix(tokens.RefreshToken, "s1."))
+
+		next, err := uc.Refresh(ctx, tokens.RefreshToken, client)
+		require.NoError(t, err)
+		require.NotEqual(t, tokens.RefreshToken, next.RefreshToken)
+	})
+
+	t.Run("reused token revokes the whole session", func(t *testing.T) {
+		uc, _, token := newTestSessionUseCase(t, &userMetaStub{}, 1)
+
+		tokens, err := uc.Refresh(ctx, token, client)
+		require.NoError(t, err)
+
+		_, err = uc.Refresh(ctx, token, client)
+		require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+		// Token issued by the rotation is revoked together with the session
+		_, err = uc.Refresh(ctx, tokens.RefreshToken, client)
+		require.ErrorIs(t, err, ErrInvalidRefreshToken)
+		_, err = uc.CheckSession(ctx, 1, "s1")
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("unknown secret doesn't revoke the session", func(t *testing.T) {
+		uc, _, token := newTestSessionUseCase(t, &userMetaStub{}, 1)
+
+		_, err := uc.Refresh(ctx, "s1.guessed", client)
+		require.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+		_, err = uc.Refresh(ctx, token, client)
+		require.NoError(t, err)
+	})
+
+	t.Run("expired session can't be refreshed", func(t *testing.T) {
+		uc, mr, token := newTestSessionUseCase(t, &userMetaStub{}, 1)
+
+		mr.FastForward(time.Hour + time.Minute)
+
+		_, err := uc.Refresh(ctx, token, client)
+		require.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("banned user's sessions are revoked", func(t *testing.T) {
+		uc, _, token := newTestSessionUseCase(t, &userMetaStub{banned: map[int]bool{1: true}}, 1)
+
+		_, err := uc.Refresh(ctx, token, client)
+		require.ErrorIs(t, err, ErrUserBanned)
+		_, err = uc.CheckSession(ctx, 1, "s1")
+		require.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		uc, _, _ := newTestSessionUseCase(t, &userMetaStub{}, 1)
+
+		_, err := uc.Refresh(ctx, "s1", client)
+		require.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// addEvent appends the event to the delivery's timeline, actor 0 stands for the system,
+// timeline is auxiliary so failure is only logged and doesn't break the action
+func addEvent(ctx context.Context, timeline TimelineRepo, l *logger.Logger, deliveryID, actorID int, eventType string, payload interface{}) {
+	event := &entity.DeliveryEvent{DeliveryID: deliveryID, Type: eventType}
+	if actorID != 0 {
+		event.ActorID = &actorID
+	}
+
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			l.Error(err)
+			return
+		}
+		event.Payload = raw
+	}
+
+	if err := timeline.AddEvent(ctx, event); err != nil {
+		l.Error(err)
+	}
+}
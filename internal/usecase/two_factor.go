@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/dacore-x/truckly/pkg/totp"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Errors of the second factor, they are shown to users as is
+var (
+	ErrTwoFactorRequired    = errors.New("two-factor authentication code is required")
+	ErrInvalidTwoFactorCode = errors.New("two-factor authentication code is invalid")
+	ErrTwoFactorLocked      = errors.New("too many failed attempts, try again later")
+	ErrTwoFactorMandatory   = errors.New("two-factor authentication is mandatory for staff")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+)
+
+// Issuer shown in authenticator apps, failed attempts allowed within
+// the lockout window and number of recovery codes issued at once
+var (
+	twoFactorIssuer      = "Truckly"
+	maxTwoFactorFailures = 5
+	twoFactorLockout     = 15 * time.Minute
+	recoveryCodesCount   = 10
+)
+
+// TwoFactorUseCase is a struct that provides all use cases of users' TOTP second factor,
+// it is optional for users and mandatory for staff
+type TwoFactorUseCase struct {
+	users     UserRepo
+	roles     RoleRepo
+	repo      TwoFactorRepo
+	failures  TwoFactorStore
+	sessions  SessionStore
+	appLogger *logger.Logger
+}
+
+func NewTwoFactorUseCase(u UserRepo, ro RoleRepo, r TwoFactorRepo, f TwoFactorStore, s SessionStore, l *logger.Logger) *TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		users:     u,
+		roles:     ro,
+		repo:      r,
+		failures:  f,
+		sessions:  s,
+		appLogger: l,
+	}
+}
+
+// Enroll usecase generates new secret of the user, second factor is enabled
+// only after the user confirms it with the code from authenticator app
+func (uc *TwoFactorUseCase) Enroll(ctx context.Context, userID int) (*entity.TwoFactorEnrollment, error) {
+	user, err := uc.users.GetUserPrivateByID(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.repo.SaveTwoFactorSecret(ctx, userID, secret)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	return &entity.TwoFactorEnrollment{
+		Secret: secret,
+		URL:    totp.URL(twoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm usecase enables second factor of the user by the first code from
+// authenticator app, the current session is treated as passed the second factor.
+// Recovery codes are returned only once
+func (uc *TwoFactorUseCase) Confirm(ctx context.Context, userID int, sessionID string, req *dto.TwoFactorCodeRequestBody) ([]string, error) {
+	tf, err := uc.repo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if tf == nil {
+		err = fmt.Errorf("two-factor authentication enrollment is not started")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if tf.EnabledAt != nil {
+		err = fmt.Errorf("two-factor authentication is already enabled")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = checkSecondFactor(ctx, uc.repo, uc.failures, tf, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.repo.EnableTwoFactor(ctx, userID, hashes)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.sessions.MarkSessionTwoFactor(ctx, sessionID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable usecase disables second factor of the user by the current code,
+// staff can't disable it
+func (uc *TwoFactorUseCase) Disable(ctx context.Context, userID int, req *dto.TwoFactorCodeRequestBody) error {
+	roles, err := uc.roles.GetUserRoles(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if entity.HasStaffRole(roles) {
+		return ErrTwoFactorMandatory
+	}
+
+	tf, err := uc.enabledTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = checkSecondFactor(ctx, uc.repo, uc.failures, tf, req.Code)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.DeleteTwoFactor(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes usecase replaces recovery codes of the user with new ones
+// by the current code, codes are returned only once
+func (uc *TwoFactorUseCase) RegenerateRecoveryCodes(ctx context.Context, userID int, req *dto.TwoFactorCodeRequestBody) ([]string, error) {
+	tf, err := uc.enabledTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkSecondFactor(ctx, uc.repo, uc.failures, tf, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.repo.ReplaceRecoveryCodes(ctx, userID, hashes)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return codes, nil
+}
+
+// enabledTwoFactor gets second factor of the user checking that it is enabled
+func (uc *TwoFactorUseCase) enabledTwoFactor(ctx context.Context, userID int) (*entity.TwoFactor, error) {
+	tf, err := uc.repo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if tf == nil || tf.EnabledAt == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return tf, nil
+}
+
+// checkSecondFactor checks TOTP code or unused recovery code of the user. Each TOTP code
+// is accepted once and after too many failed attempts all codes are rejected for a while.
+// Attempt is counted before the check, so that concurrent guesses can't exceed the limit
+func checkSecondFactor(ctx context.Context, repo TwoFactorRepo, attempts TwoFactorStore, tf *entity.TwoFactor, code string) error {
+	count, err := attempts.CountAttempt(ctx, tf.UserID, twoFactorLockout)
+	if err != nil {
+		return err
+	}
+	if count > maxTwoFactorFailures {
+		return ErrTwoFactorLocked
+	}
+
+	code = strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+	var ok bool
+	if step, valid := totp.Validate(tf.Secret, code, time.Now()); valid {
+		ok, err = repo.UseTOTPStep(ctx, tf.UserID, step)
+	} else if tf.EnabledAt != nil {
+		ok, err = repo.UseRecoveryCode(ctx, tf.UserID, hashSecret(code))
+	}
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	return attempts.ResetAttempts(ctx, tf.UserID)
+}
+
+// generateRecoveryCodes generates recovery codes of the form xxxxx-xxxxx and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := randomHex(5)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, hashSecret(code))
+	}
+	return codes, hashes, nil
+}
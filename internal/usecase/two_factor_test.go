@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dacore-x/truckly/internal/entity"
+	"github.com/dacore-x/truckly/internal/infrastructure/repository/cache"
+)
+
+// recoveryCodeStub rejects all recovery codes and counts the checks
+type recoveryCodeStub struct {
+	TwoFactorRepo
+	mu     sync.Mutex
+	checks int
+}
+
+func (r *recoveryCodeStub) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks++
+	return false, nil
+}
+
+func TestCheckSecondFactor_Lockout(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	store := cache.NewTwoFactorStore(rdb, logger.New(logrus.New()))
+
+	now := time.Now()
+	tf := &entity.TwoFactor{UserID: 1, Secret: "JBSWY3DPEHPK3PXP", EnabledAt: &now}
+	repo := &recoveryCodeStub{}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- checkSecondFactor(ctx, repo, store, tf, "wrong-code")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var invalid, locked int
+	for err := range errs {
+		switch err {
+		case ErrInvalidTwoFactorCode:
+			invalid++
+		case ErrTwoFactorLocked:
+			locked++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	require.Equal(t, maxTwoFactorFailures, invalid)
+	require.Equal(t, 20-maxTwoFactorFailures, locked)
+	require.Equal(t, maxTwoFactorFailures, repo.checks)
+	require.Greater(t, mr.TTL("two_factor_failures:1"), time.Duration(0))
+}
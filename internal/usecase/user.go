@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/dacore-x/truckly/pkg/phonehelper"
 
 	"github.com/dacore-x/truckly/internal/dto"
 )
@@ -24,7 +25,18 @@ func NewUserUseCase(r UserRepo, l *logger.Logger) *UserUseCase {
 
 // CreateUser usecase creates new user account
 func (uc *UserUseCase) CreateUser(ctx context.Context, req *dto.UserSignUpRequestBody) error {
-	err := uc.repo.CreateUser(ctx, req)
+	if req.CityID == 0 {
+		req.CityID = defaultCityID
+	}
+
+	phone, err := phonehelper.NormalizeE164(req.PhoneNumber)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	req.PhoneNumber = phone
+
+	err = uc.repo.CreateUser(ctx, req)
 	if err != nil {
 		uc.appLogger.Error(err)
 		return err
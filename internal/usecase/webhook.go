@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dacore-x/truckly/config"
+	"github.com/dacore-x/truckly/pkg/logger"
+	"github.com/dacore-x/truckly/pkg/nethelper"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Number of webhook deliveries taken from the queue at once and time they are
+// hidden from other workers while being sent
+var (
+	webhookBatchSize = 50
+	webhookLease     = time.Minute
+)
+
+// Delivery events webhooks can subscribe to
+var webhookEvents = map[string]bool{
+	entity.WebhookDeliveryCreated:   true,
+	entity.WebhookDeliveryAccepted:  true,
+	entity.WebhookDeliveryPickedUp:  true,
+	entity.WebhookDeliveryCompleted: true,
+	entity.WebhookDeliveryCancelled: true,
+}
+
+// Maximum number of webhooks of the client
+var maxWebhooks = 10
+
+// webhookPayload represents body of the request sent to webhook,
+// id of the event is kept on redelivery so that receivers can deduplicate it
+type webhookPayload struct {
+	ID        string                   `json:"id"`
+	Event     string                   `json:"event"`
+	CreatedAt time.Time                `json:"created_at"`
+	Data      *entity.WebhookEventData `json:"data"`
+}
+
+// WebhookUseCase is a struct that provides all use cases of clients' webhooks,
+// events are queued for each subscribed webhook and sent by the worker
+type WebhookUseCase struct {
+	repo      WebhookRepo
+	client    WebhookClient
+	cfg       *config.WEBHOOK
+	appLogger *logger.Logger
+}
+
+func NewWebhookUseCase(r WebhookRepo, c WebhookClient, cfg *config.WEBHOOK, l *logger.Logger) *WebhookUseCase {
+	return &WebhookUseCase{
+		repo:      r,
+		client:    c,
+		cfg:       cfg,
+		appLogger: l,
+	}
+}
+
+// CreateWebhook usecase registers endpoint of the client with secret used to sign events,
+// the secret is returned only once on creation
+func (uc *WebhookUseCase) CreateWebhook(ctx context.Context, userID int, req *dto.WebhookRequestBody) (*entity.Webhook, error) {
+	err := validateWebhookURL(ctx, req.URL)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	webhooks, err := uc.repo.GetWebhooks(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	if len(webhooks) >= maxWebhooks {
+		err = fmt.Errorf("webhooks limit reached")
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	webhook := &entity.Webhook{
+		UserID: userID,
+		URL:    req.URL,
+		Secret: "whsec_" + secret,
+		Events: req.Events,
+	}
+	err = uc.repo.CreateWebhook(ctx, webhook)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook usecase changes endpoint and events of the client's webhook,
+// disabled webhook is enabled again with failures counter reset
+func (uc *WebhookUseCase) UpdateWebhook(ctx context.Context, userID, webhookID int, req *dto.WebhookRequestBody) error {
+	err := validateWebhookURL(ctx, req.URL)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	webhook, err := uc.repo.GetWebhookByID(ctx, userID, webhookID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	webhook.URL = req.URL
+	webhook.Events = req.Events
+	if req.IsActive != nil {
+		if *req.IsActive && !webhook.IsActive {
+			webhook.FailureCount = 0
+			webhook.DisabledAt = nil
+		}
+		webhook.IsActive = *req.IsActive
+	}
+
+	err = uc.repo.UpdateWebhook(ctx, webhook)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteWebhook usecase deletes the client's webhook
+func (uc *WebhookUseCase) DeleteWebhook(ctx context.Context, userID, webhookID int) error {
+	err := uc.repo.DeleteWebhook(ctx, userID, webhookID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// GetWebhooks usecase gets all webhooks of the client
+func (uc *WebhookUseCase) GetWebhooks(ctx context.Context, userID int) ([]*entity.Webhook, error) {
+	webhooks, err := uc.repo.GetWebhooks(ctx, userID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhookDeliveries usecase gets page of the delivery log of the client's webhook
+func (uc *WebhookUseCase) GetWebhookDeliveries(ctx context.Context, userID, webhookID, page int) ([]*entity.WebhookDelivery, error) {
+	_, err := uc.repo.GetWebhookByID(ctx, userID, webhookID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	deliveries, err := uc.repo.GetWebhookDeliveries(ctx, webhookID, page)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Redeliver usecase queues the same event once more as a new delivery of the webhook
+func (uc *WebhookUseCase) Redeliver(ctx context.Context, userID, webhookID, deliveryID int) (*entity.WebhookDelivery, error) {
+	_, err := uc.repo.GetWebhookByID(ctx, userID, webhookID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	original, err := uc.repo.GetWebhookDeliveryByID(ctx, webhookID, deliveryID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	delivery := &entity.WebhookDelivery{
+		WebhookID: webhookID,
+		Event:     original.Event,
+		Payload:   original.Payload,
+	}
+	err = uc.repo.CreateWebhookDelivery(ctx, delivery)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// HandleEvent queues the delivery's domain event with current state of the delivery
+// for each webhook of its client subscribed to the event, id of the domain event is sent
+// so that receivers can deduplicate events handled more than once
+func (uc *WebhookUseCase) HandleEvent(ctx context.Context, e *entity.OutboxEvent) error {
+	if e.AggregateType != entity.AggregateDelivery || !webhookEvents[e.Type] {
+		return nil
+	}
+
+	data, err := uc.repo.GetWebhookEventData(ctx, e.AggregateID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	webhooks, err := uc.repo.GetSubscribedWebhooks(ctx, data.ClientID, e.Type)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(&webhookPayload{
+		ID:        fmt.Sprintf("evt_%d", e.ID),
+		Event:     e.Type,
+		CreatedAt: e.CreatedAt.UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		delivery := &entity.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event:     e.Type,
+			Payload:   payload,
+		}
+		if err = uc.repo.CreateWebhookDelivery(ctx, delivery); err != nil {
+			uc.appLogger.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Run sends queued webhook deliveries until the context is done,
+// several workers may run at once since deliveries are claimed
+func (uc *WebhookUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.sendPending(ctx)
+		}
+	}
+}
+
+// sendPending sends deliveries due for sending and schedules retries of failed ones
+// with exponential backoff until attempts are exhausted
+func (uc *WebhookUseCase) sendPending(ctx context.Context) {
+	deliveries, err := uc.repo.ClaimWebhookDeliveries(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		status, err := uc.send(ctx, delivery)
+		delivery.ResponseStatus = nil
+		if status != 0 {
+			delivery.ResponseStatus = &status
+		}
+
+		if err == nil {
+			now := time.Now()
+			delivery.Status = entity.WebhookSucceeded
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+		} else {
+			delivery.LastError = err.Error()
+			if delivery.Attempts >= uc.cfg.MaxAttempts {
+				delivery.Status = entity.WebhookFailed
+			} else {
+				delivery.NextAttemptAt = time.Now().Add(uc.cfg.RetryDelay << (delivery.Attempts - 1))
+			}
+		}
+
+		disabled, err := uc.repo.RecordWebhookAttempt(ctx, delivery, uc.cfg.DisableAfter)
+		if err != nil {
+			uc.appLogger.Error(err)
+			continue
+		}
+		if disabled {
+			uc.appLogger.Infof("webhook: webhook %v is disabled after %v consecutive failures",
+				delivery.WebhookID, uc.cfg.DisableAfter)
+		}
+	}
+}
+
+// send signs payload of the delivery with secret of its webhook and posts it
+func (uc *WebhookUseCase) send(ctx context.Context, delivery *entity.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		"X-Truckly-Event":     delivery.Event,
+		"X-Truckly-Delivery":  strconv.Itoa(delivery.ID),
+		"X-Truckly-Signature": "t=" + timestamp + ",v1=" + signWebhookPayload(delivery.Secret, timestamp, delivery.Payload),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.cfg.Timeout)
+	defer cancel()
+	return uc.client.Send(ctx, delivery.URL, headers, delivery.Payload)
+}
+
+// signWebhookPayload computes HMAC-SHA256 of the timestamp and payload, receivers
+// recompute it with their secret and reject old timestamps to prevent replays
+func signWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateWebhookURL checks that the webhook is an absolute https URL
+// whose host resolves only to public addresses
+func validateWebhookURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("url must be absolute https url")
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("url host can't be resolved")
+	}
+	for _, addr := range addrs {
+		if !nethelper.IsPublicIP(addr.IP) {
+			return fmt.Errorf("url must point to a public address")
+		}
+	}
+	return nil
+}
+
+// randomHex generates random string of n bytes in hex
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dacore-x/truckly/pkg/geohelper"
+	"github.com/dacore-x/truckly/pkg/logger"
+
+	"github.com/dacore-x/truckly/internal/dto"
+	"github.com/dacore-x/truckly/internal/entity"
+)
+
+// Errors of the service area checks, they are shown to users as is
+var (
+	ErrOutsideServiceArea = errors.New("location is outside of service area")
+	ErrTypeNotAvailable   = errors.New("delivery type is not available in this zone")
+)
+
+// Lifetime of the cached active zones used for coverage checks
+var zonesCacheTTL = time.Minute
+
+// ZoneUseCase is a struct that provides all use cases
+// of service zones and coverage checks
+type ZoneUseCase struct {
+	repo      ZoneRepo
+	appLogger *logger.Logger
+
+	mu       sync.RWMutex
+	active   []*entity.ServiceZone
+	loadedAt time.Time
+}
+
+func NewZoneUseCase(r ZoneRepo, l *logger.Logger) *ZoneUseCase {
+	return &ZoneUseCase{repo: r, appLogger: l}
+}
+
+// CreateZone usecase creates service zone from GeoJSON feature
+func (uc *ZoneUseCase) CreateZone(ctx context.Context, feature *dto.ZoneFeature) (*dto.ZoneFeature, error) {
+	zone, err := zoneFromFeature(feature)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	err = uc.repo.CreateZone(ctx, zone)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	uc.invalidate()
+	return zoneToFeature(zone), nil
+}
+
+// ImportZones usecase creates service zones from GeoJSON feature collection
+// and returns amount of created zones
+func (uc *ZoneUseCase) ImportZones(ctx context.Context, collection *dto.ZoneFeatureCollection) (int, error) {
+	zones := make([]*entity.ServiceZone, 0, len(collection.Features))
+	for _, feature := range collection.Features {
+		zone, err := zoneFromFeature(feature)
+		if err != nil {
+			uc.appLogger.Error(err)
+			return 0, err
+		}
+		zones = append(zones, zone)
+	}
+
+	err := uc.repo.ImportZones(ctx, zones)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return 0, err
+	}
+	uc.invalidate()
+	return len(zones), nil
+}
+
+// UpdateZone usecase replaces geometry and settings of the service zone
+func (uc *ZoneUseCase) UpdateZone(ctx context.Context, zoneID int, feature *dto.ZoneFeature) error {
+	zone, err := zoneFromFeature(feature)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	zone.ID = zoneID
+
+	err = uc.repo.UpdateZone(ctx, zone)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	uc.invalidate()
+	return nil
+}
+
+// DeleteZone usecase deletes the service zone
+func (uc *ZoneUseCase) DeleteZone(ctx context.Context, zoneID int) error {
+	err := uc.repo.DeleteZone(ctx, zoneID)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return err
+	}
+	uc.invalidate()
+	return nil
+}
+
+// GetZones usecase exports all service zones as GeoJSON feature collection
+func (uc *ZoneUseCase) GetZones(ctx context.Context) (*dto.ZoneFeatureCollection, error) {
+	zones, err := uc.repo.GetZones(ctx)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+
+	collection := &dto.ZoneFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]*dto.ZoneFeature, 0, len(zones)),
+	}
+	for _, zone := range zones {
+		collection.Features = append(collection.Features, zoneToFeature(zone))
+	}
+	return collection, nil
+}
+
+// CheckCoverage checks that both points of the delivery are inside service zones
+// and delivery type is served in the pickup zone, returns the pickup zone.
+// Coverage isn't restricted until at least one active zone is configured
+func (uc *ZoneUseCase) CheckCoverage(ctx context.Context, typeID int, from, to *dto.PointRequest) (*entity.ServiceZone, error) {
+	zones, err := uc.activeZones(ctx)
+	if err != nil {
+		uc.appLogger.Error(err)
+		return nil, err
+	}
+	if len(zones) == 0 {
+		return nil, nil
+	}
+
+	pickupZone := findZone(zones, from.Lat, from.Lon)
+	if pickupZone == nil || findZone(zones, to.Lat, to.Lon) == nil {
+		return nil, ErrOutsideServiceArea
+	}
+
+	if !pickupZone.AllowsType(typeID) {
+		return nil, ErrTypeNotAvailable
+	}
+	return pickupZone, nil
+}
+
+// activeZones returns cached active zones and reloads them when the cache is stale
+func (uc *ZoneUseCase) activeZones(ctx context.Context) ([]*entity.ServiceZone, error) {
+	uc.mu.RLock()
+	if uc.active != nil && time.Since(uc.loadedAt) < zonesCacheTTL {
+		defer uc.mu.RUnlock()
+		return uc.active, nil
+	}
+	uc.mu.RUnlock()
+
+	zones, err := uc.repo.GetZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*entity.ServiceZone, 0, len(zones))
+	for _, zone := range zones {
+		if !zone.IsActive {
+			continue
+		}
+
+		var geometry dto.GeoJSONGeometry
+		err = json.Unmarshal(zone.Geometry, &geometry)
+		if err == nil {
+			zone.Area, err = geohelper.ParseGeoJSON(geometry.Type, geometry.Coordinates)
+		}
+		if err != nil {
+			// Zones are validated on write, broken one shouldn't disable the others
+			uc.appLogger.Warnf("zones: skipping zone %v with invalid geometry: %v", zone.ID, err)
+			continue
+		}
+		active = append(active, zone)
+	}
+
+	uc.mu.Lock()
+	uc.active = active
+	uc.loadedAt = time.Now()
+	uc.mu.Unlock()
+	return active, nil
+}
+
+// invalidate drops cached zones so that changes take effect immediately
+func (uc *ZoneUseCase) invalidate() {
+	uc.mu.Lock()
+	uc.active = nil
+	uc.mu.Unlock()
+}
+
+// findZone returns the first zone containing the point
+func findZone(zones []*entity.ServiceZone, lat, lon float64) *entity.ServiceZone {
+	for _, zone := range zones {
+		if zone.Area.Contains(lat, lon) {
+			return zone
+		}
+	}
+	return nil
+}
+
+// zoneFromFeature converts GeoJSON feature to the zone validating its geometry
+func zoneFromFeature(feature *dto.ZoneFeature) (*entity.ServiceZone, error) {
+	area, err := geohelper.ParseGeoJSON(feature.Geometry.Type, feature.Geometry.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	geometry, err := json.Marshal(feature.Geometry)
+	if err != nil {
+		return nil, err
+	}
+
+	zone := &entity.ServiceZone{
+		Name:             feature.Properties.Name,
+		Geometry:         geometry,
+		AllowedTypes:     feature.Properties.AllowedTypes,
+		TariffMultiplier: feature.Properties.TariffMultiplier,
+		IsActive:         true,
+		Area:             area,
+	}
+	if feature.Properties.IsActive != nil {
+		zone.IsActive = *feature.Properties.IsActive
+	}
+	return zone, nil
+}
+
+// zoneToFeature converts the zone to GeoJSON feature
+func zoneToFeature(zone *entity.ServiceZone) *dto.ZoneFeature {
+	feature := &dto.ZoneFeature{
+		Type:     "Feature",
+		ID:       zone.ID,
+		Geometry: &dto.GeoJSONGeometry{},
+		Properties: &dto.ZoneProperties{
+			Name:             zone.Name,
+			AllowedTypes:     zone.AllowedTypes,
+			TariffMultiplier: zone.TariffMultiplier,
+			IsActive:         &zone.IsActive,
+		},
+	}
+	json.Unmarshal(zone.Geometry, feature.Geometry)
+	return feature
+}
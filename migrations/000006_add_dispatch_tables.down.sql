@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS dispatch_offers;
+
+DROP TABLE IF EXISTS courier_presence;
+
+ALTER TABLE deliveries DROP COLUMN is_dispatching;
@@ -0,0 +1,33 @@
+ALTER TABLE deliveries ADD COLUMN is_dispatching bool NOT NULL DEFAULT (FALSE);
+
+CREATE TABLE courier_presence (
+  user_id bigint PRIMARY KEY,
+  is_online bool NOT NULL DEFAULT (FALSE),
+  type_id bigint NOT NULL,
+  latitude float8 NOT NULL,
+  longitude float8 NOT NULL,
+  idle_since timestamptz NOT NULL DEFAULT (now()),
+  updated_at timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE dispatch_offers (
+  id bigserial PRIMARY KEY,
+  delivery_id bigint NOT NULL,
+  courier_id bigint NOT NULL,
+  status varchar NOT NULL DEFAULT ('pending'),
+  score float8 NOT NULL,
+  distance float8 NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  expires_at timestamptz NOT NULL,
+  responded_at timestamptz
+);
+
+ALTER TABLE courier_presence ADD FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE courier_presence ADD FOREIGN KEY (type_id) REFERENCES delivery_types (id);
+
+ALTER TABLE dispatch_offers ADD FOREIGN KEY (delivery_id) REFERENCES deliveries (id);
+
+ALTER TABLE dispatch_offers ADD FOREIGN KEY (courier_id) REFERENCES users (id);
+
+CREATE INDEX ON dispatch_offers (courier_id, status);
@@ -0,0 +1,9 @@
+ALTER TABLE deliveries
+  DROP COLUMN cargo_weight,
+  DROP COLUMN cargo_volume,
+  DROP COLUMN picked_up_at;
+
+ALTER TABLE delivery_types
+  DROP COLUMN max_active,
+  DROP COLUMN max_weight,
+  DROP COLUMN max_volume;
@@ -0,0 +1,15 @@
+ALTER TABLE delivery_types
+  ADD COLUMN max_active int NOT NULL DEFAULT (1),
+  ADD COLUMN max_weight float8 NOT NULL DEFAULT (0),
+  ADD COLUMN max_volume float8 NOT NULL DEFAULT (0);
+
+UPDATE delivery_types SET max_active = 3, max_weight = 10, max_volume = 0.05 WHERE id = 1;
+UPDATE delivery_types SET max_active = 4, max_weight = 300, max_volume = 1.5 WHERE id = 2;
+UPDATE delivery_types SET max_active = 5, max_weight = 1000, max_volume = 6 WHERE id = 3;
+UPDATE delivery_types SET max_active = 3, max_weight = 5000, max_volume = 30 WHERE id = 4;
+UPDATE delivery_types SET max_active = 2, max_weight = 20000, max_volume = 80 WHERE id = 5;
+
+ALTER TABLE deliveries
+  ADD COLUMN cargo_weight float8 NOT NULL DEFAULT (0),
+  ADD COLUMN cargo_volume float8 NOT NULL DEFAULT (0),
+  ADD COLUMN picked_up_at timestamptz;
@@ -0,0 +1,5 @@
+ALTER TABLE deliveries
+  DROP COLUMN pickup_from,
+  DROP COLUMN pickup_to,
+  DROP COLUMN dropoff_from,
+  DROP COLUMN dropoff_to;
@@ -0,0 +1,5 @@
+ALTER TABLE deliveries
+  ADD COLUMN pickup_from timestamptz,
+  ADD COLUMN pickup_to timestamptz,
+  ADD COLUMN dropoff_from timestamptz,
+  ADD COLUMN dropoff_to timestamptz;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS deliveries_new_geo_id_idx;
+
+DROP INDEX IF EXISTS geo_from_point_idx;
+
+DROP EXTENSION IF EXISTS earthdistance;
+
+DROP EXTENSION IF EXISTS cube;
@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS cube;
+
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+CREATE INDEX geo_from_point_idx ON geo USING gist (ll_to_earth(from_latitude, from_longitude));
+
+CREATE INDEX deliveries_new_geo_id_idx ON deliveries (geo_id) WHERE status_id = 1;
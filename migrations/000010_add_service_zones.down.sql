@@ -0,0 +1,3 @@
+ALTER TABLE deliveries DROP COLUMN IF EXISTS zone_id;
+
+DROP TABLE IF EXISTS service_zones;
@@ -0,0 +1,15 @@
+CREATE TABLE service_zones (
+  id bigserial PRIMARY KEY,
+  name varchar NOT NULL,
+  geometry jsonb NOT NULL,
+  allowed_types bigint[] NOT NULL DEFAULT ('{1,2,3,4,5}'),
+  tariff_multiplier float8 NOT NULL DEFAULT (1),
+  is_active bool NOT NULL DEFAULT (TRUE),
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE deliveries ADD COLUMN zone_id bigint;
+
+ALTER TABLE deliveries ADD FOREIGN KEY (zone_id) REFERENCES service_zones (id) ON DELETE SET NULL;
+
+CREATE INDEX ON deliveries (zone_id);
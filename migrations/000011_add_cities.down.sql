@@ -0,0 +1,5 @@
+ALTER TABLE deliveries DROP COLUMN IF EXISTS city_id;
+
+ALTER TABLE meta DROP COLUMN IF EXISTS city_id;
+
+DROP TABLE IF EXISTS cities;
@@ -0,0 +1,22 @@
+CREATE TABLE cities (
+  id bigserial PRIMARY KEY,
+  name varchar NOT NULL,
+  geocoder_id varchar NOT NULL,
+  timezone varchar NOT NULL,
+  currency varchar(3) NOT NULL,
+  tariff_multiplier float8 NOT NULL DEFAULT (1),
+  enabled_types bigint[] NOT NULL DEFAULT ('{1,2,3,4,5}'),
+  is_active bool NOT NULL DEFAULT (TRUE)
+);
+
+INSERT INTO cities(name, geocoder_id, timezone, currency) VALUES ('Moscow', '4504222397630173', 'Europe/Moscow', 'RUB');
+
+ALTER TABLE meta ADD COLUMN city_id bigint NOT NULL DEFAULT (1);
+
+ALTER TABLE deliveries ADD COLUMN city_id bigint NOT NULL DEFAULT (1);
+
+ALTER TABLE meta ADD FOREIGN KEY (city_id) REFERENCES cities (id);
+
+ALTER TABLE deliveries ADD FOREIGN KEY (city_id) REFERENCES cities (id);
+
+CREATE INDEX ON deliveries (city_id, status_id);
@@ -0,0 +1,16 @@
+CREATE TABLE saved_places (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  label varchar NOT NULL,
+  latitude float8 NOT NULL,
+  longitude float8 NOT NULL,
+  object varchar NOT NULL,
+  entrance varchar NOT NULL DEFAULT (''),
+  floor varchar NOT NULL DEFAULT (''),
+  notes varchar NOT NULL DEFAULT (''),
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE saved_places ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX ON saved_places (user_id);
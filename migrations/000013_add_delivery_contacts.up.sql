@@ -0,0 +1,14 @@
+CREATE TABLE delivery_contacts (
+  delivery_id bigint NOT NULL,
+  stop_type varchar NOT NULL,
+  name varchar NOT NULL DEFAULT (''),
+  phone_number varchar NOT NULL DEFAULT (''),
+  entrance varchar NOT NULL DEFAULT (''),
+  floor varchar NOT NULL DEFAULT (''),
+  intercom varchar NOT NULL DEFAULT (''),
+  notes varchar NOT NULL DEFAULT (''),
+  PRIMARY KEY (delivery_id, stop_type),
+  CHECK (stop_type IN ('pickup', 'dropoff'))
+);
+
+ALTER TABLE delivery_contacts ADD FOREIGN KEY (delivery_id) REFERENCES deliveries (id) ON DELETE CASCADE;
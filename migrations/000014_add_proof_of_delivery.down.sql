@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS delivery_events;
+
+DROP TABLE IF EXISTS delivery_proofs;
@@ -0,0 +1,34 @@
+CREATE TABLE delivery_proofs (
+  delivery_id bigint PRIMARY KEY,
+  pin varchar NOT NULL,
+  pin_attempts int NOT NULL DEFAULT (0),
+  photo_key varchar,
+  signature_key varchar,
+  latitude float8,
+  longitude float8,
+  distance float8,
+  completed_at timestamptz
+);
+
+ALTER TABLE delivery_proofs ADD FOREIGN KEY (delivery_id) REFERENCES deliveries (id) ON DELETE CASCADE;
+
+-- Deliveries in progress get PIN to be completed with proof
+INSERT INTO delivery_proofs(delivery_id, pin)
+SELECT id, lpad(floor(random() * 10000)::int::text, 4, '0')
+FROM deliveries
+WHERE status_id IN (1, 2);
+
+CREATE TABLE delivery_events (
+  id bigserial PRIMARY KEY,
+  delivery_id bigint NOT NULL,
+  type varchar NOT NULL,
+  actor_id bigint,
+  payload jsonb NOT NULL DEFAULT ('{}'),
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE delivery_events ADD FOREIGN KEY (delivery_id) REFERENCES deliveries (id) ON DELETE CASCADE;
+
+ALTER TABLE delivery_events ADD FOREIGN KEY (actor_id) REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX ON delivery_events (delivery_id, created_at);
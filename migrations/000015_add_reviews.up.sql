@@ -0,0 +1,23 @@
+CREATE TABLE reviews (
+  id bigserial PRIMARY KEY,
+  delivery_id bigint NOT NULL,
+  author_id bigint NOT NULL,
+  target_id bigint NOT NULL,
+  author_role varchar NOT NULL,
+  score smallint NOT NULL,
+  comment varchar NOT NULL DEFAULT (''),
+  tags varchar[] NOT NULL DEFAULT ('{}'),
+  is_comment_hidden bool NOT NULL DEFAULT (FALSE),
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE (delivery_id, author_id),
+  CHECK (score BETWEEN 1 AND 5),
+  CHECK (author_role IN ('client', 'courier'))
+);
+
+ALTER TABLE reviews ADD FOREIGN KEY (delivery_id) REFERENCES deliveries (id) ON DELETE CASCADE;
+
+ALTER TABLE reviews ADD FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE reviews ADD FOREIGN KEY (target_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX ON reviews (target_id, created_at);
@@ -0,0 +1,14 @@
+CREATE TABLE chat_messages (
+  id bigserial PRIMARY KEY,
+  delivery_id bigint NOT NULL,
+  sender_id bigint NOT NULL,
+  text varchar NOT NULL,
+  read_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE chat_messages ADD FOREIGN KEY (delivery_id) REFERENCES deliveries (id) ON DELETE CASCADE;
+
+ALTER TABLE chat_messages ADD FOREIGN KEY (sender_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX ON chat_messages (delivery_id, id);
@@ -0,0 +1,54 @@
+CREATE TABLE disputes (
+  id bigserial PRIMARY KEY,
+  delivery_id bigint NOT NULL,
+  opener_id bigint NOT NULL,
+  category varchar NOT NULL,
+  description varchar NOT NULL,
+  status varchar NOT NULL DEFAULT ('open'),
+  assignee_id bigint,
+  resolution varchar NOT NULL DEFAULT (''),
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  updated_at timestamptz NOT NULL DEFAULT (now()),
+  resolved_at timestamptz,
+  CHECK (category IN ('damaged', 'lost', 'no_show', 'late', 'other')),
+  CHECK (status IN ('open', 'in_progress', 'resolved', 'rejected'))
+);
+
+ALTER TABLE disputes ADD FOREIGN KEY (delivery_id) REFERENCES deliveries (id) ON DELETE CASCADE;
+
+ALTER TABLE disputes ADD FOREIGN KEY (opener_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE disputes ADD FOREIGN KEY (assignee_id) REFERENCES users (id) ON DELETE SET NULL;
+
+-- Each participant may have only one unresolved dispute per delivery
+CREATE UNIQUE INDEX ON disputes (delivery_id, opener_id) WHERE status IN ('open', 'in_progress');
+
+CREATE INDEX ON disputes (status, created_at);
+
+CREATE TABLE dispute_attachments (
+  id bigserial PRIMARY KEY,
+  dispute_id bigint NOT NULL,
+  file_key varchar NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE dispute_attachments ADD FOREIGN KEY (dispute_id) REFERENCES disputes (id) ON DELETE CASCADE;
+
+CREATE TABLE ledger_entries (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  delivery_id bigint NOT NULL,
+  dispute_id bigint,
+  kind varchar NOT NULL,
+  amount float8 NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  CHECK (kind IN ('refund', 'penalty'))
+);
+
+ALTER TABLE ledger_entries ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE ledger_entries ADD FOREIGN KEY (delivery_id) REFERENCES deliveries (id) ON DELETE CASCADE;
+
+ALTER TABLE ledger_entries ADD FOREIGN KEY (dispute_id) REFERENCES disputes (id) ON DELETE SET NULL;
+
+CREATE INDEX ON ledger_entries (user_id, created_at);
@@ -0,0 +1,31 @@
+CREATE TABLE notification_preferences (
+  user_id bigint PRIMARY KEY,
+  email_enabled bool NOT NULL DEFAULT (TRUE),
+  sms_enabled bool NOT NULL DEFAULT (FALSE),
+  push_enabled bool NOT NULL DEFAULT (TRUE),
+  push_token varchar NOT NULL DEFAULT ('')
+);
+
+ALTER TABLE notification_preferences ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE TABLE notifications (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  event varchar NOT NULL,
+  channel varchar NOT NULL,
+  recipient varchar NOT NULL,
+  subject varchar NOT NULL,
+  body varchar NOT NULL,
+  status varchar NOT NULL DEFAULT ('pending'),
+  attempts int NOT NULL DEFAULT (0),
+  last_error varchar NOT NULL DEFAULT (''),
+  next_attempt_at timestamptz NOT NULL DEFAULT (now()),
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  sent_at timestamptz,
+  CHECK (channel IN ('email', 'sms', 'push')),
+  CHECK (status IN ('pending', 'sent', 'failed'))
+);
+
+ALTER TABLE notifications ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX ON notifications (next_attempt_at) WHERE status = 'pending';
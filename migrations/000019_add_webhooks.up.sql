@@ -0,0 +1,36 @@
+CREATE TABLE webhooks (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  url varchar NOT NULL,
+  secret varchar NOT NULL,
+  events varchar[] NOT NULL,
+  is_active bool NOT NULL DEFAULT (TRUE),
+  failure_count int NOT NULL DEFAULT (0),
+  disabled_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE webhooks ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX ON webhooks (user_id);
+
+CREATE TABLE webhook_deliveries (
+  id bigserial PRIMARY KEY,
+  webhook_id bigint NOT NULL,
+  event varchar NOT NULL,
+  payload jsonb NOT NULL,
+  status varchar NOT NULL DEFAULT ('pending'),
+  attempts int NOT NULL DEFAULT (0),
+  response_status int,
+  last_error varchar NOT NULL DEFAULT (''),
+  next_attempt_at timestamptz NOT NULL DEFAULT (now()),
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  delivered_at timestamptz,
+  CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+ALTER TABLE webhook_deliveries ADD FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE;
+
+CREATE INDEX ON webhook_deliveries (webhook_id, created_at);
+
+CREATE INDEX ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,13 @@
+CREATE TABLE outbox_events (
+  id bigserial PRIMARY KEY,
+  aggregate_type varchar NOT NULL,
+  aggregate_id bigint NOT NULL,
+  type varchar NOT NULL,
+  payload jsonb NOT NULL DEFAULT ('{}'),
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  published_at timestamptz
+);
+
+CREATE INDEX ON outbox_events (id) WHERE published_at IS NULL;
+
+CREATE INDEX ON outbox_events (published_at);
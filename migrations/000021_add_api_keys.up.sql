@@ -0,0 +1,16 @@
+CREATE TABLE api_keys (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  name varchar NOT NULL,
+  prefix varchar NOT NULL UNIQUE,
+  key_hash varchar NOT NULL,
+  scopes varchar[] NOT NULL,
+  rate_limit int NOT NULL,
+  last_used_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT (now()),
+  revoked_at timestamptz
+);
+
+ALTER TABLE api_keys ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX ON api_keys (user_id);
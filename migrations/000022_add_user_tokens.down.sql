@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_tokens;
+
+ALTER TABLE meta DROP COLUMN IF EXISTS email_verified;
@@ -0,0 +1,18 @@
+ALTER TABLE meta ADD COLUMN email_verified boolean NOT NULL DEFAULT false;
+
+-- Accounts created before verification was introduced are trusted
+UPDATE meta SET email_verified = true;
+
+CREATE TABLE user_tokens (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  purpose varchar NOT NULL,
+  token_hash varchar NOT NULL UNIQUE,
+  expires_at timestamptz NOT NULL,
+  used_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE user_tokens ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX ON user_tokens (user_id, purpose);
@@ -0,0 +1,3 @@
+-- Numbers stored before verification was introduced were not validated,
+-- so all users have to verify them
+ALTER TABLE meta ADD COLUMN phone_verified boolean NOT NULL DEFAULT false;
@@ -0,0 +1,20 @@
+CREATE TABLE two_factor (
+  user_id bigint PRIMARY KEY,
+  secret varchar NOT NULL,
+  last_step bigint, -- time step of the last accepted code, codes can't be replayed
+  enabled_at timestamptz, -- NULL until enrollment is confirmed
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE recovery_codes (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  code_hash varchar NOT NULL,
+  used_at timestamptz
+);
+
+ALTER TABLE two_factor ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE recovery_codes ADD FOREIGN KEY (user_id) REFERENCES two_factor (user_id) ON DELETE CASCADE;
+
+CREATE INDEX ON recovery_codes (user_id);
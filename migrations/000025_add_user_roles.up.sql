@@ -0,0 +1,22 @@
+CREATE TABLE user_roles (
+  user_id bigint NOT NULL,
+  role varchar NOT NULL,
+  granted_by bigint,
+  granted_at timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY (user_id, role)
+);
+
+ALTER TABLE user_roles ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE user_roles ADD FOREIGN KEY (granted_by) REFERENCES users (id) ON DELETE SET NULL;
+
+-- Roles of the existing users are derived from the flags, the flags are kept
+-- in sync with the roles: is_courier for courier and is_admin for any staff role
+INSERT INTO user_roles(user_id, role)
+SELECT user_id, 'client' FROM meta;
+
+INSERT INTO user_roles(user_id, role)
+SELECT user_id, 'courier' FROM meta WHERE is_courier = true;
+
+INSERT INTO user_roles(user_id, role)
+SELECT user_id, 'super_admin' FROM meta WHERE is_admin = true;
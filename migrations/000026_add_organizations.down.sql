@@ -0,0 +1,14 @@
+DELETE FROM saved_places WHERE organization_id IS NOT NULL;
+
+ALTER TABLE saved_places
+  DROP CONSTRAINT IF EXISTS saved_places_owner_check,
+  DROP COLUMN IF EXISTS organization_id,
+  ALTER COLUMN user_id SET NOT NULL;
+
+ALTER TABLE deliveries DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organization_invitations;
+
+DROP TABLE IF EXISTS organization_members;
+
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,59 @@
+CREATE TABLE organizations (
+  id bigserial PRIMARY KEY,
+  name varchar NOT NULL,
+  legal_name varchar NOT NULL DEFAULT (''),
+  tax_id varchar NOT NULL DEFAULT (''),
+  billing_email varchar NOT NULL DEFAULT (''),
+  billing_address varchar NOT NULL DEFAULT (''),
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE organization_members (
+  organization_id bigint NOT NULL,
+  user_id bigint NOT NULL,
+  role varchar NOT NULL,
+  joined_at timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE TABLE organization_invitations (
+  id bigserial PRIMARY KEY,
+  organization_id bigint NOT NULL,
+  email varchar NOT NULL,
+  role varchar NOT NULL,
+  token_hash varchar UNIQUE NOT NULL,
+  invited_by bigint,
+  expires_at timestamptz NOT NULL,
+  accepted_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE organization_members ADD FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE;
+
+ALTER TABLE organization_members ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE organization_invitations ADD FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE;
+
+ALTER TABLE organization_invitations ADD FOREIGN KEY (invited_by) REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX ON organization_members (user_id);
+
+CREATE INDEX ON organization_invitations (organization_id, email);
+
+-- Deliveries created on behalf of the organization are billed to it,
+-- client_id still keeps the member who requested the delivery
+ALTER TABLE deliveries ADD COLUMN organization_id bigint;
+
+ALTER TABLE deliveries ADD FOREIGN KEY (organization_id) REFERENCES organizations (id);
+
+CREATE INDEX ON deliveries (organization_id);
+
+-- Places of the organization's shared address book have no user
+ALTER TABLE saved_places
+  ALTER COLUMN user_id DROP NOT NULL,
+  ADD COLUMN organization_id bigint,
+  ADD CONSTRAINT saved_places_owner_check CHECK ((user_id IS NULL) <> (organization_id IS NULL));
+
+ALTER TABLE saved_places ADD FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE;
+
+CREATE INDEX ON saved_places (organization_id);
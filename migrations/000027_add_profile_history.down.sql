@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS user_changes;
+
+ALTER TABLE user_tokens DROP COLUMN IF EXISTS email;
+
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_key;
@@ -0,0 +1,20 @@
+ALTER TABLE users ADD COLUMN avatar_key varchar NOT NULL DEFAULT ('');
+
+-- New email of the email change token, it replaces the current one once confirmed
+ALTER TABLE user_tokens ADD COLUMN email varchar;
+
+CREATE TABLE user_changes (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  field varchar NOT NULL,
+  old_value varchar NOT NULL,
+  new_value varchar NOT NULL,
+  changed_by bigint,
+  changed_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE user_changes ADD FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE user_changes ADD FOREIGN KEY (changed_by) REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX ON user_changes (user_id, changed_at);
@@ -0,0 +1,5 @@
+-- Time the dispatcher started offering the delivery, dispatches left
+-- by a stopped instance are detected by it and released to the open marketplace
+ALTER TABLE deliveries ADD COLUMN dispatching_since timestamptz;
+
+UPDATE deliveries SET dispatching_since = now() WHERE is_dispatching = true;
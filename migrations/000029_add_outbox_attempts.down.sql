@@ -0,0 +1,6 @@
+ALTER TABLE outbox_events
+  DROP COLUMN IF EXISTS attempts,
+  DROP COLUMN IF EXISTS next_attempt_at,
+  DROP COLUMN IF EXISTS handled_groups,
+  DROP COLUMN IF EXISTS last_error,
+  DROP COLUMN IF EXISTS dead_at;
@@ -0,0 +1,11 @@
+-- Failed events are published again after a backoff and moved aside
+-- as dead after too many attempts. Groups of the bus that already handled
+-- the event are kept so that it's redelivered only to the failed ones
+ALTER TABLE outbox_events
+  ADD COLUMN attempts int NOT NULL DEFAULT 0,
+  ADD COLUMN next_attempt_at timestamptz,
+  ADD COLUMN handled_groups text[] NOT NULL DEFAULT '{}',
+  ADD COLUMN last_error text,
+  ADD COLUMN dead_at timestamptz;
+
+CREATE INDEX ON outbox_events (aggregate_type, aggregate_id, id) WHERE published_at IS NULL AND dead_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE api_keys
+  DROP COLUMN IF EXISTS organization_id,
+  ADD COLUMN rate_limit int NOT NULL DEFAULT 60;
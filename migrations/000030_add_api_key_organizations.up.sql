@@ -0,0 +1,9 @@
+-- Keys of the organization create deliveries billed to it. Rate limit
+-- of the keys is defined by the server and is not stored anymore
+ALTER TABLE api_keys
+  ADD COLUMN organization_id bigint,
+  DROP COLUMN rate_limit;
+
+ALTER TABLE api_keys ADD FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE;
+
+CREATE INDEX ON api_keys (organization_id);
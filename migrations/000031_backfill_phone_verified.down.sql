@@ -0,0 +1 @@
+-- Backfilled numbers can't be told apart from the verified ones, so they are kept
@@ -0,0 +1,45 @@
+-- Numbers of the users registered before verification was introduced
+-- are trusted if they are valid and used by no other account, they are
+-- normalized to E.164 the same way as the verified ones (phonehelper.NormalizeE164)
+WITH parsed AS (
+  SELECT
+    id,
+    left(btrim(phone_number), 1) = '+' AS international,
+    regexp_replace(phone_number, '[^0-9]', '', 'g') AS digits
+  FROM users
+  WHERE btrim(phone_number) ~ '^\+?[0-9 ().-]+$'
+), normalized AS (
+  SELECT
+    id,
+    '+' || CASE
+      WHEN international THEN digits
+      WHEN length(digits) = 11 AND left(digits, 1) = '8' THEN '7' || substr(digits, 2)
+      WHEN length(digits) = 10 THEN '7' || digits
+      ELSE digits
+    END AS phone
+  FROM parsed
+), valid AS (
+  SELECT id, phone
+  FROM normalized
+  WHERE phone ~ '^\+[1-9][0-9]{7,14}$'
+), trusted AS (
+  SELECT id, phone
+  FROM valid
+  WHERE phone IN (SELECT phone FROM valid GROUP BY phone HAVING count(*) = 1)
+), recorded AS (
+  -- Rewritten numbers are recorded so that the profiles' history keeps the old ones,
+  -- the statement's snapshot still has the numbers before the update below
+  INSERT INTO user_changes(user_id, field, old_value, new_value)
+  SELECT users.id, 'phone_number', users.phone_number, trusted.phone
+  FROM users INNER JOIN trusted ON users.id = trusted.id
+  WHERE users.phone_number <> trusted.phone
+), updated AS (
+  UPDATE users
+  SET phone_number = trusted.phone
+  FROM trusted
+  WHERE users.id = trusted.id
+  RETURNING users.id
+)
+UPDATE meta
+SET phone_verified = true
+WHERE user_id IN (SELECT id FROM updated);
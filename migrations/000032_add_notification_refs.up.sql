@@ -0,0 +1,14 @@
+-- Emails with one-time links keep only the reference to the token,
+-- the link is built when the email is sent so that the token is never stored
+ALTER TABLE notifications ADD COLUMN ref_id bigint;
+
+-- Links queued before carry raw tokens, they are erased and the tokens revoked
+UPDATE user_tokens
+SET used_at = now()
+WHERE used_at IS NULL AND purpose IN ('email_verification', 'password_reset');
+
+UPDATE notifications
+SET body = '',
+    status = CASE WHEN status = 'pending' THEN 'failed' ELSE status END,
+    last_error = CASE WHEN status = 'pending' THEN 'link is revoked' ELSE last_error END
+WHERE event IN ('email_verification', 'password_reset');
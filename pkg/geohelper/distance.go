@@ -0,0 +1,22 @@
+package geohelper
+
+import "math"
+
+// earthRadius is the mean radius of the Earth in meters
+const earthRadius = 6371000.
+
+// toRadians converts degrees to radians
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// Haversine returns the great-circle distance in meters
+// between two points set by latitude and longitude in degrees
+func Haversine(latFrom, lonFrom, latTo, lonTo float64) float64 {
+	dLat := toRadians(latTo - latFrom)
+	dLon := toRadians(lonTo - lonFrom)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(latFrom))*math.Cos(toRadians(latTo))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
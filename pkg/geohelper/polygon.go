@@ -0,0 +1,124 @@
+package geohelper
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Point represents a geographic point set by latitude and longitude in degrees
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Polygon is a list of closed rings, the first ring is
+// the outer boundary and the rest ones are holes
+type Polygon [][]Point
+
+// MultiPolygon is a list of polygons covering one area
+type MultiPolygon []Polygon
+
+// Contains reports whether the point lies inside the outer ring
+// of the polygon and outside of all its holes
+func (p Polygon) Contains(lat, lon float64) bool {
+	if len(p) == 0 || !ringContains(p[0], lat, lon) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether the point lies inside any of the polygons
+func (mp MultiPolygon) Contains(lat, lon float64) bool {
+	for _, p := range mp {
+		if p.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains checks the point against the ring by ray casting, coordinates are
+// treated as planar which is accurate enough for city-sized areas
+func ringContains(ring []Point, lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > lat) != (b.Lat > lat) &&
+			lon < (b.Lon-a.Lon)*(lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// ParseGeoJSON parses coordinates of GeoJSON Polygon or MultiPolygon geometry,
+// positions are expected in [longitude, latitude] order as the format requires
+func ParseGeoJSON(geometryType string, coordinates json.RawMessage) (MultiPolygon, error) {
+	switch geometryType {
+	case "Polygon":
+		var raw [][][]float64
+		if err := json.Unmarshal(coordinates, &raw); err != nil {
+			return nil, fmt.Errorf("invalid polygon coordinates")
+		}
+		p, err := parsePolygon(raw)
+		if err != nil {
+			return nil, err
+		}
+		return MultiPolygon{p}, nil
+	case "MultiPolygon":
+		var raw [][][][]float64
+		if err := json.Unmarshal(coordinates, &raw); err != nil {
+			return nil, fmt.Errorf("invalid multipolygon coordinates")
+		}
+		if len(raw) == 0 {
+			return nil, fmt.Errorf("multipolygon has no polygons")
+		}
+		mp := make(MultiPolygon, 0, len(raw))
+		for _, rawPolygon := range raw {
+			p, err := parsePolygon(rawPolygon)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, p)
+		}
+		return mp, nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", geometryType)
+	}
+}
+
+// parsePolygon converts GeoJSON rings to the polygon validating positions
+func parsePolygon(raw [][][]float64) (Polygon, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("polygon has no rings")
+	}
+
+	p := make(Polygon, 0, len(raw))
+	for _, rawRing := range raw {
+		if len(rawRing) < 4 {
+			return nil, fmt.Errorf("ring must have at least 4 positions")
+		}
+
+		ring := make([]Point, 0, len(rawRing))
+		for _, pos := range rawRing {
+			if len(pos) < 2 {
+				return nil, fmt.Errorf("position must have longitude and latitude")
+			}
+			if pos[0] < -180 || pos[0] > 180 || pos[1] < -90 || pos[1] > 90 {
+				return nil, fmt.Errorf("position is out of range")
+			}
+			ring = append(ring, Point{Lat: pos[1], Lon: pos[0]})
+		}
+
+		if ring[0] != ring[len(ring)-1] {
+			return nil, fmt.Errorf("ring must be closed")
+		}
+		p = append(p, ring)
+	}
+	return p, nil
+}
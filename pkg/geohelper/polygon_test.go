@@ -0,0 +1,86 @@
+package geohelper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiPolygon_Contains(t *testing.T) {
+	// Square 0..10 with a hole 4..6 and a separate square 20..22
+	coordinates := json.RawMessage(`[
+		[
+			[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+			[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+		],
+		[
+			[[20, 20], [22, 20], [22, 22], [20, 22], [20, 20]]
+		]
+	]`)
+	area, err := ParseGeoJSON("MultiPolygon", coordinates)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		lat  float64
+		lon  float64
+		want bool
+	}{
+		{name: "inside outer ring", lat: 2, lon: 2, want: true},
+		{name: "inside hole", lat: 5, lon: 5, want: false},
+		{name: "inside second polygon", lat: 21, lon: 21.5, want: true},
+		{name: "between polygons", lat: 15, lon: 15, want: false},
+		{name: "outside by longitude only", lat: 2, lon: -1, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, area.Contains(tt.lat, tt.lon))
+		})
+	}
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		geometryType string
+		coordinates  string
+		wantErr      bool
+	}{
+		{
+			name:         "valid polygon",
+			geometryType: "Polygon",
+			coordinates:  `[[[37.5, 55.7], [37.7, 55.7], [37.7, 55.8], [37.5, 55.7]]]`,
+		},
+		{
+			name:         "ring is not closed",
+			geometryType: "Polygon",
+			coordinates:  `[[[37.5, 55.7], [37.7, 55.7], [37.7, 55.8], [37.5, 55.8]]]`,
+			wantErr:      true,
+		},
+		{
+			name:         "too few positions",
+			geometryType: "Polygon",
+			coordinates:  `[[[37.5, 55.7], [37.7, 55.7], [37.5, 55.7]]]`,
+			wantErr:      true,
+		},
+		{
+			name:         "latitude out of range",
+			geometryType: "Polygon",
+			coordinates:  `[[[37.5, 95.7], [37.7, 55.7], [37.7, 55.8], [37.5, 95.7]]]`,
+			wantErr:      true,
+		},
+		{
+			name:         "unsupported type",
+			geometryType: "LineString",
+			coordinates:  `[[37.5, 55.7], [37.7, 55.7]]`,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseGeoJSON(tt.geometryType, json.RawMessage(tt.coordinates))
+			require.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package nethelper
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Shared address space of carrier-grade NAT, it is not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP reports whether ip is a global unicast address that is not
+// private, loopback, link-local or shared, so it's safe to connect to on behalf of users
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return false
+	}
+	return true
+}
+
+// PublicOnlyControl is net.Dialer Control function that refuses connections
+// to non-public addresses. It is called with the address already resolved,
+// so hosts resolving to internal addresses after validation are rejected as well
+func PublicOnlyControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublicIP(net.ParseIP(host)) {
+		return fmt.Errorf("connection to non-public address %s is not allowed", host)
+	}
+	return nil
+}
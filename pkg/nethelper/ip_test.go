@@ -0,0 +1,42 @@
+package nethelper
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{name: "public ipv4", ip: "93.184.216.34", want: true},
+		{name: "public ipv6", ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{name: "loopback", ip: "127.0.0.1"},
+		{name: "ipv6 loopback", ip: "::1"},
+		{name: "private", ip: "10.0.0.5"},
+		{name: "private 172", ip: "172.16.3.4"},
+		{name: "private 192", ip: "192.168.1.1"},
+		{name: "ipv6 unique local", ip: "fd00::1"},
+		{name: "link-local metadata", ip: "169.254.169.254"},
+		{name: "ipv6 link-local", ip: "fe80::1"},
+		{name: "shared address space", ip: "100.64.0.1"},
+		{name: "unspecified", ip: "0.0.0.0"},
+		{name: "multicast", ip: "224.0.0.1"},
+		{name: "ipv4-mapped loopback", ip: "::ffff:127.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsPublicIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestPublicOnlyControl(t *testing.T) {
+	require.NoError(t, PublicOnlyControl("tcp4", "93.184.216.34:443", nil))
+	require.Error(t, PublicOnlyControl("tcp4", "127.0.0.1:443", nil))
+	require.Error(t, PublicOnlyControl("tcp6", "[::1]:443", nil))
+}
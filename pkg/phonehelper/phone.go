@@ -0,0 +1,50 @@
+package phonehelper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Country calling code of the numbers written without it,
+// national numbers start with the trunk prefix 8 instead
+const (
+	defaultCountryCode = "7"
+	trunkPrefix        = '8'
+)
+
+// NormalizeE164 converts phone number written with spaces, dashes, dots
+// or parentheses to the E.164 format, national numbers are treated as numbers
+// of the default country. Error is returned if the number is not valid
+func NormalizeE164(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	international := strings.HasPrefix(raw, "+")
+	if international {
+		raw = raw[1:]
+	}
+
+	digits := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		switch ch := raw[i]; {
+		case ch >= '0' && ch <= '9':
+			digits = append(digits, ch)
+		case ch == ' ' || ch == '-' || ch == '.' || ch == '(' || ch == ')':
+		default:
+			return "", fmt.Errorf("invalid phone number")
+		}
+	}
+
+	if !international {
+		switch {
+		case len(digits) == 11 && digits[0] == trunkPrefix:
+			digits = append([]byte(defaultCountryCode), digits[1:]...)
+		case len(digits) == 10:
+			digits = append([]byte(defaultCountryCode), digits...)
+		}
+	}
+
+	// E.164 numbers have up to 15 digits and country code never starts with 0
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", fmt.Errorf("invalid phone number")
+	}
+	return "+" + string(digits), nil
+}
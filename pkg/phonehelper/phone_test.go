@@ -0,0 +1,37 @@
+package phonehelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeE164(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{name: "national with trunk prefix", raw: "89157650030", want: "+79157650030"},
+		{name: "national without trunk prefix", raw: "(915) 765-00-30", want: "+79157650030"},
+		{name: "international formatted", raw: "+7 915 765-00-30", want: "+79157650030"},
+		{name: "international of another country", raw: "+44 20 7946 0958", want: "+442079460958"},
+		{name: "letters", raw: "+7 915 CALL-ME", wantErr: true},
+		{name: "too short", raw: "+7 915", wantErr: true},
+		{name: "too long", raw: "+7 915 765 00 30 12345", wantErr: true},
+		{name: "country code starts with zero", raw: "+0 915 765 00 30", wantErr: true},
+		{name: "empty", raw: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeE164(tt.raw)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
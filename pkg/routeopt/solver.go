@@ -0,0 +1,166 @@
+package routeopt
+
+import (
+	"math"
+	"time"
+)
+
+// Penalty added to the route cost for each second of being late, in m
+var latenessPenalty = 1000.
+
+// Window represents time window of the stop in seconds since route start,
+// stop can't be served earlier than Earliest and should be served until Latest
+type Window struct {
+	Earliest float64
+	Latest   float64
+}
+
+// NoWindow is a time window that doesn't restrict the stop
+var NoWindow = Window{Earliest: 0, Latest: math.Inf(1)}
+
+// Problem represents pickup and delivery routing problem for one vehicle.
+// Node 0 is the start location, nodes 1..n are stops to visit
+type Problem struct {
+	Distances   [][]float64 // in m
+	Durations   [][]float64 // in s
+	Windows     []Window
+	Precedence  map[int]int // dropoff node -> pickup node that must be visited before
+	ServiceTime float64     // time spent at each stop, in s
+}
+
+// Solution represents the order of visiting stops with route parameters
+type Solution struct {
+	Order    []int     // stop nodes in order of visiting
+	Arrivals []float64 // arrival time to each stop in seconds since route start
+	Distance float64   // in m
+	Lateness float64   // total time of being late in s
+}
+
+// Solve builds the route greedily by visiting the nearest available stop and
+// improves it by relocating stops while the time budget is not exceeded
+func Solve(p *Problem, budget time.Duration) *Solution {
+	deadline := time.Now().Add(budget)
+
+	order := p.greedy()
+	best := p.evaluate(order)
+	bestCost := best.cost()
+
+	improved := true
+	for improved && time.Now().Before(deadline) {
+		improved = false
+		for i := 0; i < len(order) && !improved; i++ {
+			for j := 0; j < len(order) && !improved; j++ {
+				if i == j || time.Now().After(deadline) {
+					continue
+				}
+				candidate := relocate(order, i, j)
+				if !p.isValid(candidate) {
+					continue
+				}
+				s := p.evaluate(candidate)
+				if s.cost() < bestCost-1e-9 {
+					order, best, bestCost = candidate, s, s.cost()
+					improved = true
+				}
+			}
+		}
+	}
+	return best
+}
+
+// greedy orders stops by visiting the nearest stop which precedence allows
+func (p *Problem) greedy() []int {
+	n := len(p.Distances)
+	visited := make([]bool, n)
+	visited[0] = true
+
+	order := make([]int, 0, n-1)
+	cur := 0
+	for len(order) < n-1 {
+		next := -1
+		for node := 1; node < n; node++ {
+			if visited[node] {
+				continue
+			}
+			if pickup, ok := p.Precedence[node]; ok && !visited[pickup] {
+				continue
+			}
+			if next == -1 || p.Distances[cur][node] < p.Distances[cur][next] {
+				next = node
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+		cur = next
+	}
+	return order
+}
+
+// isValid checks that every dropoff goes after its pickup
+func (p *Problem) isValid(order []int) bool {
+	pos := make(map[int]int, len(order))
+	for i, node := range order {
+		pos[node] = i
+	}
+	for dropoff, pickup := range p.Precedence {
+		pickupPos, ok := pos[pickup]
+		if !ok {
+			continue
+		}
+		if pickupPos > pos[dropoff] {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluate calculates distance, arrivals and lateness of the route
+func (p *Problem) evaluate(order []int) *Solution {
+	s := &Solution{
+		Order:    order,
+		Arrivals: make([]float64, len(order)),
+	}
+
+	var t float64
+	prev := 0
+	for i, node := range order {
+		s.Distance += p.Distances[prev][node]
+		t += p.Durations[prev][node]
+
+		w := NoWindow
+		if node < len(p.Windows) {
+			w = p.Windows[node]
+		}
+		if t < w.Earliest {
+			t = w.Earliest
+		}
+		if t > w.Latest {
+			s.Lateness += t - w.Latest
+		}
+		s.Arrivals[i] = t
+
+		t += p.ServiceTime
+		prev = node
+	}
+	return s
+}
+
+// cost returns the value minimized by the solver
+func (s *Solution) cost() float64 {
+	return s.Distance + latenessPenalty*s.Lateness
+}
+
+// relocate returns a copy of the order with the stop
+// at position i moved to position j
+func relocate(order []int, i, j int) []int {
+	result := make([]int, 0, len(order))
+	node := order[i]
+	for k, v := range order {
+		if k == i {
+			continue
+		}
+		result = append(result, v)
+	}
+	result = append(result[:j], append([]int{node}, result[j:]...)...)
+	return result
+}
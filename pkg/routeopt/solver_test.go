@@ -0,0 +1,72 @@
+package routeopt
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lineProblem builds problem with all nodes placed on a line at given coordinates
+func lineProblem(coords []float64) *Problem {
+	n := len(coords)
+	p := &Problem{
+		Distances:  make([][]float64, n),
+		Durations:  make([][]float64, n),
+		Windows:    make([]Window, n),
+		Precedence: map[int]int{},
+	}
+	for i := range coords {
+		p.Distances[i] = make([]float64, n)
+		p.Durations[i] = make([]float64, n)
+		p.Windows[i] = NoWindow
+		for j := range coords {
+			p.Distances[i][j] = math.Abs(coords[i] - coords[j])
+			p.Durations[i][j] = p.Distances[i][j] / 10 // 10 m/s
+		}
+	}
+	return p
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		problem   func() *Problem
+		wantOrder []int
+	}{
+		{
+			name: "nearest stops first",
+			problem: func() *Problem {
+				return lineProblem([]float64{0, 300, 100, 200})
+			},
+			wantOrder: []int{2, 3, 1},
+		},
+		{
+			name: "dropoff goes after its pickup",
+			problem: func() *Problem {
+				p := lineProblem([]float64{0, 500, 100})
+				// node 2 is dropoff of pickup at node 1
+				p.Precedence[2] = 1
+				return p
+			},
+			wantOrder: []int{1, 2},
+		},
+		{
+			name: "time window changes the order",
+			problem: func() *Problem {
+				p := lineProblem([]float64{0, -100, 1000})
+				// node 2 must be served before the route via node 1 reaches it
+				p.Windows[2] = Window{Earliest: 0, Latest: 105}
+				return p
+			},
+			wantOrder: []int{2, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Solve(tt.problem(), 100*time.Millisecond)
+			require.Equal(t, tt.wantOrder, s.Order)
+		})
+	}
+}